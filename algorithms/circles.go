@@ -0,0 +1,31 @@
+package algorithms
+
+import (
+	"image/color"
+)
+
+
+func BresenhamCircle(canvas [][]color.Color, centerX, centerY, radius int, c color.Color) {
+	x := 0
+	y := radius
+	d := 3 - 2*radius
+
+	for y >= x {
+		SetPixel(canvas, centerX+x, centerY+y, c)
+		SetPixel(canvas, centerX-x, centerY+y, c)
+		SetPixel(canvas, centerX+x, centerY-y, c)
+		SetPixel(canvas, centerX-x, centerY-y, c)
+		SetPixel(canvas, centerX+y, centerY+x, c)
+		SetPixel(canvas, centerX-y, centerY+x, c)
+		SetPixel(canvas, centerX+y, centerY-x, c)
+		SetPixel(canvas, centerX-y, centerY-x, c)
+
+		if d < 0 {
+			d += 4*x + 6
+		} else {
+			d += 4*(x-y) + 10
+			y--
+		}
+		x++
+	}
+}
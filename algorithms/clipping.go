@@ -1,6 +1,7 @@
 package algorithms
 
 import (
+	"fmt"
 	"math"
 )
 
@@ -9,12 +10,24 @@ import (
 
 
 func SutherlandHodgman(subject, clip []Point) []Point {
+	return sutherlandHodgman(subject, clip, nil)
+}
+
+
+func sutherlandHodgman(subject, clip []Point, trace *Trace) []Point {
 	if len(subject) < 3 || len(clip) < 3 {
 		return nil 
 	}
 
 	
 	output := subject
+	if trace != nil {
+		trace.add(TraceStep{
+			Description: "Start with the unclipped subject polygon",
+			Vars:        []TraceVar{traceVar("subject vertices", len(subject)), traceVar("clip edges", len(clip))},
+			Polygon:     append([]Point(nil), subject...),
+		})
+	}
 
 	
 	for i := 0; i < len(clip); i++ {
@@ -43,6 +56,19 @@ func SutherlandHodgman(subject, clip []Point) []Point {
 			}
 				s = e
 		}
+
+		if trace != nil {
+			trace.add(TraceStep{
+				Description: fmt.Sprintf("Clip against edge %d", i),
+				Vars: []TraceVar{
+					traceVar("edge", fmt.Sprintf("(%.1f, %.1f) -> (%.1f, %.1f)", clipEdgeStart.X, clipEdgeStart.Y, clipEdgeEnd.X, clipEdgeEnd.Y)),
+					traceVar("input vertices", len(input)),
+					traceVar("output vertices", len(output)),
+				},
+				Polygon: append([]Point(nil), output...),
+				Edge:    []Point{clipEdgeStart, clipEdgeEnd},
+			})
+		}
 	}
 
 	return output
@@ -58,10 +84,10 @@ func isInside(clipEdgeStart, clipEdgeEnd, point Point) bool {
 
 func computeIntersection(s, e, clipEdgeStart, clipEdgeEnd Point) Point {
 	
-	x1, y1 := float64(s.X), float64(s.Y)
-	x2, y2 := float64(e.X), float64(e.Y)
-	x3, y3 := float64(clipEdgeStart.X), float64(clipEdgeStart.Y)
-	x4, y4 := float64(clipEdgeEnd.X), float64(clipEdgeEnd.Y)
+	x1, y1 := s.X, s.Y
+	x2, y2 := e.X, e.Y
+	x3, y3 := clipEdgeStart.X, clipEdgeStart.Y
+	x4, y4 := clipEdgeEnd.X, clipEdgeEnd.Y
 
 	
 	
@@ -72,8 +98,8 @@ func computeIntersection(s, e, clipEdgeStart, clipEdgeEnd Point) Point {
 	
 	if math.Abs(denom) < 0.0001 {
 		return Point{
-			X: int((x1 + x2) / 2),
-			Y: int((y1 + y2) / 2),
+			X: (x1 + x2) / 2,
+			Y: (y1 + y2) / 2,
 		}
 	}
 	
@@ -81,12 +107,9 @@ func computeIntersection(s, e, clipEdgeStart, clipEdgeEnd Point) Point {
 	ua := ((x4-x3)*(y1-y3) - (y4-y3)*(x1-x3)) / denom
 	
 	
-	intersectX := x1 + ua*(x2-x1)
-	intersectY := y1 + ua*(y2-y1)
-	
 	return Point{
-		X: int(math.Round(intersectX)),
-		Y: int(math.Round(intersectY)),
+		X: x1 + ua*(x2-x1),
+		Y: y1 + ua*(y2-y1),
 	}
 }
 
@@ -0,0 +1,85 @@
+package algorithms
+
+import (
+	"image/color"
+	"math"
+)
+
+
+type BlendMode string
+
+const (
+	BlendNormal   BlendMode = "normal"
+	BlendMultiply BlendMode = "multiply"
+	BlendScreen   BlendMode = "screen"
+	BlendOverlay  BlendMode = "overlay"
+	BlendDarken   BlendMode = "darken"
+	BlendLighten  BlendMode = "lighten"
+)
+
+
+var BlendModes = []BlendMode{BlendNormal, BlendMultiply, BlendScreen, BlendOverlay, BlendDarken, BlendLighten}
+
+
+func CompositePixel(dst, src color.Color, opacity float64, mode BlendMode) color.RGBA {
+	dr, dg, db, da := dst.RGBA()
+	sr, sg, sb, sa := src.RGBA()
+
+	alpha := float64(sa) / 0xffff * opacity
+	if alpha <= 0 {
+		return color.RGBA{uint8(dr >> 8), uint8(dg >> 8), uint8(db >> 8), uint8(da >> 8)}
+	}
+
+	unpremultiply := func(c, a uint32) float64 {
+		if a == 0 {
+			return 0
+		}
+		return float64(c) / float64(a)
+	}
+
+	d := [3]float64{unpremultiply(dr, da), unpremultiply(dg, da), unpremultiply(db, da)}
+	s := [3]float64{unpremultiply(sr, sa), unpremultiply(sg, sa), unpremultiply(sb, sa)}
+	dstAlpha := float64(da) / 0xffff
+
+	var out [3]float64
+	for i := range out {
+		blended := blendChannel(d[i], s[i], mode)
+		if dstAlpha < 1 {
+			blended = s[i]*(1-dstAlpha) + blended*dstAlpha
+		}
+		out[i] = blended*alpha + d[i]*dstAlpha*(1-alpha)
+	}
+	outAlpha := alpha + dstAlpha*(1-alpha)
+
+	return color.RGBA{
+		R: toByte(out[0]),
+		G: toByte(out[1]),
+		B: toByte(out[2]),
+		A: toByte(outAlpha),
+	}
+}
+
+
+func blendChannel(d, s float64, mode BlendMode) float64 {
+	switch mode {
+	case BlendMultiply:
+		return s * d
+	case BlendScreen:
+		return 1 - (1-s)*(1-d)
+	case BlendOverlay:
+		if d < 0.5 {
+			return 2 * s * d
+		}
+		return 1 - 2*(1-s)*(1-d)
+	case BlendDarken:
+		return math.Min(s, d)
+	case BlendLighten:
+		return math.Max(s, d)
+	}
+	return s
+}
+
+
+func toByte(v float64) uint8 {
+	return uint8(math.Round(math.Max(0, math.Min(1, v)) * 255))
+}
@@ -7,6 +7,11 @@ import (
 
 
 type Point struct {
+	X, Y float64
+}
+
+
+type Pixel struct {
 	X, Y int
 }
 
@@ -58,6 +63,13 @@ func SetPixelWithAlpha(canvas [][]color.Color, x, y int, c color.Color, alpha fl
 
 
 func MidpointLine(canvas [][]color.Color, x0, y0, x1, y1 int, c color.Color) {
+	midpointPoints(x0, y0, x1, y1, func(x, y int) {
+		SetPixel(canvas, x, y, c)
+	}, nil)
+}
+
+
+func midpointPoints(x0, y0, x1, y1 int, plot func(x, y int), trace *Trace) {
 	dx := x1 - x0
 	dy := y1 - y0
 	
@@ -68,7 +80,14 @@ func MidpointLine(canvas [][]color.Color, x0, y0, x1, y1 int, c color.Color) {
 			startY, endY = y1, y0
 		}
 		for y := startY; y <= endY; y++ {
-			SetPixel(canvas, x0, y, c)
+			plot(x0, y)
+			if trace != nil {
+				trace.add(TraceStep{
+					Description: "Vertical line: plot without a decision variable",
+					Vars:        []TraceVar{traceVar("x", x0), traceVar("y", y)},
+					Pixels:      []Pixel{{X: x0, Y: y}},
+				})
+			}
 		}
 		return
 	}
@@ -80,7 +99,14 @@ func MidpointLine(canvas [][]color.Color, x0, y0, x1, y1 int, c color.Color) {
 			startX, endX = x1, x0
 		}
 		for x := startX; x <= endX; x++ {
-			SetPixel(canvas, x, y0, c)
+			plot(x, y0)
+			if trace != nil {
+				trace.add(TraceStep{
+					Description: "Horizontal line: plot without a decision variable",
+					Vars:        []TraceVar{traceVar("x", x), traceVar("y", y0)},
+					Pixels:      []Pixel{{X: x, Y: y0}},
+				})
+			}
 		}
 		return
 	}
@@ -114,18 +140,34 @@ func MidpointLine(canvas [][]color.Color, x0, y0, x1, y1 int, c color.Color) {
 	
 	
 	for x := x0; x <= x1; x++ {
+		px, py := x, y
 		if steep {
-			SetPixel(canvas, y, x, c)
-		} else {
-			SetPixel(canvas, x, y, c)
+			px, py = y, x
 		}
+		plot(px, py)
 
+		decision := d
 		if d > 0 {
 			y += yStep
 			d += 2 * (dy - dx)
 		} else {
 			d += 2 * dy
 		}
+
+		if trace != nil {
+			choice := "d <= 0: keep minor coordinate, d += 2*dy"
+			if decision > 0 {
+				choice = "d > 0: step minor coordinate, d += 2*(dy-dx)"
+			}
+			trace.add(TraceStep{
+				Description: choice,
+				Vars: []TraceVar{
+					traceVar("x", px), traceVar("y", py), traceVar("d", decision),
+					traceVar("next d", d), traceVar("steep", steep),
+				},
+				Pixels: []Pixel{{X: px, Y: py}},
+			})
+		}
 	}
 }
 
@@ -163,7 +205,7 @@ func ThickLine(canvas [][]color.Color, x0, y0, x1, y1 int, c color.Color, thickn
 	radius := thickness / 2
 	
 	
-	linePixels := make([]Point, 0)
+	linePixels := make([]Pixel, 0)
 	
 	
 	dx := x1 - x0
@@ -176,7 +218,7 @@ func ThickLine(canvas [][]color.Color, x0, y0, x1, y1 int, c color.Color, thickn
 			startY, endY = y1, y0
 		}
 		for y := startY; y <= endY; y++ {
-			linePixels = append(linePixels, Point{X: x0, Y: y})
+			linePixels = append(linePixels, Pixel{X: x0, Y: y})
 		}
 	} else if dy == 0 { 
 		startX, endX := x0, x1
@@ -184,7 +226,7 @@ func ThickLine(canvas [][]color.Color, x0, y0, x1, y1 int, c color.Color, thickn
 			startX, endX = x1, x0
 		}
 		for x := startX; x <= endX; x++ {
-			linePixels = append(linePixels, Point{X: x, Y: y0})
+			linePixels = append(linePixels, Pixel{X: x, Y: y0})
 		}
 	} else {
 		steep := math.Abs(float64(dy)) > math.Abs(float64(dx))
@@ -212,9 +254,9 @@ func ThickLine(canvas [][]color.Color, x0, y0, x1, y1 int, c color.Color, thickn
 
 		for x := x0; x <= x1; x++ {
 			if steep {
-				linePixels = append(linePixels, Point{X: y, Y: x})
+				linePixels = append(linePixels, Pixel{X: y, Y: x})
 			} else {
-				linePixels = append(linePixels, Point{X: x, Y: y})
+				linePixels = append(linePixels, Pixel{X: x, Y: y})
 			}
 	
 			if d > 0 {
@@ -243,20 +285,31 @@ func ThickLine(canvas [][]color.Color, x0, y0, x1, y1 int, c color.Color, thickn
 
 
 func MidpointCircle(canvas [][]color.Color, centerX, centerY, radius int, c color.Color) {
+	midpointCircle(canvas, centerX, centerY, radius, c, nil)
+}
+
+
+func midpointCircle(canvas [][]color.Color, centerX, centerY, radius int, c color.Color, trace *Trace) {
 	x := radius
 	y := 0
 	err := 0
 	
 	for x >= y {
-		SetPixel(canvas, centerX+x, centerY+y, c)
-		SetPixel(canvas, centerX+y, centerY+x, c)
-		SetPixel(canvas, centerX-y, centerY+x, c)
-		SetPixel(canvas, centerX-x, centerY+y, c)
-		SetPixel(canvas, centerX-x, centerY-y, c)
-		SetPixel(canvas, centerX-y, centerY-x, c)
-		SetPixel(canvas, centerX+y, centerY-x, c)
-		SetPixel(canvas, centerX+x, centerY-y, c)
+		octants := [8]Pixel{
+			{X: centerX + x, Y: centerY + y},
+			{X: centerX + y, Y: centerY + x},
+			{X: centerX - y, Y: centerY + x},
+			{X: centerX - x, Y: centerY + y},
+			{X: centerX - x, Y: centerY - y},
+			{X: centerX - y, Y: centerY - x},
+			{X: centerX + y, Y: centerY - x},
+			{X: centerX + x, Y: centerY - y},
+		}
+		for _, p := range octants {
+			SetPixel(canvas, p.X, p.Y, c)
+		}
 
+		plottedX, plottedY, decision := x, y, err
 		if err <= 0 {
 			y++
 			err += 2*y + 1
@@ -265,14 +318,32 @@ func MidpointCircle(canvas [][]color.Color, centerX, centerY, radius int, c colo
 			x--
 			err -= 2*x + 1
 		}
+
+		if trace != nil {
+			trace.add(TraceStep{
+				Description: "Plot the eight symmetric points, then update the error term",
+				Vars: []TraceVar{
+					traceVar("x", plottedX), traceVar("y", plottedY),
+					traceVar("err", decision), traceVar("next err", err),
+				},
+				Pixels: append([]Pixel(nil), octants[:]...),
+			})
+		}
 	}
 }
 
 
 
-func XiaolinWuLine(canvas [][]color.Color, x0, y0, x1, y1 int, c color.Color) {
+func XiaolinWuLine(canvas [][]color.Color, x0, y0, x1, y1 float64, c color.Color) {
+	xiaolinWuPoints(x0, y0, x1, y1, func(x, y int, alpha float64) {
+		SetPixelWithAlpha(canvas, x, y, c, alpha)
+	})
+}
+
+
+func xiaolinWuPoints(x0, y0, x1, y1 float64, put func(x, y int, alpha float64)) {
 	
-	steep := math.Abs(float64(y1-y0)) > math.Abs(float64(x1-x0))
+	steep := math.Abs(y1-y0) > math.Abs(x1-x0)
 	if steep {
 		x0, y0 = y0, x0
 		x1, y1 = y1, x1
@@ -282,107 +353,88 @@ func XiaolinWuLine(canvas [][]color.Color, x0, y0, x1, y1 int, c color.Color) {
 		y0, y1 = y1, y0
 	}
 	
-	dx := float64(x1 - x0)
-	dy := float64(y1 - y0)
-	gradient := float64(1.0)
+	plot := func(x, y int, alpha float64) {
+		if steep {
+			put(y, x, alpha)
+		} else {
+			put(x, y, alpha)
+		}
+	}
+	
+	dx := x1 - x0
+	dy := y1 - y0
+	gradient := 1.0
 	if dx != 0 {
 		gradient = dy / dx
 	}
 	
 	
-	xend := float64(x0)
-	yend := float64(y0) + gradient*(xend-float64(x0))
-	xgap := 1.0 - math.Mod(float64(x0)+0.5, 1.0)
-	xpxl1 := x0
-	ypxl1 := int(yend)
-	
-	if steep {
-		SetPixelWithAlpha(canvas, ypxl1, xpxl1, c, (1-math.Mod(yend, 1.0))*xgap)
-		SetPixelWithAlpha(canvas, ypxl1+1, xpxl1, c, math.Mod(yend, 1.0)*xgap)
-	} else {
-		SetPixelWithAlpha(canvas, xpxl1, ypxl1, c, (1-math.Mod(yend, 1.0))*xgap)
-		SetPixelWithAlpha(canvas, xpxl1, ypxl1+1, c, math.Mod(yend, 1.0)*xgap)
-	}
+	xend := math.Round(x0)
+	yend := y0 + gradient*(xend-x0)
+	xgap := rfpart(x0 + 0.5)
+	xpxl1 := int(xend)
+	ypxl1 := int(math.Floor(yend))
+	plot(xpxl1, ypxl1, rfpart(yend)*xgap)
+	plot(xpxl1, ypxl1+1, fpart(yend)*xgap)
 	
 	
 	intery := yend + gradient
 	
 	
-	xend = float64(x1)
-	yend = float64(y1) + gradient*(xend-float64(x1))
-	xgap = math.Mod(float64(x1)+0.5, 1.0)
-	xpxl2 := x1
-	ypxl2 := int(yend)
-	
-	if steep {
-		SetPixelWithAlpha(canvas, ypxl2, xpxl2, c, (1-math.Mod(yend, 1.0))*xgap)
-		SetPixelWithAlpha(canvas, ypxl2+1, xpxl2, c, math.Mod(yend, 1.0)*xgap)
-	} else {
-		SetPixelWithAlpha(canvas, xpxl2, ypxl2, c, (1-math.Mod(yend, 1.0))*xgap)
-		SetPixelWithAlpha(canvas, xpxl2, ypxl2+1, c, math.Mod(yend, 1.0)*xgap)
-	}
+	xend = math.Round(x1)
+	yend = y1 + gradient*(xend-x1)
+	xgap = fpart(x1 + 0.5)
+	xpxl2 := int(xend)
+	ypxl2 := int(math.Floor(yend))
+	plot(xpxl2, ypxl2, rfpart(yend)*xgap)
+	plot(xpxl2, ypxl2+1, fpart(yend)*xgap)
 	
 	
-	if steep {
-		for x := xpxl1 + 1; x < xpxl2; x++ {
-			SetPixelWithAlpha(canvas, int(intery), x, c, 1-math.Mod(intery, 1.0))
-			SetPixelWithAlpha(canvas, int(intery)+1, x, c, math.Mod(intery, 1.0))
-			intery += gradient
-		}
-	} else {
-		for x := xpxl1 + 1; x < xpxl2; x++ {
-			SetPixelWithAlpha(canvas, x, int(intery), c, 1-math.Mod(intery, 1.0))
-			SetPixelWithAlpha(canvas, x, int(intery)+1, c, math.Mod(intery, 1.0))
-			intery += gradient
-		}
+	for x := xpxl1 + 1; x < xpxl2; x++ {
+		plot(x, int(math.Floor(intery)), rfpart(intery))
+		plot(x, int(math.Floor(intery))+1, fpart(intery))
+		intery += gradient
 	}
 }
 
 
-func XiaolinWuCircle(canvas [][]color.Color, centerX, centerY, radius int, c color.Color) {
-	
-	x := radius
-	y := 0
-	err := 0
-	
-	
-	drawPixel := func(xi, yi int, alpha float64) {
-		SetPixelWithAlpha(canvas, centerX+xi, centerY+yi, c, alpha)
-		SetPixelWithAlpha(canvas, centerX+yi, centerY+xi, c, alpha)
-		SetPixelWithAlpha(canvas, centerX-yi, centerY+xi, c, alpha)
-		SetPixelWithAlpha(canvas, centerX-xi, centerY+yi, c, alpha)
-		SetPixelWithAlpha(canvas, centerX-xi, centerY-yi, c, alpha)
-		SetPixelWithAlpha(canvas, centerX-yi, centerY-xi, c, alpha)
-		SetPixelWithAlpha(canvas, centerX+yi, centerY-xi, c, alpha)
-		SetPixelWithAlpha(canvas, centerX+xi, centerY-yi, c, alpha)
+func fpart(x float64) float64 {
+	return x - math.Floor(x)
+}
+
+
+func rfpart(x float64) float64 {
+	return 1 - fpart(x)
+}
+
+
+func XiaolinWuCircle(canvas [][]color.Color, centerX, centerY, radius float64, c color.Color) {
+	if radius <= 0 {
+		return
 	}
 	
 	
-	drawPixel(x, y, 1.0)
+	limit := radius / math.Sqrt2
 	
 	
-	for x > y {
-		y++
-
-		if err <= 0 {
-			err += 2*y + 1
+	for x := int(math.Ceil(centerX - limit)); x <= int(math.Floor(centerX+limit)); x++ {
+		dx := float64(x) - centerX
+		h := math.Sqrt(radius*radius - dx*dx)
+		for _, y := range []float64{centerY - h, centerY + h} {
+			yi := int(math.Floor(y))
+			SetPixelWithAlpha(canvas, x, yi, c, rfpart(y))
+			SetPixelWithAlpha(canvas, x, yi+1, c, fpart(y))
 		}
-
-		if err > 0 {
-			x--
-			err -= 2*x + 1
-		}
-
-
-		if x >= y {
-			idealRadius := math.Sqrt(float64(x*x + y*y))
-			diff := idealRadius - float64(radius)
-			alpha := 1.0 - math.Abs(diff)
-			if alpha < 0 {
-				alpha = 0
-			}
+	}
 	
-			drawPixel(x, y, alpha)
+	
+	for y := int(math.Ceil(centerY - limit)); y <= int(math.Floor(centerY+limit)); y++ {
+		dy := float64(y) - centerY
+		w := math.Sqrt(radius*radius - dy*dy)
+		for _, x := range []float64{centerX - w, centerX + w} {
+			xi := int(math.Floor(x))
+			SetPixelWithAlpha(canvas, xi, y, c, rfpart(x))
+			SetPixelWithAlpha(canvas, xi+1, y, c, fpart(x))
 		}
 	}
-}
\ No newline at end of file
+}
@@ -1,6 +1,7 @@
 package algorithms
 
 import (
+	"fmt"
 	"image/color"
 	"math"
 	"sort"
@@ -8,90 +9,137 @@ import (
 
 
 type Edge struct {
-	YMax   int     
-	XOfYMin int     
-	SlopeInv float64 
+	YMax     float64
+	XOfYMin  float64
+	SlopeInv float64
 }
 
 
 func EdgeTableFill(canvas [][]color.Color, vertices []Point, fillColor color.Color) {
 	if len(vertices) < 3 {
-		return 
+		return
 	}
 
-	
-	minY := vertices[0].Y
-	maxY := vertices[0].Y
-	for _, v := range vertices {
-		if v.Y < minY {
-			minY = v.Y
+	EdgeTableFillRings(canvas, [][]Point{vertices}, fillColor)
+}
+
+
+func EdgeTableFillRings(canvas [][]color.Color, rings [][]Point, fillColor color.Color) {
+	scanlineSpans(rings, func(y, xStart, xEnd int) {
+		for x := xStart; x <= xEnd; x++ {
+			if y >= 0 && y < len(canvas) && x >= 0 && x < len(canvas[0]) {
+				canvas[y][x] = fillColor
+			}
 		}
-		if v.Y > maxY {
-			maxY = v.Y
+	}, nil)
+}
+
+
+func scanlineSpans(rings [][]Point, span func(y, xStart, xEnd int), trace *Trace) {
+	minY, maxY := 0.0, 0.0
+	found := false
+	for _, ring := range rings {
+		if len(ring) < 3 {
+			continue
 		}
+		for _, v := range ring {
+			if !found || v.Y < minY {
+				minY = v.Y
+			}
+			if !found || v.Y > maxY {
+				maxY = v.Y
+			}
+			found = true
+		}
+	}
+	if !found {
+		return
 	}
 
-	
+
 	edgeTable := make(map[int][]Edge)
-	
-	
-	for i := 0; i < len(vertices); i++ {
-		v1 := vertices[i]
-		v2 := vertices[(i+1)%len(vertices)]
-		
-		if v1.Y == v2.Y {
+
+
+	for _, vertices := range rings {
+		if len(vertices) < 3 {
 			continue
 		}
-		if v1.Y > v2.Y {
-			v1, v2 = v2, v1
-		}
-		
-		slopeInv := float64(v2.X-v1.X) / float64(v2.Y-v1.Y)
-		
-		edge := Edge{
-			YMax:    v2.Y,
-			XOfYMin: v1.X,
-			SlopeInv: slopeInv,
+		for i := 0; i < len(vertices); i++ {
+			v1 := vertices[i]
+			v2 := vertices[(i+1)%len(vertices)]
+
+			if v1.Y == v2.Y {
+				continue
+			}
+			if v1.Y > v2.Y {
+				v1, v2 = v2, v1
+			}
+
+			slopeInv := (v2.X - v1.X) / (v2.Y - v1.Y)
+
+
+			startY := int(math.Ceil(v1.Y))
+			if float64(startY) >= v2.Y {
+				continue
+			}
+
+			edge := Edge{
+				YMax:     v2.Y,
+				XOfYMin:  v1.X + (float64(startY)-v1.Y)*slopeInv,
+				SlopeInv: slopeInv,
+			}
+
+			edgeTable[startY] = append(edgeTable[startY], edge)
 		}
-		
-		edgeTable[v1.Y] = append(edgeTable[v1.Y], edge)
 	}
-	
-	
+
+
 	var activeEdgeList []Edge
-	
-	
-	for y := minY; y <= maxY; y++ {
+
+
+	for y := int(math.Ceil(minY)); float64(y) <= maxY; y++ {
 		if edges, exists := edgeTable[y]; exists {
 			activeEdgeList = append(activeEdgeList, edges...)
 		}
-		
+
 		newAEL := make([]Edge, 0, len(activeEdgeList))
 		for _, edge := range activeEdgeList {
-			if edge.YMax > y {
+			if edge.YMax > float64(y) {
 				newAEL = append(newAEL, edge)
 			}
 		}
 		activeEdgeList = newAEL
-		
+
 		sort.Slice(activeEdgeList, func(i, j int) bool {
 			return activeEdgeList[i].XOfYMin < activeEdgeList[j].XOfYMin
 		})
-		
-		for i := 0; i < len(activeEdgeList)-1; i += 2 {
-			if i+1 < len(activeEdgeList) {
-				xStart := int(math.Floor(float64(activeEdgeList[i].XOfYMin)))
-				xEnd := int(math.Ceil(float64(activeEdgeList[i+1].XOfYMin)))
-						for x := xStart; x <= xEnd; x++ {
-					if y >= 0 && y < len(canvas) && x >= 0 && x < len(canvas[0]) {
-						canvas[y][x] = fillColor
-					}
-				}
+
+		var step *TraceStep
+		if trace != nil {
+			step = &TraceStep{
+				Description: fmt.Sprintf("Scanline %d: fill between pairs of active edges", y),
+				Vars:        []TraceVar{traceVar("y", y), traceVar("active edges", len(activeEdgeList))},
+			}
+			for i, edge := range activeEdgeList {
+				step.Vars = append(step.Vars, traceVar(fmt.Sprintf("AEL[%d]", i),
+					fmt.Sprintf("x=%.2f yMax=%.2f 1/m=%.3f", edge.XOfYMin, edge.YMax, edge.SlopeInv)))
 			}
 		}
-		
+
+		for i := 0; i+1 < len(activeEdgeList); i += 2 {
+			xStart := int(math.Round(activeEdgeList[i].XOfYMin))
+			xEnd := int(math.Round(activeEdgeList[i+1].XOfYMin))
+			span(y, xStart, xEnd)
+			if step != nil {
+				step.Pixels = append(step.Pixels, spanPixels(y, xStart, xEnd)...)
+			}
+		}
+		if step != nil {
+			trace.add(*step)
+		}
+
 		for i := range activeEdgeList {
-			activeEdgeList[i].XOfYMin += int(math.Round(activeEdgeList[i].SlopeInv))
+			activeEdgeList[i].XOfYMin += activeEdgeList[i].SlopeInv
 		}
 	}
 }
@@ -99,115 +147,48 @@ func EdgeTableFill(canvas [][]color.Color, vertices []Point, fillColor color.Col
 
 func FillPolygonWithImage(canvas [][]color.Color, vertices []Point, fillImage [][]color.Color) {
 	if len(vertices) < 3 || fillImage == nil || len(fillImage) == 0 || len(fillImage[0]) == 0 {
-		return 
+		return
 	}
 
-	
+
 	minX, minY := vertices[0].X, vertices[0].Y
 	maxX, maxY := vertices[0].X, vertices[0].Y
-	
+
 	for _, v := range vertices {
-		if v.X < minX {
-			minX = v.X
-		}
-		if v.Y < minY {
-			minY = v.Y
-		}
-		if v.X > maxX {
-			maxX = v.X
-		}
-		if v.Y > maxY {
-			maxY = v.Y
-		}
+		minX = math.Min(minX, v.X)
+		minY = math.Min(minY, v.Y)
+		maxX = math.Max(maxX, v.X)
+		maxY = math.Max(maxY, v.Y)
 	}
-	
-	
+
+
 	polygonWidth := maxX - minX
 	polygonHeight := maxY - minY
-	
-	
+
+
 	if polygonWidth <= 0 {
 		polygonWidth = 1
 	}
 	if polygonHeight <= 0 {
 		polygonHeight = 1
 	}
-	
+
 	imgWidth := len(fillImage[0])
 	imgHeight := len(fillImage)
 
-	
-	edgeTable := make(map[int][]Edge)
-	
-	
-	for i := 0; i < len(vertices); i++ {
-		v1 := vertices[i]
-		v2 := vertices[(i+1)%len(vertices)]
-		
-		if v1.Y == v2.Y {
-			continue
-		}
-		
-		if v1.Y > v2.Y {
-			v1, v2 = v2, v1
-		}
-		
-		slopeInv := float64(v2.X-v1.X) / float64(v2.Y-v1.Y)
-		
-		edge := Edge{
-			YMax:    v2.Y,
-			XOfYMin: v1.X,
-			SlopeInv: slopeInv,
-		}
-		
-		edgeTable[v1.Y] = append(edgeTable[v1.Y], edge)
-	}
-	
-	
-	var activeEdgeList []Edge
-	
-	
-	for y := minY; y <= maxY; y++ {
-		if edges, exists := edgeTable[y]; exists {
-			activeEdgeList = append(activeEdgeList, edges...)
-		}
-		
-		newAEL := make([]Edge, 0, len(activeEdgeList))
-		for _, edge := range activeEdgeList {
-			if edge.YMax > y {
-				newAEL = append(newAEL, edge)
-			}
-		}
-		activeEdgeList = newAEL
-		
-		sort.Slice(activeEdgeList, func(i, j int) bool {
-			return activeEdgeList[i].XOfYMin < activeEdgeList[j].XOfYMin
-		})
-		
-		for i := 0; i < len(activeEdgeList)-1; i += 2 {
-			if i+1 < len(activeEdgeList) {
-				xStart := int(math.Floor(float64(activeEdgeList[i].XOfYMin)))
-				xEnd := int(math.Ceil(float64(activeEdgeList[i+1].XOfYMin)))
-						for x := xStart; x <= xEnd; x++ {
-					if y >= 0 && y < len(canvas) && x >= 0 && x < len(canvas[0]) {
-														tx := ((x - minX) * imgWidth) / polygonWidth % imgWidth
-						ty := ((y - minY) * imgHeight) / polygonHeight % imgHeight
-														if tx < 0 {
-							tx += imgWidth
-						}
-						if ty < 0 {
-							ty += imgHeight
-						}
-														if ty >= 0 && ty < imgHeight && tx >= 0 && tx < imgWidth {
-							canvas[y][x] = fillImage[ty][tx]
-						}
-					}
+	scanlineSpans([][]Point{vertices}, func(y, xStart, xEnd int) {
+		for x := xStart; x <= xEnd; x++ {
+			if y >= 0 && y < len(canvas) && x >= 0 && x < len(canvas[0]) {
+				tx := int((float64(x)-minX)*float64(imgWidth)/polygonWidth) % imgWidth
+				ty := int((float64(y)-minY)*float64(imgHeight)/polygonHeight) % imgHeight
+				if tx < 0 {
+					tx += imgWidth
 				}
+				if ty < 0 {
+					ty += imgHeight
+				}
+				canvas[y][x] = fillImage[ty][tx]
 			}
 		}
-		
-		for i := range activeEdgeList {
-			activeEdgeList[i].XOfYMin += int(math.Round(activeEdgeList[i].SlopeInv))
-		}
-	}
+	}, nil)
 }
@@ -0,0 +1,405 @@
+package algorithms
+
+import (
+	"image/color"
+	"math"
+)
+
+
+func DDALine(canvas [][]color.Color, x0, y0, x1, y1 int, c color.Color) {
+	ddaPoints(x0, y0, x1, y1, func(x, y int) {
+		SetPixel(canvas, x, y, c)
+	})
+}
+
+
+func ddaPoints(x0, y0, x1, y1 int, plot func(x, y int)) {
+	dx := float64(x1 - x0)
+	dy := float64(y1 - y0)
+	steps := int(math.Max(math.Abs(dx), math.Abs(dy)))
+	if steps == 0 {
+		plot(x0, y0)
+		return
+	}
+
+	xInc := dx / float64(steps)
+	yInc := dy / float64(steps)
+	x, y := float64(x0), float64(y0)
+	for i := 0; i <= steps; i++ {
+		plot(int(math.Round(x)), int(math.Round(y)))
+		x += xInc
+		y += yInc
+	}
+}
+
+
+func BresenhamLine(canvas [][]color.Color, x0, y0, x1, y1 int, c color.Color) {
+	bresenhamPoints(x0, y0, x1, y1, func(x, y int) {
+		SetPixel(canvas, x, y, c)
+	})
+}
+
+
+func bresenhamPoints(x0, y0, x1, y1 int, plot func(x, y int)) {
+	dx := absInt(x1 - x0)
+	dy := -absInt(y1 - y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	err := dx + dy
+	for {
+		plot(x0, y0)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * err
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+
+func DoubleStepLine(canvas [][]color.Color, x0, y0, x1, y1 int, c color.Color) {
+	doubleStepPoints(x0, y0, x1, y1, func(x, y int) {
+		SetPixel(canvas, x, y, c)
+	})
+}
+
+
+func doubleStepPoints(x0, y0, x1, y1 int, put func(x, y int)) {
+	steep := absInt(y1-y0) > absInt(x1-x0)
+	if steep {
+		x0, y0 = y0, x0
+		x1, y1 = y1, x1
+	}
+	if x0 > x1 {
+		x0, x1 = x1, x0
+		y0, y1 = y1, y0
+	}
+
+	plot := func(x, y int) {
+		if steep {
+			put(y, x)
+		} else {
+			put(x, y)
+		}
+	}
+
+	dx := x1 - x0
+	dy := y1 - y0
+	step := 1
+	if dy < 0 {
+		step = -1
+		dy = -dy
+	}
+	if dx == 0 {
+		plot(x0, y0)
+		return
+	}
+
+	x, y := x0, y0
+	xb, yb := x1, y1
+	xEnd := (dx - 1) / 4
+	pixelsLeft := (dx - 1) % 4
+	plot(x, y)
+	plot(xb, yb)
+
+	incr2 := 4*dy - 2*dx
+	if incr2 < 0 {
+		cc := 2 * dy
+		incr1 := 2 * cc
+		d := incr1 - dx
+		for i := 0; i < xEnd; i++ {
+			x++
+			xb--
+			if d < 0 {
+				plot(x, y)
+				x++
+				plot(x, y)
+				plot(xb, yb)
+				xb--
+				plot(xb, yb)
+				d += incr1
+			} else {
+				if d < cc {
+					plot(x, y)
+					x++
+					y += step
+					plot(x, y)
+					plot(xb, yb)
+					xb--
+					yb -= step
+					plot(xb, yb)
+				} else {
+					y += step
+					plot(x, y)
+					x++
+					plot(x, y)
+					yb -= step
+					plot(xb, yb)
+					xb--
+					plot(xb, yb)
+				}
+				d += incr2
+			}
+		}
+
+		if pixelsLeft > 0 {
+			if d < 0 {
+				x++
+				plot(x, y)
+				if pixelsLeft > 1 {
+					x++
+					plot(x, y)
+				}
+				if pixelsLeft > 2 {
+					xb--
+					plot(xb, yb)
+				}
+			} else if d < cc {
+				x++
+				plot(x, y)
+				if pixelsLeft > 1 {
+					x++
+					y += step
+					plot(x, y)
+				}
+				if pixelsLeft > 2 {
+					xb--
+					plot(xb, yb)
+				}
+			} else {
+				x++
+				y += step
+				plot(x, y)
+				if pixelsLeft > 1 {
+					x++
+					plot(x, y)
+				}
+				if pixelsLeft > 2 {
+					xb--
+					yb -= step
+					plot(xb, yb)
+				}
+			}
+		}
+		return
+	}
+
+	cc := 2 * (dy - dx)
+	incr1 := 2 * cc
+	d := incr1 + dx
+	for i := 0; i < xEnd; i++ {
+		x++
+		xb--
+		if d > 0 {
+			y += step
+			plot(x, y)
+			x++
+			y += step
+			plot(x, y)
+			yb -= step
+			plot(xb, yb)
+			xb--
+			yb -= step
+			plot(xb, yb)
+			d += incr1
+		} else {
+			if d < cc {
+				plot(x, y)
+				x++
+				y += step
+				plot(x, y)
+				plot(xb, yb)
+				xb--
+				yb -= step
+				plot(xb, yb)
+			} else {
+				y += step
+				plot(x, y)
+				x++
+				plot(x, y)
+				yb -= step
+				plot(xb, yb)
+				xb--
+				plot(xb, yb)
+			}
+			d += incr2
+		}
+	}
+
+	if pixelsLeft > 0 {
+		if d > 0 {
+			x++
+			y += step
+			plot(x, y)
+			if pixelsLeft > 1 {
+				x++
+				y += step
+				plot(x, y)
+			}
+			if pixelsLeft > 2 {
+				xb--
+				yb -= step
+				plot(xb, yb)
+			}
+		} else if d < cc {
+			x++
+			plot(x, y)
+			if pixelsLeft > 1 {
+				x++
+				y += step
+				plot(x, y)
+			}
+			if pixelsLeft > 2 {
+				xb--
+				plot(xb, yb)
+			}
+		} else {
+			x++
+			y += step
+			plot(x, y)
+			if pixelsLeft > 1 {
+				x++
+				plot(x, y)
+			}
+			if pixelsLeft > 2 {
+				xb--
+				if d > cc {
+					yb -= step
+				}
+				plot(xb, yb)
+			}
+		}
+	}
+}
+
+
+func GuptaSproullLine(canvas [][]color.Color, x0, y0, x1, y1 int, c color.Color) {
+	guptaSproullPoints(x0, y0, x1, y1, func(x, y int, alpha float64) {
+		SetPixelWithAlpha(canvas, x, y, c, alpha)
+	})
+}
+
+
+func guptaSproullPoints(x0, y0, x1, y1 int, plot func(x, y int, alpha float64)) {
+	steep := absInt(y1-y0) > absInt(x1-x0)
+	if steep {
+		x0, y0 = y0, x0
+		x1, y1 = y1, x1
+	}
+	if x0 > x1 {
+		x0, x1 = x1, x0
+		y0, y1 = y1, y0
+	}
+
+	dx := x1 - x0
+	dy := y1 - y0
+	step := 1
+	if dy < 0 {
+		step = -1
+		dy = -dy
+	}
+
+	intensify := func(x, y int, distance float64) {
+		alpha := 1 - math.Abs(distance)/1.5
+		if alpha <= 0 {
+			return
+		}
+		if steep {
+			plot(y, x, alpha)
+		} else {
+			plot(x, y, alpha)
+		}
+	}
+
+	if dx == 0 {
+		intensify(x0, y0, 0)
+		return
+	}
+
+	d := 2*dy - dx
+	incrE := 2 * dy
+	incrNE := 2 * (dy - dx)
+	invDenom := 1 / (2 * math.Sqrt(float64(dx*dx+dy*dy)))
+	twoDxInvDenom := 2 * float64(dx) * invDenom
+
+	x, y := x0, y0
+	intensify(x, y, 0)
+	intensify(x, y+step, twoDxInvDenom)
+	intensify(x, y-step, twoDxInvDenom)
+
+	for x < x1 {
+		var twoVdx int
+		if d < 0 {
+			twoVdx = d + dx
+			d += incrE
+		} else {
+			twoVdx = d - dx
+			d += incrNE
+			y += step
+		}
+		x++
+
+		distance := float64(twoVdx) * invDenom
+		intensify(x, y, distance)
+		intensify(x, y+step, twoDxInvDenom-distance)
+		intensify(x, y-step, twoDxInvDenom+distance)
+	}
+}
+
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+
+func StrokeLine(canvas [][]color.Color, line LinePlotter, x0, y0, x1, y1 float64, c color.Color, thickness int) {
+	if thickness <= 1 {
+		line.Draw(canvas, x0, y0, x1, y1, c)
+		return
+	}
+
+	brush := MakeCircularBrush(thickness)
+	radius := len(brush) / 2
+	coverage := map[Pixel]float64{}
+	line(x0, y0, x1, y1, func(x, y int, alpha float64) {
+		for by, row := range brush {
+			for bx, inside := range row {
+				if !inside {
+					continue
+				}
+				px, py := x+bx-radius, y+by-radius
+				if py < 0 || py >= len(canvas) || px < 0 || px >= len(canvas[py]) {
+					continue
+				}
+				pixel := Pixel{X: px, Y: py}
+				if alpha > coverage[pixel] {
+					coverage[pixel] = alpha
+				}
+			}
+		}
+	})
+
+	for pixel, alpha := range coverage {
+		if alpha >= 1 {
+			SetPixel(canvas, pixel.X, pixel.Y, c)
+		} else {
+			SetPixelWithAlpha(canvas, pixel.X, pixel.Y, c, alpha)
+		}
+	}
+}
@@ -0,0 +1,403 @@
+package algorithms
+
+import (
+	"math"
+)
+
+
+type JoinType int
+
+const (
+	MiterJoin JoinType = iota
+	RoundJoin
+	BevelJoin
+)
+
+
+type CapType int
+
+const (
+	ButtCap CapType = iota
+	RoundCap
+	SquareCap
+)
+
+
+const DefaultMiterLimit = 4.0
+
+
+func OffsetPolygon(vertices []Point, distance float64, join JoinType, miterLimit float64) []Point {
+	if len(vertices) < 3 {
+		return nil
+	}
+
+	pts := dedupePoints(vertices, true)
+	if len(pts) < 3 {
+		return nil
+	}
+
+	area := signedArea(pts)
+	if area == 0 {
+		return nil
+	}
+
+	d := distance
+	if area < 0 {
+		d = -d
+	}
+
+	result := offsetPath(pts, true, d, join, miterLimit)
+	if len(result) < 3 {
+		return nil
+	}
+
+	if distance < 0 {
+		result = dropCollapsedPoints(result, pts, -distance)
+		if len(result) < 3 || !validInset(result, pts, -distance) {
+			return nil
+		}
+	}
+
+	newArea := signedArea(result)
+	if newArea == 0 || (newArea > 0) != (area > 0) {
+		return nil
+	}
+
+	return result
+}
+
+
+func StrokeOutline(points []Point, closed bool, width float64, join JoinType, capType CapType, miterLimit float64) []Point {
+	pts := dedupePoints(points, closed)
+	half := width / 2
+	if half <= 0 {
+		half = 0.5
+	}
+
+	if len(pts) == 1 {
+		return arcPoints(pts[0], half, 0, 2*math.Pi, false)
+	}
+	if len(pts) == 0 {
+		return nil
+	}
+
+	if closed && len(pts) >= 3 {
+		d := half
+		if signedArea(pts) < 0 {
+			d = -d
+		}
+		outer := offsetPath(pts, true, d, join, miterLimit)
+		inner := OffsetPolygon(pts, -half, join, miterLimit)
+		if len(inner) < 3 {
+			return outer
+		}
+
+
+		ring := make([]Point, 0, len(outer)+len(inner)+2)
+		ring = append(ring, outer...)
+		ring = append(ring, outer[0])
+		ring = append(ring, inner[0])
+		for i := len(inner) - 1; i >= 1; i-- {
+			ring = append(ring, inner[i])
+		}
+		ring = append(ring, inner[0])
+		return ring
+	}
+
+	left := offsetPath(pts, false, half, join, miterLimit)
+	reversed := make([]Point, len(pts))
+	for i, p := range pts {
+		reversed[len(pts)-1-i] = p
+	}
+	right := offsetPath(reversed, false, half, join, miterLimit)
+
+	result := make([]Point, 0, len(left)+len(right)+32)
+	result = append(result, left...)
+	result = append(result, capPoints(pts[len(pts)-2], pts[len(pts)-1], half, capType)...)
+	result = append(result, right...)
+	result = append(result, capPoints(pts[1], pts[0], half, capType)...)
+
+	return result
+}
+
+
+func offsetPath(pts []Point, closed bool, d float64, join JoinType, miterLimit float64) []Point {
+	n := len(pts)
+	if n < 2 {
+		return nil
+	}
+	if miterLimit < 1 {
+		miterLimit = DefaultMiterLimit
+	}
+
+	segCount := n - 1
+	if closed {
+		segCount = n
+	}
+
+	normals := make([]Point, segCount)
+	for i := 0; i < segCount; i++ {
+		a := pts[i]
+		b := pts[(i+1)%n]
+		normals[i] = edgeNormal(a, b)
+	}
+
+	result := make([]Point, 0, n*2)
+
+	if !closed {
+		result = append(result, Point{pts[0].X + normals[0].X*d, pts[0].Y + normals[0].Y*d})
+	}
+
+	for i := 0; i < n; i++ {
+		var n1, n2 Point
+		if closed {
+			n1 = normals[(i-1+n)%n]
+			n2 = normals[i]
+		} else {
+			if i == 0 || i == n-1 {
+				continue
+			}
+			n1 = normals[i-1]
+			n2 = normals[i]
+		}
+		result = append(result, joinPoints(pts[i], n1, n2, d, join, miterLimit)...)
+	}
+
+	if !closed {
+		last := normals[segCount-1]
+		result = append(result, Point{pts[n-1].X + last.X*d, pts[n-1].Y + last.Y*d})
+	}
+
+	return result
+}
+
+
+func joinPoints(b, n1, n2 Point, d float64, join JoinType, miterLimit float64) []Point {
+	p1 := Point{b.X + n1.X*d, b.Y + n1.Y*d}
+	p2 := Point{b.X + n2.X*d, b.Y + n2.Y*d}
+
+	cross := n1.X*n2.Y - n1.Y*n2.X
+	dot := n1.X*n2.X + n1.Y*n2.Y
+
+	if math.Abs(cross) < 1e-9 && dot > 0 {
+		return []Point{p1}
+	}
+
+	e1 := Point{-n1.Y, n1.X}
+	e2 := Point{-n2.Y, n2.X}
+	turn := e1.X*e2.Y - e1.Y*e2.X
+	outer := turn*d > 0
+
+	if !outer {
+		return []Point{miterPoint(b, n1, n2, d, p1, p2)}
+	}
+
+	switch join {
+	case RoundJoin:
+		a1 := math.Atan2(n1.Y*d, n1.X*d)
+		delta := math.Atan2(cross, dot)
+		return arcPoints(b, math.Abs(d), a1, delta, true)
+	case MiterJoin:
+		mx, my := n1.X+n2.X, n1.Y+n2.Y
+		ml := math.Hypot(mx, my)
+		if ml > 1e-9 {
+			mx, my = mx/ml, my/ml
+			cosHalf := mx*n1.X + my*n1.Y
+			if cosHalf > 1e-9 && 1/cosHalf <= miterLimit {
+				return []Point{{b.X + mx*d/cosHalf, b.Y + my*d/cosHalf}}
+			}
+		}
+	}
+
+	return []Point{p1, p2}
+}
+
+
+func miterPoint(b, n1, n2 Point, d float64, p1, p2 Point) Point {
+	mx, my := n1.X+n2.X, n1.Y+n2.Y
+	ml := math.Hypot(mx, my)
+	if ml < 1e-9 {
+		return Point{(p1.X + p2.X) / 2, (p1.Y + p2.Y) / 2}
+	}
+	mx, my = mx/ml, my/ml
+	cosHalf := mx*n1.X + my*n1.Y
+	if cosHalf < 1e-3 {
+		return Point{(p1.X + p2.X) / 2, (p1.Y + p2.Y) / 2}
+	}
+	return Point{b.X + mx*d/cosHalf, b.Y + my*d/cosHalf}
+}
+
+
+func dropCollapsedPoints(result, original []Point, distance float64) []Point {
+	kept := make([]Point, 0, len(result))
+	for _, p := range result {
+		minDist := math.Inf(1)
+		for i := range original {
+			d := segmentDistance(p, original[i], original[(i+1)%len(original)])
+			if d < minDist {
+				minDist = d
+			}
+		}
+		if minDist >= distance-1 {
+			kept = append(kept, p)
+		}
+	}
+	return kept
+}
+
+
+func validInset(result, original []Point, distance float64) bool {
+	if !IsPolygonSimple(result) {
+		return false
+	}
+	for i, p := range result {
+		if !insidePolygon(original, p) {
+			return false
+		}
+		q := result[(i+1)%len(result)]
+		if isJoinEdge(p, q, original, distance) {
+			continue
+		}
+		for j := range original {
+			if segmentsDistance(p, q, original[j], original[(j+1)%len(original)]) < distance-1 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+
+func isJoinEdge(p, q Point, original []Point, distance float64) bool {
+	eps := 1e-6 * math.Max(1, distance)
+	for _, v := range original {
+		if math.Abs(math.Hypot(p.X-v.X, p.Y-v.Y)-distance) <= eps && math.Abs(math.Hypot(q.X-v.X, q.Y-v.Y)-distance) <= eps {
+			return true
+		}
+	}
+	return false
+}
+
+
+func insidePolygon(vertices []Point, p Point) bool {
+	inside := false
+	for i, j := 0, len(vertices)-1; i < len(vertices); j, i = i, i+1 {
+		a, b := vertices[i], vertices[j]
+		if (a.Y > p.Y) != (b.Y > p.Y) && p.X < (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y)+a.X {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+
+func segmentsDistance(a1, a2, b1, b2 Point) float64 {
+	if doLinesIntersect(a1, a2, b1, b2) {
+		return 0
+	}
+	return math.Min(
+		math.Min(segmentDistance(a1, b1, b2), segmentDistance(a2, b1, b2)),
+		math.Min(segmentDistance(b1, a1, a2), segmentDistance(b2, a1, a2)))
+}
+
+
+func segmentDistance(p, a, b Point) float64 {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	lenSq := dx*dx + dy*dy
+	if lenSq == 0 {
+		return math.Hypot(p.X-a.X, p.Y-a.Y)
+	}
+	t := ((p.X-a.X)*dx + (p.Y-a.Y)*dy) / lenSq
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(p.X-(a.X+t*dx), p.Y-(a.Y+t*dy))
+}
+
+
+func capPoints(prev, end Point, half float64, capType CapType) []Point {
+	n := edgeNormal(prev, end)
+	dx, dy := end.X-prev.X, end.Y-prev.Y
+	l := math.Hypot(dx, dy)
+	if l == 0 {
+		return nil
+	}
+	dx, dy = dx/l, dy/l
+
+	switch capType {
+	case RoundCap:
+		pts := arcPoints(end, half, math.Atan2(n.Y, n.X), math.Pi, true)
+		if len(pts) > 2 {
+			return pts[1 : len(pts)-1]
+		}
+		return nil
+	case SquareCap:
+		return []Point{
+			{end.X + n.X*half + dx*half, end.Y + n.Y*half + dy*half},
+			{end.X - n.X*half + dx*half, end.Y - n.Y*half + dy*half},
+		}
+	}
+	return nil
+}
+
+
+func arcPoints(center Point, radius, start, sweep float64, includeEnd bool) []Point {
+	segments := int(math.Ceil(math.Abs(sweep) / (math.Pi / 16)))
+	if radius > 40 {
+		segments = int(math.Ceil(float64(segments) * radius / 40))
+	}
+	if segments < 1 {
+		segments = 1
+	}
+
+	count := segments
+	if includeEnd {
+		count++
+	}
+
+	pts := make([]Point, 0, count)
+	for i := 0; i < count; i++ {
+		a := start + sweep*float64(i)/float64(segments)
+		pts = append(pts, Point{center.X + radius*math.Cos(a), center.Y + radius*math.Sin(a)})
+	}
+	return pts
+}
+
+
+func edgeNormal(a, b Point) Point {
+	dx, dy := b.X-a.X, b.Y-a.Y
+	l := math.Hypot(dx, dy)
+	if l == 0 {
+		return Point{}
+	}
+	return Point{dy / l, -dx / l}
+}
+
+
+func signedArea(pts []Point) float64 {
+	area := 0.0
+	for i := range pts {
+		j := (i + 1) % len(pts)
+		area += pts[i].X*pts[j].Y - pts[j].X*pts[i].Y
+	}
+	return area / 2
+}
+
+
+func dedupePoints(pts []Point, closed bool) []Point {
+	result := make([]Point, 0, len(pts))
+	for _, p := range pts {
+		if len(result) > 0 {
+			last := result[len(result)-1]
+			if last.X == p.X && last.Y == p.Y {
+				continue
+			}
+		}
+		result = append(result, p)
+	}
+	if closed && len(result) > 1 {
+		first, last := result[0], result[len(result)-1]
+		if first.X == last.X && first.Y == last.Y {
+			result = result[:len(result)-1]
+		}
+	}
+	return result
+}
@@ -0,0 +1,178 @@
+package algorithms
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+
+var (
+	offsetSquare   = []Point{{0, 0}, {10, 0}, {10, 10}, {0, 10}}
+	offsetReversed = []Point{{0, 10}, {10, 10}, {10, 0}, {0, 0}}
+	offsetL        = []Point{{0, 0}, {20, 0}, {20, 10}, {10, 10}, {10, 20}, {0, 20}}
+	offsetDumbbell = []Point{
+		{0, 0}, {20, 0}, {20, 8}, {30, 8}, {30, 0}, {50, 0},
+		{50, 20}, {30, 20}, {30, 12}, {20, 12}, {20, 20}, {0, 20},
+	}
+)
+
+
+func boundaryDistance(p Point, polygon []Point) float64 {
+	best := math.Inf(1)
+	for i := range polygon {
+		best = math.Min(best, segmentDistance(p, polygon[i], polygon[(i+1)%len(polygon)]))
+	}
+	return best
+}
+
+
+func TestOffsetPolygon(t *testing.T) {
+	tests := []struct {
+		name     string
+		polygon  []Point
+		distance float64
+		want     []Point
+	}{
+		{name: "square outward", polygon: offsetSquare, distance: 2, want: []Point{{-2, -2}, {12, -2}, {12, 12}, {-2, 12}}},
+		{name: "square inward", polygon: offsetSquare, distance: -2, want: []Point{{2, 2}, {8, 2}, {8, 8}, {2, 8}}},
+		{name: "reversed square outward", polygon: offsetReversed, distance: 2, want: []Point{{-2, 12}, {12, 12}, {12, -2}, {-2, -2}}},
+		{name: "reversed square inward", polygon: offsetReversed, distance: -2, want: []Point{{2, 8}, {8, 8}, {8, 2}, {2, 2}}},
+		{name: "concave outward", polygon: offsetL, distance: 2, want: []Point{{-2, -2}, {22, -2}, {22, 12}, {12, 12}, {12, 22}, {-2, 22}}},
+		{name: "concave inward", polygon: offsetL, distance: -2, want: []Point{{2, 2}, {18, 2}, {18, 8}, {8, 8}, {8, 18}, {2, 18}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := OffsetPolygon(tt.polygon, tt.distance, MiterJoin, DefaultMiterLimit); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("OffsetPolygon = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+
+func TestOffsetPolygonRoundJoin(t *testing.T) {
+	got := OffsetPolygon(offsetL, 2, RoundJoin, DefaultMiterLimit)
+	if len(got) <= len(offsetL) {
+		t.Fatalf("round join produced %d points, want arcs at the convex corners", len(got))
+	}
+	for _, p := range got {
+		if d := boundaryDistance(p, offsetL); math.Abs(d-2) > 1e-9 {
+			t.Fatalf("point %v is %v from the polygon, want 2", p, d)
+		}
+	}
+	if !IsPolygonSimple(got) {
+		t.Fatalf("round-joined offset is not simple")
+	}
+}
+
+
+func TestOffsetPolygonPastInradius(t *testing.T) {
+	tests := []struct {
+		name     string
+		polygon  []Point
+		distance float64
+		valid    bool
+	}{
+		{name: "square at its inradius", polygon: offsetSquare, distance: -5},
+		{name: "square past its inradius", polygon: offsetSquare, distance: -6},
+		{name: "square far past its inradius", polygon: offsetSquare, distance: -50},
+		{name: "reversed square far past its inradius", polygon: offsetReversed, distance: -50},
+		{name: "concave past its inradius", polygon: offsetL, distance: -6},
+		{name: "concave far past its inradius", polygon: offsetL, distance: -50},
+		{name: "dumbbell inside its bridge", polygon: offsetDumbbell, distance: -1, valid: true},
+		{name: "dumbbell closing its bridge", polygon: offsetDumbbell, distance: -2},
+		{name: "dumbbell past its bridge", polygon: offsetDumbbell, distance: -4.9},
+		{name: "dumbbell near its inradius", polygon: offsetDumbbell, distance: -9},
+		{name: "dumbbell past its inradius", polygon: offsetDumbbell, distance: -11},
+		{name: "dumbbell far past its inradius", polygon: offsetDumbbell, distance: -50},
+	}
+
+	for _, tt := range tests {
+		for _, join := range []JoinType{MiterJoin, RoundJoin, BevelJoin} {
+			t.Run(fmt.Sprintf("%s/join %d", tt.name, join), func(t *testing.T) {
+				got := OffsetPolygon(tt.polygon, tt.distance, join, DefaultMiterLimit)
+				if !tt.valid {
+					if got != nil {
+						t.Fatalf("OffsetPolygon = %v, want nil", got)
+					}
+					return
+				}
+				if len(got) < 3 || !IsPolygonSimple(got) {
+					t.Fatalf("OffsetPolygon = %v, want a simple polygon", got)
+				}
+				for _, p := range got {
+					if !insidePolygon(tt.polygon, p) || boundaryDistance(p, tt.polygon) < -tt.distance-1e-9 {
+						t.Fatalf("point %v is not %v inside the polygon", p, -tt.distance)
+					}
+				}
+			})
+		}
+	}
+}
+
+
+func TestStrokeOutline(t *testing.T) {
+	line := []Point{{0, 0}, {10, 0}}
+
+	tests := []struct {
+		name   string
+		points []Point
+		closed bool
+		width  float64
+		cap    CapType
+		want   []Point
+	}{
+		{
+			name:   "butt cap",
+			points: line,
+			width:  4,
+			cap:    ButtCap,
+			want:   []Point{{0, -2}, {10, -2}, {10, 2}, {0, 2}},
+		},
+		{
+			name:   "square cap",
+			points: line,
+			width:  4,
+			cap:    SquareCap,
+			want:   []Point{{0, -2}, {10, -2}, {12, -2}, {12, 2}, {10, 2}, {0, 2}, {-2, 2}, {-2, -2}},
+		},
+		{
+			name:   "closed square",
+			points: offsetSquare,
+			closed: true,
+			width:  2,
+			want:   []Point{{-1, -1}, {11, -1}, {11, 11}, {-1, 11}, {-1, -1}, {1, 1}, {1, 9}, {9, 9}, {9, 1}, {1, 1}},
+		},
+		{
+			name:   "closed square wider than its inradius",
+			points: offsetSquare,
+			closed: true,
+			width:  12,
+			want:   []Point{{-6, -6}, {16, -6}, {16, 16}, {-6, 16}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StrokeOutline(tt.points, tt.closed, tt.width, MiterJoin, tt.cap, DefaultMiterLimit); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("StrokeOutline = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+
+func TestStrokeOutlineRoundCap(t *testing.T) {
+	got := StrokeOutline([]Point{{0, 0}, {10, 0}}, false, 4, MiterJoin, RoundCap, DefaultMiterLimit)
+	for _, p := range got {
+		if d := segmentDistance(p, Point{0, 0}, Point{10, 0}); math.Abs(d-2) > 1e-9 {
+			t.Fatalf("point %v is %v from the line, want 2", p, d)
+		}
+	}
+	if p := StrokeOutline([]Point{{5, 5}}, false, 4, MiterJoin, RoundCap, DefaultMiterLimit); len(p) == 0 {
+		t.Fatalf("a single point has no outline")
+	}
+}
@@ -10,19 +10,29 @@ func PointAdapter(modelPoints interface{}) []Point {
 	case []Point:
 		return points 
 
-	case []struct{ X, Y int }:
+	case []struct{ X, Y float64 }:
 		algorithmPoints = make([]Point, len(points))
 		for i, p := range points {
 			algorithmPoints[i] = Point{X: p.X, Y: p.Y}
 		}
 		
+	case []struct{ X, Y int }:
+		algorithmPoints = make([]Point, len(points))
+		for i, p := range points {
+			algorithmPoints[i] = Point{X: float64(p.X), Y: float64(p.Y)}
+		}
+		
 	case []interface{}:
 		algorithmPoints = make([]Point, len(points))
 		for i, p := range points {
-			if point, ok := p.(struct{ X, Y int }); ok {
+			if point, ok := p.(struct{ X, Y float64 }); ok {
 				algorithmPoints[i] = Point{X: point.X, Y: point.Y}
-			} else if pointMap, ok := p.(map[string]int); ok {
+			} else if point, ok := p.(struct{ X, Y int }); ok {
+				algorithmPoints[i] = Point{X: float64(point.X), Y: float64(point.Y)}
+			} else if pointMap, ok := p.(map[string]float64); ok {
 				algorithmPoints[i] = Point{X: pointMap["X"], Y: pointMap["Y"]}
+			} else if pointMap, ok := p.(map[string]int); ok {
+				algorithmPoints[i] = Point{X: float64(pointMap["X"]), Y: float64(pointMap["Y"])}
 			}
 		}
 	}
@@ -0,0 +1,157 @@
+package algorithms
+
+import (
+	"image/color"
+	"math"
+)
+
+
+type LineAlgorithm func(canvas [][]color.Color, x0, y0, x1, y1 float64, c color.Color)
+
+
+type LinePlotter func(x0, y0, x1, y1 float64, plot func(x, y int, alpha float64))
+
+
+type CircleAlgorithm func(canvas [][]color.Color, centerX, centerY, radius float64, c color.Color)
+
+
+type FillAlgorithm func(canvas [][]color.Color, rings [][]Point, fillColor color.Color)
+
+
+const (
+	LineMidpoint     = "Midpoint"
+	LineDDA          = "DDA"
+	LineBresenham    = "Bresenham"
+	LineDoubleStep   = "Symmetric Double-Step"
+	LineXiaolinWu    = "Xiaolin Wu"
+	LineGuptaSproull = "Gupta-Sproull"
+	CircleMidpoint   = "Midpoint"
+	CircleBresenham  = "Bresenham"
+	CircleXiaolinWu  = "Xiaolin Wu"
+	FillEdgeTable    = "Edge Table"
+	FillSeed         = "Seed Fill"
+)
+
+
+var (
+	lineNames        []string
+	circleNames      []string
+	fillNames        []string
+	lineAlgorithms   = map[string]LinePlotter{}
+	circleAlgorithms = map[string]CircleAlgorithm{}
+	fillAlgorithms   = map[string]FillAlgorithm{}
+)
+
+
+func RegisterLineAlgorithm(name string, algorithm LinePlotter) {
+	if _, exists := lineAlgorithms[name]; !exists {
+		lineNames = append(lineNames, name)
+	}
+	lineAlgorithms[name] = algorithm
+}
+
+
+func RegisterCircleAlgorithm(name string, algorithm CircleAlgorithm) {
+	if _, exists := circleAlgorithms[name]; !exists {
+		circleNames = append(circleNames, name)
+	}
+	circleAlgorithms[name] = algorithm
+}
+
+
+func RegisterFillAlgorithm(name string, algorithm FillAlgorithm) {
+	if _, exists := fillAlgorithms[name]; !exists {
+		fillNames = append(fillNames, name)
+	}
+	fillAlgorithms[name] = algorithm
+}
+
+
+func LookupLineAlgorithm(name string) (LineAlgorithm, bool) {
+	plotter, ok := lineAlgorithms[name]
+	if !ok {
+		return nil, false
+	}
+	return plotter.Draw, true
+}
+
+
+func LookupLinePlotter(name string) (LinePlotter, bool) {
+	plotter, ok := lineAlgorithms[name]
+	return plotter, ok
+}
+
+
+func (p LinePlotter) Draw(canvas [][]color.Color, x0, y0, x1, y1 float64, c color.Color) {
+	p(x0, y0, x1, y1, func(x, y int, alpha float64) {
+		if alpha >= 1 {
+			SetPixel(canvas, x, y, c)
+		} else {
+			SetPixelWithAlpha(canvas, x, y, c, alpha)
+		}
+	})
+}
+
+
+func LookupCircleAlgorithm(name string) (CircleAlgorithm, bool) {
+	algorithm, ok := circleAlgorithms[name]
+	return algorithm, ok
+}
+
+
+func LookupFillAlgorithm(name string) (FillAlgorithm, bool) {
+	algorithm, ok := fillAlgorithms[name]
+	return algorithm, ok
+}
+
+
+func LineAlgorithmNames() []string {
+	return append([]string(nil), lineNames...)
+}
+
+
+func CircleAlgorithmNames() []string {
+	return append([]string(nil), circleNames...)
+}
+
+
+func FillAlgorithmNames() []string {
+	return append([]string(nil), fillNames...)
+}
+
+
+func integerLine(points func(x0, y0, x1, y1 int, plot func(x, y int))) LinePlotter {
+	return func(x0, y0, x1, y1 float64, plot func(x, y int, alpha float64)) {
+		points(int(math.Round(x0)), int(math.Round(y0)), int(math.Round(x1)), int(math.Round(y1)), func(x, y int) {
+			plot(x, y, 1)
+		})
+	}
+}
+
+
+func integerCircle(draw func(canvas [][]color.Color, centerX, centerY, radius int, c color.Color)) CircleAlgorithm {
+	return func(canvas [][]color.Color, centerX, centerY, radius float64, c color.Color) {
+		draw(canvas, int(math.Round(centerX)), int(math.Round(centerY)), int(math.Round(radius)), c)
+	}
+}
+
+
+func init() {
+	RegisterLineAlgorithm(LineMidpoint, integerLine(func(x0, y0, x1, y1 int, plot func(x, y int)) {
+		midpointPoints(x0, y0, x1, y1, plot, nil)
+	}))
+	RegisterLineAlgorithm(LineDDA, integerLine(ddaPoints))
+	RegisterLineAlgorithm(LineBresenham, integerLine(bresenhamPoints))
+	RegisterLineAlgorithm(LineDoubleStep, integerLine(doubleStepPoints))
+	RegisterLineAlgorithm(LineXiaolinWu, xiaolinWuPoints)
+	RegisterLineAlgorithm(LineGuptaSproull, func(x0, y0, x1, y1 float64, plot func(x, y int, alpha float64)) {
+		guptaSproullPoints(int(math.Round(x0)), int(math.Round(y0)), int(math.Round(x1)), int(math.Round(y1)), plot)
+	})
+
+	RegisterCircleAlgorithm(CircleMidpoint, integerCircle(MidpointCircle))
+	RegisterCircleAlgorithm(CircleBresenham, integerCircle(BresenhamCircle))
+	RegisterCircleAlgorithm(CircleXiaolinWu, XiaolinWuCircle)
+
+	RegisterFillAlgorithm(FillEdgeTable, EdgeTableFillRings)
+	RegisterFillAlgorithm(FillSeed, SeedFillRings)
+}
@@ -0,0 +1,204 @@
+package algorithms
+
+import (
+	"fmt"
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+
+var (
+	testBackground = color.RGBA{255, 255, 255, 255}
+	testInk        = color.RGBA{0, 0, 0, 255}
+)
+
+
+func testCanvas(width, height int) [][]color.Color {
+	canvas := make([][]color.Color, height)
+	for y := range canvas {
+		canvas[y] = make([]color.Color, width)
+		for x := range canvas[y] {
+			canvas[y][x] = testBackground
+		}
+	}
+	return canvas
+}
+
+
+func inkedPixels(canvas [][]color.Color) map[Pixel]bool {
+	pixels := map[Pixel]bool{}
+	for y, row := range canvas {
+		for x, c := range row {
+			if c != color.Color(testBackground) {
+				pixels[Pixel{X: x, Y: y}] = true
+			}
+		}
+	}
+	return pixels
+}
+
+
+func plottedPixels(name string, x0, y0, x1, y1 float64) map[Pixel]bool {
+	plotter, _ := LookupLinePlotter(name)
+	pixels := map[Pixel]bool{}
+	plotter(x0, y0, x1, y1, func(x, y int, alpha float64) {
+		if alpha > 0 {
+			pixels[Pixel{X: x, Y: y}] = true
+		}
+	})
+	return pixels
+}
+
+
+func eightConnected(pixels map[Pixel]bool, start Pixel) bool {
+	seen := map[Pixel]bool{start: true}
+	queue := []Pixel{start}
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				n := Pixel{X: p.X + dx, Y: p.Y + dy}
+				if pixels[n] && !seen[n] {
+					seen[n] = true
+					queue = append(queue, n)
+				}
+			}
+		}
+	}
+	return len(seen) == len(pixels)
+}
+
+
+var lineCases = []struct {
+	name           string
+	x0, y0, x1, y1 int
+}{
+	{name: "horizontal", x0: 2, y0: 5, x1: 17, y1: 5},
+	{name: "horizontal reversed", x0: 17, y0: 5, x1: 2, y1: 5},
+	{name: "vertical", x0: 6, y0: 1, x1: 6, y1: 18},
+	{name: "vertical reversed", x0: 6, y0: 18, x1: 6, y1: 1},
+	{name: "diagonal", x0: 1, y0: 1, x1: 15, y1: 15},
+	{name: "anti-diagonal", x0: 15, y0: 2, x1: 2, y1: 15},
+	{name: "shallow", x0: 0, y0: 3, x1: 19, y1: 9},
+	{name: "steep", x0: 4, y0: 0, x1: 11, y1: 19},
+	{name: "steep reversed", x0: 11, y0: 19, x1: 4, y1: 0},
+	{name: "single pixel", x0: 7, y0: 7, x1: 7, y1: 7},
+}
+
+
+func TestLineAlgorithmsCoverEndpoints(t *testing.T) {
+	for _, name := range LineAlgorithmNames() {
+		for _, tt := range lineCases {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				pixels := plottedPixels(name, float64(tt.x0), float64(tt.y0), float64(tt.x1), float64(tt.y1))
+				for _, end := range []Pixel{{X: tt.x0, Y: tt.y0}, {X: tt.x1, Y: tt.y1}} {
+					if !pixels[end] {
+						t.Fatalf("endpoint %v not plotted", end)
+					}
+				}
+			})
+		}
+	}
+}
+
+
+func TestIntegerLinesAreEightConnected(t *testing.T) {
+	for _, name := range []string{LineMidpoint, LineDDA, LineBresenham, LineDoubleStep} {
+		for _, tt := range lineCases {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				pixels := plottedPixels(name, float64(tt.x0), float64(tt.y0), float64(tt.x1), float64(tt.y1))
+				steps := absInt(tt.x1 - tt.x0)
+				if dy := absInt(tt.y1 - tt.y0); dy > steps {
+					steps = dy
+				}
+				if len(pixels) != steps+1 {
+					t.Fatalf("plotted %d pixels, want %d", len(pixels), steps+1)
+				}
+				if !eightConnected(pixels, Pixel{X: tt.x0, Y: tt.y0}) {
+					t.Fatalf("line is not 8-connected: %v", pixels)
+				}
+			})
+		}
+	}
+}
+
+
+func TestIntegerLinesAgreeOnAxesAndDiagonals(t *testing.T) {
+	for _, tt := range lineCases {
+		if tt.name == "shallow" || tt.name == "steep" || tt.name == "steep reversed" {
+			continue
+		}
+		t.Run(tt.name, func(t *testing.T) {
+			want := plottedPixels(LineBresenham, float64(tt.x0), float64(tt.y0), float64(tt.x1), float64(tt.y1))
+			for _, name := range []string{LineDDA, LineDoubleStep} {
+				got := plottedPixels(name, float64(tt.x0), float64(tt.y0), float64(tt.x1), float64(tt.y1))
+				if !reflect.DeepEqual(got, want) {
+					t.Fatalf("%s plotted %v, Bresenham plotted %v", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+
+func TestCircleOctantSymmetry(t *testing.T) {
+	const cx, cy = 20, 20
+	for _, name := range CircleAlgorithmNames() {
+		for _, radius := range []int{1, 4, 9, 15} {
+			t.Run(fmt.Sprintf("%s/radius %d", name, radius), func(t *testing.T) {
+				canvas := testCanvas(41, 41)
+				circle, _ := LookupCircleAlgorithm(name)
+				circle(canvas, cx, cy, float64(radius), testInk)
+
+				pixels := inkedPixels(canvas)
+				if len(pixels) == 0 {
+					t.Fatalf("circle plotted no pixels")
+				}
+				for p := range pixels {
+					dx, dy := p.X-cx, p.Y-cy
+					for _, m := range [][2]int{{dx, dy}, {-dx, dy}, {dx, -dy}, {-dx, -dy}, {dy, dx}, {-dy, dx}, {dy, -dx}, {-dy, -dx}} {
+						mirror := Pixel{X: cx + m[0], Y: cy + m[1]}
+						if canvas[mirror.Y][mirror.X] != canvas[p.Y][p.X] {
+							t.Fatalf("pixel %v is %v but its mirror %v is %v", p, canvas[p.Y][p.X], mirror, canvas[mirror.Y][mirror.X])
+						}
+					}
+				}
+			})
+		}
+	}
+}
+
+
+func TestFillAlgorithmsAgree(t *testing.T) {
+	tests := []struct {
+		name  string
+		rings [][]Point
+	}{
+		{name: "square", rings: [][]Point{{{X: 3, Y: 3}, {X: 15, Y: 3}, {X: 15, Y: 15}, {X: 3, Y: 15}}}},
+		{name: "triangle", rings: [][]Point{{{X: 2, Y: 2}, {X: 18, Y: 6}, {X: 6, Y: 17}}}},
+		{name: "concave", rings: [][]Point{{{X: 2, Y: 2}, {X: 18, Y: 2}, {X: 18, Y: 18}, {X: 10, Y: 9}, {X: 2, Y: 18}}}},
+		{name: "square with a hole", rings: [][]Point{
+			{{X: 1, Y: 1}, {X: 19, Y: 1}, {X: 19, Y: 19}, {X: 1, Y: 19}},
+			{{X: 7, Y: 7}, {X: 13, Y: 7}, {X: 13, Y: 13}, {X: 7, Y: 13}},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			edge := testCanvas(21, 21)
+			seed := testCanvas(21, 21)
+			EdgeTableFillRings(edge, tt.rings, testInk)
+			SeedFillRings(seed, tt.rings, testInk)
+
+			want := inkedPixels(edge)
+			if len(want) == 0 {
+				t.Fatalf("edge-table fill painted nothing")
+			}
+			if got := inkedPixels(seed); !reflect.DeepEqual(got, want) {
+				t.Fatalf("seed fill painted %d pixels, edge-table fill painted %d", len(got), len(want))
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package algorithms
+
+import (
+	"image/color"
+	"math"
+	"sort"
+)
+
+
+func SeedFillRings(canvas [][]color.Color, rings [][]Point, fillColor color.Color) {
+	if len(canvas) == 0 || len(canvas[0]) == 0 {
+		return
+	}
+
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, ring := range rings {
+		for _, p := range ring {
+			minX, minY = math.Min(minX, p.X), math.Min(minY, p.Y)
+			maxX, maxY = math.Max(maxX, p.X), math.Max(maxY, p.Y)
+		}
+	}
+	if math.IsInf(minX, 1) {
+		return
+	}
+
+	x0 := int(math.Max(0, math.Floor(minX)))
+	y0 := int(math.Max(0, math.Floor(minY)))
+	x1 := int(math.Min(float64(len(canvas[0])-1), math.Ceil(maxX)))
+	y1 := int(math.Min(float64(len(canvas)-1), math.Ceil(maxY)))
+	if x0 > x1 || y0 > y1 {
+		return
+	}
+
+	w, h := x1-x0+1, y1-y0+1
+	visited := make([]bool, w*h)
+	crossings := make([][]float64, h)
+	scanned := make([]bool, h)
+	inside := func(x, y int) bool {
+		row := y - y0
+		if !scanned[row] {
+			crossings[row] = rowCrossings(rings, float64(y))
+			scanned[row] = true
+		}
+		xs := crossings[row]
+		for i := 0; i+1 < len(xs); i += 2 {
+			if x >= int(math.Round(xs[i])) && x <= int(math.Round(xs[i+1])) {
+				return true
+			}
+		}
+		return false
+	}
+
+	var stack []Pixel
+	for sy := y0; sy <= y1; sy++ {
+		for sx := x0; sx <= x1; sx++ {
+			i := (sy-y0)*w + (sx - x0)
+			if visited[i] || !inside(sx, sy) {
+				continue
+			}
+
+			visited[i] = true
+			stack = append(stack[:0], Pixel{X: sx, Y: sy})
+			for len(stack) > 0 {
+				p := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				canvas[p.Y][p.X] = fillColor
+
+				for _, n := range [4]Pixel{{p.X + 1, p.Y}, {p.X - 1, p.Y}, {p.X, p.Y + 1}, {p.X, p.Y - 1}} {
+					if n.X < x0 || n.X > x1 || n.Y < y0 || n.Y > y1 {
+						continue
+					}
+					j := (n.Y-y0)*w + (n.X - x0)
+					if visited[j] || !inside(n.X, n.Y) {
+						continue
+					}
+					visited[j] = true
+					stack = append(stack, n)
+				}
+			}
+		}
+	}
+}
+
+
+func rowCrossings(rings [][]Point, y float64) []float64 {
+	var xs []float64
+	for _, ring := range rings {
+		if len(ring) < 3 {
+			continue
+		}
+		for i := range ring {
+			a, b := ring[i], ring[(i+1)%len(ring)]
+			if a.Y == b.Y {
+				continue
+			}
+			if a.Y > b.Y {
+				a, b = b, a
+			}
+			if y >= a.Y && y < b.Y {
+				xs = append(xs, a.X+(y-a.Y)*(b.X-a.X)/(b.Y-a.Y))
+			}
+		}
+	}
+	sort.Float64s(xs)
+	return xs
+}
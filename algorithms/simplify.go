@@ -15,8 +15,8 @@ func SimplifyPolygon(points []Point, threshold float64) []Point {
 		prev := result[len(result)-1]
 		current := points[i]
 		
-		dx := float64(current.X - prev.X)
-		dy := float64(current.Y - prev.Y)
+		dx := current.X - prev.X
+		dy := current.Y - prev.Y
 		distSquared := dx*dx + dy*dy
 		if distSquared > threshold*threshold {
 			result = append(result, current)
@@ -27,8 +27,8 @@ func SimplifyPolygon(points []Point, threshold float64) []Point {
 	if len(result) > 2 {
 		last := result[len(result)-1]
 		first := result[0]
-		dx := float64(last.X - first.X)
-		dy := float64(last.Y - first.Y)
+		dx := last.X - first.X
+		dy := last.Y - first.Y
 		distSquared := dx*dx + dy*dy
 		if distSquared < threshold*threshold {
 				result = result[:len(result)-1]
@@ -109,14 +109,14 @@ func onSegment(p, q, r Point) bool {
 		   q.Y <= max(p.Y, r.Y) && q.Y >= min(p.Y, r.Y)
 }
 
-func min(a, b int) int {
+func min(a, b float64) float64 {
 	if a < b {
 		return a
 	}
 	return b
 }
 
-func max(a, b int) int {
+func max(a, b float64) float64 {
 	if a > b {
 		return a
 	}
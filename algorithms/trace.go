@@ -0,0 +1,77 @@
+package algorithms
+
+import (
+	"fmt"
+)
+
+
+type TraceVar struct {
+	Name  string
+	Value string
+}
+
+
+type TraceStep struct {
+	Description string
+	Vars        []TraceVar
+	Pixels      []Pixel
+	Polygon     []Point
+	Edge        []Point
+}
+
+
+type Trace struct {
+	Algorithm string
+	Steps     []TraceStep
+}
+
+
+func (t *Trace) add(step TraceStep) {
+	if t != nil {
+		t.Steps = append(t.Steps, step)
+	}
+}
+
+
+func traceVar(name string, value interface{}) TraceVar {
+	return TraceVar{Name: name, Value: fmt.Sprint(value)}
+}
+
+
+func TraceMidpointLine(x0, y0, x1, y1 int) *Trace {
+	trace := &Trace{Algorithm: "MidpointLine"}
+	midpointPoints(x0, y0, x1, y1, func(x, y int) {}, trace)
+	return trace
+}
+
+
+func TraceMidpointCircle(centerX, centerY, radius int) *Trace {
+	trace := &Trace{Algorithm: "MidpointCircle"}
+	midpointCircle(nil, centerX, centerY, radius, nil, trace)
+	return trace
+}
+
+
+func TraceEdgeTableFill(vertices []Point) *Trace {
+	trace := &Trace{Algorithm: "EdgeTableFill"}
+	if len(vertices) >= 3 {
+		scanlineSpans([][]Point{vertices}, func(y, xStart, xEnd int) {}, trace)
+	}
+	return trace
+}
+
+
+func TraceSutherlandHodgman(subject, clip []Point) *Trace {
+	trace := &Trace{Algorithm: "SutherlandHodgman"}
+	sutherlandHodgman(subject, clip, trace)
+	return trace
+}
+
+
+func spanPixels(y, xStart, xEnd int) []Pixel {
+	pixels := make([]Pixel, 0, xEnd-xStart+1)
+	for x := xStart; x <= xEnd; x++ {
+		pixels = append(pixels, Pixel{X: x, Y: y})
+	}
+	return pixels
+}
@@ -0,0 +1,281 @@
+package bench
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"paint-drawer-pro/algorithms"
+	"runtime"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+
+type Options struct {
+	Sizes  []int
+	Count  int
+	Rounds int
+	Seed   int64
+	Filter string
+}
+
+
+type Result struct {
+	Routine       string  `json:"routine"`
+	Category      string  `json:"category"`
+	CanvasSize    int     `json:"canvasSize"`
+	Ops           int     `json:"ops"`
+	NsPerOp       float64 `json:"nsPerOp"`
+	OpsPerSec     float64 `json:"opsPerSec"`
+	AllocsPerOp   float64 `json:"allocsPerOp"`
+	BytesPerOp    float64 `json:"bytesPerOp"`
+	PixelsPerOp   float64 `json:"pixelsPerOp"`
+	PixelsPerSec  float64 `json:"pixelsPerSec"`
+	VerticesPerOp float64 `json:"verticesPerOp,omitempty"`
+}
+
+
+type routine struct {
+	name     string
+	category string
+	run      func(canvas [][]color.Color, w *workload, i int) int
+	bounds   func(w *workload, i int) image.Rectangle
+}
+
+
+var (
+	background = color.RGBA{255, 255, 255, 255}
+	ink        = color.RGBA{200, 30, 30, 255}
+)
+
+
+func Run(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	format := fs.String("format", "table", "output format: table or json")
+	sizes := fs.String("sizes", "256,1024", "comma-separated square canvas sizes")
+	count := fs.Int("n", 500, "workload items per routine and canvas size")
+	rounds := fs.Int("rounds", 3, "timed passes over each workload")
+	seed := fs.Int64("seed", 1, "random seed for the workloads")
+	filter := fs.String("run", "", "only run routines whose name or category contains this text")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *format != "table" && *format != "json" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	opts := Options{Count: *count, Rounds: *rounds, Seed: *seed, Filter: *filter}
+	for _, field := range strings.Split(*sizes, ",") {
+		size, err := strconv.Atoi(strings.TrimSpace(field))
+		if err != nil || size < 16 {
+			return fmt.Errorf("invalid canvas size %q", field)
+		}
+		opts.Sizes = append(opts.Sizes, size)
+	}
+	if opts.Count <= 0 || opts.Rounds <= 0 {
+		return fmt.Errorf("-n and -rounds must be positive")
+	}
+
+	results := Benchmark(opts)
+	if *format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(results)
+	}
+	return writeTable(out, results)
+}
+
+
+func Benchmark(opts Options) []Result {
+	var results []Result
+	for _, size := range opts.Sizes {
+		w := newWorkload(size, opts.Count, opts.Seed)
+		for _, r := range routines() {
+			if !matches(r, opts.Filter) {
+				continue
+			}
+			results = append(results, measure(r, w, opts.Rounds))
+		}
+	}
+	return results
+}
+
+
+func routines() []routine {
+	var list []routine
+
+	for _, name := range algorithms.LineAlgorithmNames() {
+		line, _ := algorithms.LookupLineAlgorithm(name)
+		list = append(list, routine{
+			name:     name,
+			category: "line",
+			run: func(canvas [][]color.Color, w *workload, i int) int {
+				s := w.segments[i]
+				line(canvas, s[0], s[1], s[2], s[3], ink)
+				return 0
+			},
+			bounds: (*workload).segmentBounds,
+		})
+	}
+	list = append(list, routine{
+		name:     "Thick line (5px)",
+		category: "line",
+		run: func(canvas [][]color.Color, w *workload, i int) int {
+			s := w.segments[i]
+			algorithms.ThickLine(canvas, int(s[0]), int(s[1]), int(s[2]), int(s[3]), ink, 5)
+			return 0
+		},
+		bounds: func(w *workload, i int) image.Rectangle {
+			return w.segmentBounds(i).Inset(-3)
+		},
+	})
+
+	for _, name := range algorithms.CircleAlgorithmNames() {
+		circle, _ := algorithms.LookupCircleAlgorithm(name)
+		list = append(list, routine{
+			name:     name,
+			category: "circle",
+			run: func(canvas [][]color.Color, w *workload, i int) int {
+				c := w.circles[i]
+				circle(canvas, c[0], c[1], c[2], ink)
+				return 0
+			},
+			bounds: (*workload).circleBounds,
+		})
+	}
+
+	for _, name := range algorithms.FillAlgorithmNames() {
+		fill, _ := algorithms.LookupFillAlgorithm(name)
+		list = append(list, routine{
+			name:     name,
+			category: "fill",
+			run: func(canvas [][]color.Color, w *workload, i int) int {
+				fill(canvas, [][]algorithms.Point{w.polygons[i]}, ink)
+				return 0
+			},
+			bounds: (*workload).polygonBounds,
+		})
+	}
+	list = append(list, routine{
+		name:     "Image fill",
+		category: "fill",
+		run: func(canvas [][]color.Color, w *workload, i int) int {
+			algorithms.FillPolygonWithImage(canvas, w.polygons[i], w.texture)
+			return 0
+		},
+		bounds: (*workload).polygonBounds,
+	})
+
+	list = append(list, routine{
+		name:     "Sutherland-Hodgman",
+		category: "clip",
+		run: func(canvas [][]color.Color, w *workload, i int) int {
+			return len(algorithms.SutherlandHodgman(w.polygons[i], w.clips[i]))
+		},
+	})
+
+	return list
+}
+
+
+func matches(r routine, filter string) bool {
+	if filter == "" {
+		return true
+	}
+	filter = strings.ToLower(filter)
+	return strings.Contains(strings.ToLower(r.name), filter) || strings.Contains(r.category, filter)
+}
+
+
+func measure(r routine, w *workload, rounds int) Result {
+	canvas := newCanvas(w.size)
+	n := len(w.segments)
+
+	pixels, vertices := 0, 0
+	bounds := image.Rect(0, 0, w.size, w.size)
+	for i := 0; i < n; i++ {
+		vertices += r.run(canvas, w, i)
+		if r.bounds != nil {
+			pixels += resetTouched(canvas, r.bounds(w, i).Intersect(bounds))
+		}
+	}
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	for round := 0; round < rounds; round++ {
+		for i := 0; i < n; i++ {
+			r.run(canvas, w, i)
+		}
+	}
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	ops := n * rounds
+	result := Result{
+		Routine:       r.name,
+		Category:      r.category,
+		CanvasSize:    w.size,
+		Ops:           ops,
+		NsPerOp:       float64(elapsed.Nanoseconds()) / float64(ops),
+		AllocsPerOp:   float64(after.Mallocs-before.Mallocs) / float64(ops),
+		BytesPerOp:    float64(after.TotalAlloc-before.TotalAlloc) / float64(ops),
+		PixelsPerOp:   float64(pixels) / float64(n),
+		VerticesPerOp: float64(vertices) / float64(n),
+	}
+	if seconds := elapsed.Seconds(); seconds > 0 {
+		result.OpsPerSec = float64(ops) / seconds
+		result.PixelsPerSec = result.PixelsPerOp * result.OpsPerSec
+	}
+	return result
+}
+
+
+func newCanvas(size int) [][]color.Color {
+	canvas := make([][]color.Color, size)
+	for y := range canvas {
+		canvas[y] = make([]color.Color, size)
+		for x := range canvas[y] {
+			canvas[y][x] = background
+		}
+	}
+	return canvas
+}
+
+
+func resetTouched(canvas [][]color.Color, r image.Rectangle) int {
+	touched := 0
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if canvas[y][x] != color.Color(background) {
+				touched++
+				canvas[y][x] = background
+			}
+		}
+	}
+	return touched
+}
+
+
+func writeTable(out io.Writer, results []Result) error {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ROUTINE\tCATEGORY\tCANVAS\tOPS\tNS/OP\tOPS/S\tALLOCS/OP\tB/OP\tOUTPUT/OP\tMPX/S")
+	for _, r := range results {
+		output := fmt.Sprintf("%.1f px", r.PixelsPerOp)
+		throughput := fmt.Sprintf("%.2f", r.PixelsPerSec/1e6)
+		if r.Category == "clip" {
+			output = fmt.Sprintf("%.1f verts", r.VerticesPerOp)
+			throughput = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%.0f\t%.0f\t%.1f\t%.0f\t%s\t%s\n",
+			r.Routine, r.Category, r.CanvasSize, r.Ops, r.NsPerOp, r.OpsPerSec,
+			r.AllocsPerOp, r.BytesPerOp, output, throughput)
+	}
+	return tw.Flush()
+}
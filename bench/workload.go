@@ -0,0 +1,100 @@
+package bench
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+	"paint-drawer-pro/algorithms"
+)
+
+
+type workload struct {
+	size     int
+	segments [][4]float64
+	circles  [][3]float64
+	polygons [][]algorithms.Point
+	clips    [][]algorithms.Point
+	texture  [][]color.Color
+}
+
+
+func newWorkload(size, count int, seed int64) *workload {
+	rng := rand.New(rand.NewSource(seed + int64(size)))
+	w := &workload{size: size}
+	extent := float64(size - 1)
+
+	for i := 0; i < count; i++ {
+		w.segments = append(w.segments, [4]float64{
+			rng.Float64() * extent, rng.Float64() * extent,
+			rng.Float64() * extent, rng.Float64() * extent,
+		})
+
+		radius := 2 + rng.Float64()*extent/4
+		w.circles = append(w.circles, [3]float64{
+			radius + rng.Float64()*(extent-2*radius),
+			radius + rng.Float64()*(extent-2*radius),
+			radius,
+		})
+
+		polyRadius := 4 + rng.Float64()*extent/3
+		cx := polyRadius + rng.Float64()*(extent-2*polyRadius)
+		cy := polyRadius + rng.Float64()*(extent-2*polyRadius)
+		w.polygons = append(w.polygons, randomPolygon(rng, cx, cy, polyRadius, 3+rng.Intn(10), false))
+		w.clips = append(w.clips, randomPolygon(rng, cx+polyRadius/2, cy, polyRadius*0.8, 3+rng.Intn(6), true))
+	}
+
+	w.texture = make([][]color.Color, 16)
+	for y := range w.texture {
+		w.texture[y] = make([]color.Color, 16)
+		for x := range w.texture[y] {
+			w.texture[y][x] = color.RGBA{uint8(x * 16), uint8(y * 16), 128, 255}
+		}
+	}
+	return w
+}
+
+
+func randomPolygon(rng *rand.Rand, cx, cy, radius float64, vertices int, convex bool) []algorithms.Point {
+	points := make([]algorithms.Point, vertices)
+	for i := range points {
+		angle := -2 * math.Pi * float64(i) / float64(vertices)
+		r := radius
+		if !convex {
+			r = radius * (0.3 + 0.7*rng.Float64())
+		}
+		points[i] = algorithms.Point{X: cx + r*math.Cos(angle), Y: cy + r*math.Sin(angle)}
+	}
+	return points
+}
+
+
+func (w *workload) segmentBounds(i int) image.Rectangle {
+	s := w.segments[i]
+	return pointBounds([]algorithms.Point{{X: s[0], Y: s[1]}, {X: s[2], Y: s[3]}}, 2)
+}
+
+
+func (w *workload) circleBounds(i int) image.Rectangle {
+	c := w.circles[i]
+	return pointBounds([]algorithms.Point{{X: c[0] - c[2], Y: c[1] - c[2]}, {X: c[0] + c[2], Y: c[1] + c[2]}}, 2)
+}
+
+
+func (w *workload) polygonBounds(i int) image.Rectangle {
+	return pointBounds(w.polygons[i], 1)
+}
+
+
+func pointBounds(points []algorithms.Point, margin int) image.Rectangle {
+	r := image.Rectangle{}
+	for i, p := range points {
+		pr := image.Rect(int(math.Floor(p.X)), int(math.Floor(p.Y)), int(math.Ceil(p.X))+1, int(math.Ceil(p.Y))+1)
+		if i == 0 {
+			r = pr
+		} else {
+			r = r.Union(pr)
+		}
+	}
+	return r.Inset(-margin)
+}
@@ -0,0 +1,90 @@
+package editor
+
+import (
+	"fmt"
+	"paint-drawer-pro/algorithms"
+	"paint-drawer-pro/models"
+)
+
+
+func ClipVertices(subject, clip []models.Point) []models.Point {
+	
+	subjectPoints := make([]algorithms.Point, len(subject))
+	for i, p := range subject {
+		subjectPoints[i] = algorithms.Point{X: p.X, Y: p.Y}
+	}
+	
+	clipPoints := make([]algorithms.Point, len(clip))
+	for i, p := range clip {
+		clipPoints[i] = algorithms.Point{X: p.X, Y: p.Y}
+	}
+	
+	
+	subjectPoints = algorithms.SimplifyPolygon(subjectPoints, 2.0) 
+	clipPoints = algorithms.SimplifyPolygon(clipPoints, 2.0)
+	
+	
+	algClippedPoints := algorithms.SutherlandHodgman(subjectPoints, clipPoints)
+	
+	
+	clippedVertices := make([]models.Point, len(algClippedPoints))
+	for i, p := range algClippedPoints {
+		clippedVertices[i] = models.Point{X: p.X, Y: p.Y}
+	}
+	
+	return clippedVertices
+}
+
+
+func CheckClipper(shape models.Shape) error {
+	if shape == nil {
+		return fmt.Errorf("Please select a polygon to clip first.")
+	}
+	polygon, isPolygon := shape.(*models.Polygon)
+	if !isPolygon {
+		return fmt.Errorf("Only polygons can be clipped. Please select a polygon.")
+	}
+
+	vertices := polygon.GetVertices()
+	algVertices := make([]algorithms.Point, len(vertices))
+	for i, v := range vertices {
+		algVertices[i] = algorithms.Point{X: v.X, Y: v.Y}
+	}
+
+	simplified := algorithms.SimplifyPolygon(algVertices, 2.0)
+	if !algorithms.IsPolygonConvex(simplified) {
+		return fmt.Errorf("Only convex polygons can be used for clipping. Selected polygon has %d vertices (simplified from %d).",
+			len(simplified), len(vertices))
+	}
+	return nil
+}
+
+
+func (e *Editor) ClipPolygon(subject models.Shape, clipper *models.Polygon) error {
+	polygon, isPolygon := subject.(*models.Polygon)
+	if !isPolygon {
+		return fmt.Errorf("Clipping only works with polygons. Please select a polygon.")
+	}
+	if clipper == nil || !clipper.IsConvex() {
+		return fmt.Errorf("Only convex polygons can be used as clippers.")
+	}
+	if !polygon.IsConvex() {
+		return fmt.Errorf("Only convex polygons can be clipped.")
+	}
+
+	clippedVertices := ClipVertices(polygon.GetVertices(), clipper.GetVertices())
+	if len(clippedVertices) < 3 {
+		return fmt.Errorf("Clipping result is not a valid polygon.")
+	}
+
+	clippedPoly := models.NewPolygon(clippedVertices, polygon.GetColor(), polygon.Thickness)
+	if polygon.IsFilled {
+		if polygon.UseImage {
+			clippedPoly.SetFillImage(polygon.FillImage)
+		} else {
+			clippedPoly.SetFillColor(polygon.FillColor)
+		}
+	}
+	e.AddShape(clippedPoly)
+	return nil
+}
@@ -0,0 +1,29 @@
+package editor
+
+import (
+	"paint-drawer-pro/models"
+)
+
+
+func (e *Editor) Save(filePath string) error {
+	return models.SaveDocument(filePath, e.State)
+}
+
+
+func (e *Editor) Load(filePath string) (*models.LoadReport, error) {
+	doc, report, err := models.LoadDocument(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	e.State.Shapes = doc.Shapes
+	e.State.Layers = doc.Layers
+	e.State.ActiveLayer = len(doc.Layers) - 1
+	e.State.Raster = doc.Raster
+	e.State.CurrentShape = nil
+	e.State.SelectedVertex = -1
+	e.State.ClearSelection()
+	e.History.Clear()
+	e.Notify(DocumentChanged|HistoryChanged, "Load drawing")
+	return report, nil
+}
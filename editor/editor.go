@@ -0,0 +1,161 @@
+package editor
+
+import (
+	"image/color"
+	"paint-drawer-pro/models"
+)
+
+
+type ChangeKind int
+
+const (
+	DocumentChanged ChangeKind = 1 << iota
+	SelectionChanged
+	ToolChanged
+	HistoryChanged
+	PreviewChanged
+	SettingsChanged
+)
+
+
+type Change struct {
+	Kind  ChangeKind
+	Label string
+}
+
+
+func (c Change) Has(kind ChangeKind) bool {
+	return c.Kind&kind != 0
+}
+
+
+type Editor struct {
+	State     *models.DrawingState
+	History   *models.History
+	listeners []func(Change)
+}
+
+
+func New() *Editor {
+	return &Editor{
+		State: &models.DrawingState{
+			Shapes:         []models.Shape{},
+			CurrentAction:  "line",
+			SelectedVertex: -1,
+			AntiAliasing:   true,
+			PenType:        "brush",
+			BrushThickness: 3,
+			CurrentColor:   color.RGBA{0, 0, 0, 255},
+			FillEnabled:    false,
+			FillColor:      color.RGBA{255, 255, 255, 255},
+			UseImageFill:   false,
+			Layers:         []*models.Layer{models.NewLayer("Layer 1")},
+		},
+		History: models.NewHistory(models.DefaultHistoryBudget),
+	}
+}
+
+
+func (e *Editor) Subscribe(listener func(Change)) {
+	e.listeners = append(e.listeners, listener)
+}
+
+
+func (e *Editor) Notify(kind ChangeKind, label string) {
+	change := Change{Kind: kind, Label: label}
+	for _, listener := range e.listeners {
+		listener(change)
+	}
+}
+
+
+func (e *Editor) SetTool(action string) {
+	e.State.CurrentAction = action
+	e.State.SelectedVertex = -1
+	e.Notify(ToolChanged, action)
+}
+
+
+func (e *Editor) Select(shapes ...models.Shape) {
+	e.State.SetSelection(shapes)
+	e.State.SelectedVertex = -1
+	e.Notify(SelectionChanged, "")
+}
+
+
+func (e *Editor) SelectAll() {
+	e.State.SelectAll()
+	e.Notify(SelectionChanged, "")
+}
+
+
+func (e *Editor) ClearSelection() {
+	e.State.ClearSelection()
+	e.State.SelectedVertex = -1
+	e.Notify(SelectionChanged, "")
+}
+
+
+func (e *Editor) ToggleSelection(shape models.Shape) {
+	e.State.ToggleSelection(shape)
+	e.Notify(SelectionChanged, "")
+}
+
+
+func (e *Editor) Deselect(shape models.Shape) {
+	e.State.Deselect(shape)
+	e.Notify(SelectionChanged, "")
+}
+
+
+func (e *Editor) Focus(shape models.Shape) {
+	if !e.State.IsSelected(shape) {
+		e.State.Select(shape)
+	}
+	e.State.SelectedShape = shape
+	e.Notify(SelectionChanged, "")
+}
+
+
+func (e *Editor) SelectVertex(index int) {
+	e.State.SelectedVertex = index
+	e.Notify(SelectionChanged, "")
+}
+
+
+func (e *Editor) SetActiveLayer(index int) {
+	if index < 0 || index >= len(e.State.Layers) {
+		return
+	}
+	e.State.ActiveLayer = index
+	e.Notify(SelectionChanged, "")
+}
+
+
+func (e *Editor) Preview() models.Shape {
+	return e.State.CurrentShape
+}
+
+
+func (e *Editor) SetPreview(shape models.Shape) {
+	e.State.CurrentShape = shape
+	e.Notify(PreviewChanged, "")
+}
+
+
+func (e *Editor) UpdatePreview() {
+	e.Notify(PreviewChanged, "")
+}
+
+
+func (e *Editor) CommitPreview() models.Shape {
+	shape := e.State.CurrentShape
+	if shape == nil {
+		return nil
+	}
+	index := e.State.ActiveInsertIndex()
+	e.State.CurrentShape = nil
+	e.AddShape(shape)
+	e.Notify(PreviewChanged, "")
+	return e.State.Shapes[index]
+}
@@ -0,0 +1,218 @@
+package editor
+
+import (
+	"image/color"
+	"paint-drawer-pro/models"
+	"reflect"
+	"testing"
+)
+
+
+func recordChanges(e *Editor) *[]Change {
+	var changes []Change
+	e.Subscribe(func(c Change) {
+		changes = append(changes, c)
+	})
+	return &changes
+}
+
+
+func expectChange(t *testing.T, changes *[]Change, kind ChangeKind) {
+	t.Helper()
+	if len(*changes) == 0 {
+		t.Fatalf("expected a change of kind %d, got none", kind)
+	}
+	last := (*changes)[len(*changes)-1]
+	if !last.Has(kind) {
+		t.Fatalf("expected a change of kind %d, got %d", kind, last.Kind)
+	}
+	*changes = nil
+}
+
+
+func testLine(x float64) *models.Line {
+	return models.NewLine(models.Point{X: x, Y: 0}, models.Point{X: x + 10, Y: 10}, color.RGBA{0, 0, 0, 255}, 1, "regular")
+}
+
+
+func TestSelectionEmitsSelectionChanged(t *testing.T) {
+	e := New()
+	e.AddShape(testLine(0))
+	e.AddShape(testLine(20))
+	a, b := e.State.Shapes[0], e.State.Shapes[1]
+	changes := recordChanges(e)
+
+	e.Select(a)
+	expectChange(t, changes, SelectionChanged)
+	if e.State.SelectedShape != a || len(e.State.Selection) != 1 {
+		t.Fatalf("Select: selection = %v, primary = %v", e.State.Selection, e.State.SelectedShape)
+	}
+
+	e.ToggleSelection(b)
+	expectChange(t, changes, SelectionChanged)
+	if !e.State.IsSelected(a) || !e.State.IsSelected(b) {
+		t.Fatalf("ToggleSelection did not add the shape")
+	}
+
+	e.Focus(a)
+	expectChange(t, changes, SelectionChanged)
+	if e.State.SelectedShape != a || len(e.State.Selection) != 2 {
+		t.Fatalf("Focus changed the selection instead of the primary shape")
+	}
+
+	e.Deselect(a)
+	expectChange(t, changes, SelectionChanged)
+	if e.State.IsSelected(a) || e.State.SelectedShape != b {
+		t.Fatalf("Deselect left %v selected", a)
+	}
+
+	e.SelectAll()
+	expectChange(t, changes, SelectionChanged)
+	if len(e.State.Selection) != 2 {
+		t.Fatalf("SelectAll selected %d shapes, want 2", len(e.State.Selection))
+	}
+
+	e.SelectVertex(1)
+	expectChange(t, changes, SelectionChanged)
+
+	e.ClearSelection()
+	expectChange(t, changes, SelectionChanged)
+	if e.State.SelectedShape != nil || len(e.State.Selection) != 0 || e.State.SelectedVertex != -1 {
+		t.Fatalf("ClearSelection left selection state behind")
+	}
+}
+
+
+func TestSetToolEmitsToolChanged(t *testing.T) {
+	e := New()
+	e.State.SelectedVertex = 2
+	changes := recordChanges(e)
+
+	e.SetTool("circle")
+	expectChange(t, changes, ToolChanged)
+	if e.State.CurrentAction != "circle" || e.State.SelectedVertex != -1 {
+		t.Fatalf("SetTool: action = %q, vertex = %d", e.State.CurrentAction, e.State.SelectedVertex)
+	}
+}
+
+
+func TestSettingsEmitSettingsChanged(t *testing.T) {
+	e := New()
+	changes := recordChanges(e)
+	red := color.RGBA{255, 0, 0, 255}
+
+	e.SetPenType("regular")
+	expectChange(t, changes, SettingsChanged)
+	e.SetBrushThickness(7)
+	expectChange(t, changes, SettingsChanged)
+	e.SetColor(red)
+	expectChange(t, changes, SettingsChanged)
+	e.SetFillEnabled(true)
+	expectChange(t, changes, SettingsChanged)
+	e.SetFillColor(red)
+	expectChange(t, changes, SettingsChanged)
+	e.SetAntiAliasing(false)
+	expectChange(t, changes, SettingsChanged)
+
+	state := e.State
+	if state.PenType != "regular" || state.BrushThickness != 7 || state.CurrentColor != red ||
+		!state.FillEnabled || state.FillColor != red || state.AntiAliasing {
+		t.Fatalf("settings were not applied: %+v", state)
+	}
+
+	e.SetFillImage([][]color.Color{{red}})
+	expectChange(t, changes, SettingsChanged)
+	if !state.UseImageFill {
+		t.Fatalf("SetFillImage did not enable the image fill")
+	}
+	e.SetFillImage(nil)
+	if state.UseImageFill {
+		t.Fatalf("SetFillImage(nil) left the image fill enabled")
+	}
+}
+
+
+func TestPreviewEmitsPreviewChanged(t *testing.T) {
+	e := New()
+	changes := recordChanges(e)
+	line := testLine(0)
+
+	e.SetPreview(line)
+	expectChange(t, changes, PreviewChanged)
+	if e.Preview() != line {
+		t.Fatalf("Preview() = %v, want %v", e.Preview(), line)
+	}
+
+	line.End = models.Point{X: 50, Y: 50}
+	e.UpdatePreview()
+	expectChange(t, changes, PreviewChanged)
+
+	committed := e.CommitPreview()
+	if !reflect.DeepEqual(committed, line) {
+		t.Fatalf("CommitPreview returned %v, want %v", committed, line)
+	}
+	if e.Preview() != nil || len(e.State.Shapes) != 1 || e.State.Shapes[0] != committed {
+		t.Fatalf("CommitPreview did not move the preview into the document")
+	}
+	kinds := ChangeKind(0)
+	for _, c := range *changes {
+		kinds |= c.Kind
+	}
+	if kinds&(DocumentChanged|PreviewChanged) != DocumentChanged|PreviewChanged {
+		t.Fatalf("CommitPreview emitted kinds %d", kinds)
+	}
+
+	*changes = nil
+	if e.CommitPreview() != nil || len(*changes) != 0 {
+		t.Fatalf("CommitPreview without a preview should be a no-op")
+	}
+}
+
+
+func TestSetPillLength(t *testing.T) {
+	e := New()
+	pill := models.NewPill(models.Point{X: 0, Y: 0}, 5, color.RGBA{0, 0, 0, 255})
+	pill.End = models.Point{X: 100, Y: 0}
+	pill.Step = 2
+	changes := recordChanges(e)
+
+	e.SetPreview(pill)
+	e.SetPillLength(pill, 40)
+	expectChange(t, changes, PreviewChanged)
+	if pill.End.X != 40 || e.History.CanUndo() {
+		t.Fatalf("preview pill: end = %v, undoable = %v", pill.End, e.History.CanUndo())
+	}
+
+	placed := e.CommitPreview().(*models.Pill)
+	e.History.Clear()
+	*changes = nil
+
+	e.SetPillLength(placed, 80)
+	expectChange(t, changes, DocumentChanged|HistoryChanged)
+	if placed.End.X != 80 || !e.History.CanUndo() {
+		t.Fatalf("placed pill: end = %v, undoable = %v", placed.End, e.History.CanUndo())
+	}
+
+	e.Undo()
+	if got := e.State.Shapes[0].(*models.Pill).End.X; got != 40 {
+		t.Fatalf("undo restored end.X = %v, want 40", got)
+	}
+}
+
+
+func TestSetActiveLayer(t *testing.T) {
+	e := New()
+	e.State.Layers = append(e.State.Layers, models.NewLayer("Layer 2"))
+	changes := recordChanges(e)
+
+	e.SetActiveLayer(1)
+	expectChange(t, changes, SelectionChanged)
+	if e.State.ActiveLayer != 1 {
+		t.Fatalf("ActiveLayer = %d, want 1", e.State.ActiveLayer)
+	}
+
+	e.SetActiveLayer(5)
+	if e.State.ActiveLayer != 1 || len(*changes) != 0 {
+		t.Fatalf("SetActiveLayer accepted an out-of-range index")
+	}
+}
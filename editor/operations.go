@@ -0,0 +1,162 @@
+package editor
+
+import (
+	"fmt"
+	"paint-drawer-pro/models"
+	"reflect"
+	"sort"
+)
+
+
+func (e *Editor) Execute(cmd models.Command) {
+	e.History.Execute(e.State, cmd)
+	e.Notify(DocumentChanged|HistoryChanged, cmd.Name())
+}
+
+
+func (e *Editor) AddShape(shape models.Shape) {
+	e.Execute(models.NewAddShapeCommand(e.State, shape))
+}
+
+
+func (e *Editor) AddShapes(shapes []models.Shape, label string) {
+	if len(shapes) == 1 {
+		e.AddShape(shapes[0])
+		return
+	}
+
+	batch := &models.BatchCommand{Label: label}
+	for i, shape := range shapes {
+		cmd := models.NewAddShapeCommand(e.State, shape)
+		cmd.Index += i
+		batch.Commands = append(batch.Commands, cmd)
+	}
+	e.Execute(batch)
+}
+
+
+func (e *Editor) RemoveShapes(shapes []models.Shape) {
+	var removals []*models.RemoveShapeCommand
+	for _, shape := range shapes {
+		if models.IndexOfShape(e.State.Shapes, shape) >= 0 {
+			removals = append(removals, models.NewRemoveShapeCommand(e.State, shape))
+		}
+	}
+	if len(removals) == 0 {
+		return
+	}
+	if len(removals) == 1 {
+		e.Execute(removals[0])
+		return
+	}
+
+	sort.Slice(removals, func(i, j int) bool {
+		return removals[i].Index > removals[j].Index
+	})
+	batch := &models.BatchCommand{Label: fmt.Sprintf("Delete %d shapes", len(removals))}
+	for _, cmd := range removals {
+		batch.Commands = append(batch.Commands, cmd)
+	}
+	e.Execute(batch)
+}
+
+
+func (e *Editor) ReplaceShape(old, replacement models.Shape, label string) {
+	index := models.IndexOfShape(e.State.Shapes, old)
+	if index < 0 {
+		return
+	}
+	e.Execute(models.NewReplaceShapeCommand(index, old, replacement, label))
+}
+
+
+func (e *Editor) RecordEdit(index int, before models.Shape, label string) {
+	e.RecordKeyedEdit(index, before, label, "")
+}
+
+
+func (e *Editor) RecordEdits(indices []int, befores []models.Shape, label string) {
+	var edits []models.Command
+	for i, index := range indices {
+		if index < 0 || index >= len(e.State.Shapes) || reflect.DeepEqual(befores[i], e.State.Shapes[index]) {
+			continue
+		}
+		edits = append(edits, models.NewReplaceShapeCommand(index, befores[i], e.State.Shapes[index], label))
+	}
+
+	switch len(edits) {
+	case 0:
+		return
+	case 1:
+		e.History.Record(edits[0])
+	default:
+		e.History.Record(&models.BatchCommand{Commands: edits, Label: label})
+	}
+	e.Notify(DocumentChanged|HistoryChanged, label)
+}
+
+
+func (e *Editor) RecordKeyedEdit(index int, before models.Shape, label, key string) {
+	if before == nil || index < 0 || index >= len(e.State.Shapes) {
+		return
+	}
+	after := e.State.Shapes[index]
+	if reflect.DeepEqual(before, after) {
+		return
+	}
+
+	cmd := models.NewReplaceShapeCommand(index, before, after, label)
+	cmd.Key = key
+	e.History.Record(cmd)
+	e.Notify(DocumentChanged|HistoryChanged, label)
+}
+
+
+func (e *Editor) EditShape(shape models.Shape, label string, edit func()) {
+	e.EditShapes([]models.Shape{shape}, label, edit)
+}
+
+
+func (e *Editor) EditShapes(shapes []models.Shape, label string, edit func()) {
+	indices, befores := e.SnapshotShapes(shapes)
+	edit()
+	e.RecordEdits(indices, befores, label)
+}
+
+
+func (e *Editor) SnapshotShapes(shapes []models.Shape) ([]int, []models.Shape) {
+	var indices []int
+	var befores []models.Shape
+	for _, shape := range shapes {
+		if index := models.IndexOfShape(e.State.Shapes, shape); index >= 0 {
+			indices = append(indices, index)
+			befores = append(befores, shape.Clone())
+		}
+	}
+	return indices, befores
+}
+
+
+func (e *Editor) Undo() (models.Command, bool) {
+	cmd, ok := e.History.Undo(e.State)
+	if ok {
+		e.State.SelectedVertex = -1
+		e.Notify(DocumentChanged|HistoryChanged, cmd.Name())
+	}
+	return cmd, ok
+}
+
+
+func (e *Editor) Redo() (models.Command, bool) {
+	cmd, ok := e.History.Redo(e.State)
+	if ok {
+		e.State.SelectedVertex = -1
+		e.Notify(DocumentChanged|HistoryChanged, cmd.Name())
+	}
+	return cmd, ok
+}
+
+
+func (e *Editor) Clear() {
+	e.Execute(models.NewSetShapesCommand(e.State, []models.Shape{}, "Clear all"))
+}
@@ -0,0 +1,214 @@
+package editor
+
+import (
+	"image/color"
+	"path/filepath"
+	"paint-drawer-pro/models"
+	"reflect"
+	"testing"
+)
+
+
+func expectShapes(t *testing.T, e *Editor, want ...models.Shape) {
+	t.Helper()
+	if len(e.State.Shapes) != len(want) {
+		t.Fatalf("document has %d shapes, want %d", len(e.State.Shapes), len(want))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(e.State.Shapes[i], want[i]) {
+			t.Fatalf("shape %d = %v, want %v", i, e.State.Shapes[i], want[i])
+		}
+	}
+}
+
+
+func TestOperationsUndoRedo(t *testing.T) {
+	a, b, c := testLine(0), testLine(20), testLine(40)
+	replacement := testLine(60)
+
+	tests := []struct {
+		name  string
+		setup []models.Shape
+		run   func(e *Editor)
+		after []models.Shape
+	}{
+		{
+			name:  "add shape",
+			run:   func(e *Editor) { e.AddShape(a) },
+			after: []models.Shape{a},
+		},
+		{
+			name:  "add shapes",
+			run:   func(e *Editor) { e.AddShapes([]models.Shape{a, b}, "Paste 2 shapes") },
+			after: []models.Shape{a, b},
+		},
+		{
+			name:  "remove shapes",
+			setup: []models.Shape{a, b, c},
+			run:   func(e *Editor) { e.RemoveShapes([]models.Shape{e.State.Shapes[0], e.State.Shapes[2]}) },
+			after: []models.Shape{b},
+		},
+		{
+			name:  "replace shape",
+			setup: []models.Shape{a, b},
+			run:   func(e *Editor) { e.ReplaceShape(e.State.Shapes[1], replacement, "Replace") },
+			after: []models.Shape{a, replacement},
+		},
+		{
+			name:  "clear",
+			setup: []models.Shape{a, b},
+			run:   func(e *Editor) { e.Clear() },
+			after: []models.Shape{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New()
+			for _, shape := range tt.setup {
+				e.AddShape(shape)
+			}
+			e.History.Clear()
+			changes := recordChanges(e)
+
+			tt.run(e)
+			expectChange(t, changes, DocumentChanged|HistoryChanged)
+			expectShapes(t, e, tt.after...)
+
+			if _, ok := e.Undo(); !ok {
+				t.Fatalf("Undo failed")
+			}
+			expectChange(t, changes, DocumentChanged|HistoryChanged)
+			expectShapes(t, e, tt.setup...)
+
+			if _, ok := e.Redo(); !ok {
+				t.Fatalf("Redo failed")
+			}
+			expectChange(t, changes, DocumentChanged|HistoryChanged)
+			expectShapes(t, e, tt.after...)
+		})
+	}
+}
+
+
+func TestEditShapeRecordsUndo(t *testing.T) {
+	e := New()
+	e.AddShape(testLine(0))
+	line := e.State.Shapes[0]
+	changes := recordChanges(e)
+
+	e.EditShape(line, "Move line", func() {
+		line.Move(5, 5)
+	})
+	expectChange(t, changes, DocumentChanged|HistoryChanged)
+
+	e.Undo()
+	if got := e.State.Shapes[0].(*models.Line).Start; got != (models.Point{X: 0, Y: 0}) {
+		t.Fatalf("undo restored start %v, want the origin", got)
+	}
+	e.Redo()
+	if got := e.State.Shapes[0].(*models.Line).Start; got != (models.Point{X: 5, Y: 5}) {
+		t.Fatalf("redo restored start %v, want (5, 5)", got)
+	}
+}
+
+
+func TestEditShapeWithoutChangeRecordsNothing(t *testing.T) {
+	e := New()
+	e.AddShape(testLine(0))
+	line := e.State.Shapes[0]
+	e.History.Clear()
+	changes := recordChanges(e)
+
+	e.EditShape(line, "No-op", func() {})
+	if e.History.CanUndo() || len(*changes) != 0 {
+		t.Fatalf("an unchanged edit was recorded")
+	}
+}
+
+
+func TestUndoResetsSelectedVertex(t *testing.T) {
+	e := New()
+	e.AddShape(testLine(0))
+	e.SelectVertex(1)
+
+	e.Undo()
+	if e.State.SelectedVertex != -1 {
+		t.Fatalf("SelectedVertex = %d after undo, want -1", e.State.SelectedVertex)
+	}
+}
+
+
+func TestUndoWithEmptyHistory(t *testing.T) {
+	e := New()
+	changes := recordChanges(e)
+
+	if _, ok := e.Undo(); ok {
+		t.Fatalf("Undo succeeded with an empty history")
+	}
+	if _, ok := e.Redo(); ok {
+		t.Fatalf("Redo succeeded with an empty history")
+	}
+	if len(*changes) != 0 {
+		t.Fatalf("empty undo/redo emitted %d changes", len(*changes))
+	}
+}
+
+
+func TestSaveLoadRoundTrip(t *testing.T) {
+	e := New()
+	e.AddShape(testLine(0))
+	e.AddShape(models.NewCircle(models.Point{X: 50, Y: 50}, 20, color.RGBA{255, 0, 0, 255}))
+
+	path := filepath.Join(t.TempDir(), "drawing.json")
+	if err := e.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded := New()
+	changes := recordChanges(loaded)
+	report, err := loaded.Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	expectChange(t, changes, DocumentChanged)
+	if !report.Empty() {
+		t.Fatalf("clean document reported issues: %s", report.Summary())
+	}
+	if len(loaded.State.Shapes) != 2 {
+		t.Fatalf("loaded %d shapes, want 2", len(loaded.State.Shapes))
+	}
+}
+
+
+func TestLoadClearsHistory(t *testing.T) {
+	e := New()
+	e.AddShape(testLine(0))
+	path := filepath.Join(t.TempDir(), "drawing.json")
+	if err := e.Save(path); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	e.AddShape(testLine(20))
+	e.AddShape(testLine(40))
+	changes := recordChanges(e)
+	if _, err := e.Load(path); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(*changes) != 1 || (*changes)[0].Kind != DocumentChanged|HistoryChanged {
+		t.Fatalf("Load emitted %v, want one DocumentChanged|HistoryChanged change", *changes)
+	}
+
+	if e.History.CanUndo() || e.History.CanRedo() {
+		t.Fatalf("history survived Load: undo %v, redo %v", e.History.CanUndo(), e.History.CanRedo())
+	}
+	if e.History.MemoryUsed() != 0 {
+		t.Fatalf("history still holds %d bytes after Load", e.History.MemoryUsed())
+	}
+	if _, ok := e.Undo(); ok {
+		t.Fatalf("Undo succeeded right after Load")
+	}
+	if len(e.State.Shapes) != 1 {
+		t.Fatalf("loaded %d shapes, want 1", len(e.State.Shapes))
+	}
+}
@@ -0,0 +1,42 @@
+package editor
+
+import (
+	"math"
+	"paint-drawer-pro/models"
+)
+
+
+func (e *Editor) SetPillLength(pill *models.Pill, length int) {
+	dirX, dirY, currentLength := pillDirection(pill)
+	if currentLength <= 0 {
+		return
+	}
+	
+	if models.Shape(pill) == e.State.CurrentShape {
+		pill.End.X = pill.Start.X + dirX * float64(length)
+		pill.End.Y = pill.Start.Y + dirY * float64(length)
+		e.Notify(PreviewChanged, "")
+		return
+	}
+	
+	index := models.IndexOfShape(e.State.Shapes, pill)
+	if index < 0 || math.Abs(currentLength-float64(length)) < 0.5 {
+		return
+	}
+	
+	before := pill.Clone()
+	pill.End.X = pill.Start.X + dirX * float64(length)
+	pill.End.Y = pill.Start.Y + dirY * float64(length)
+	e.RecordKeyedEdit(index, before, "Change pill length", "pill-length")
+}
+
+
+func pillDirection(pill *models.Pill) (float64, float64, float64) {
+	dx := pill.End.X - pill.Start.X
+	dy := pill.End.Y - pill.Start.Y
+	length := math.Sqrt(dx*dx + dy*dy)
+	if length == 0 {
+		return 0, 0, 0
+	}
+	return dx / length, dy / length, length
+}
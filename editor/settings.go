@@ -0,0 +1,55 @@
+package editor
+
+import (
+	"image/color"
+	"paint-drawer-pro/models"
+)
+
+
+func (e *Editor) SetAntiAliasing(enabled bool) {
+	e.State.AntiAliasing = enabled
+	e.Notify(SettingsChanged, "anti-aliasing")
+}
+
+
+func (e *Editor) SetPenType(penType string) {
+	e.State.PenType = penType
+	e.Notify(SettingsChanged, "pen type")
+}
+
+
+func (e *Editor) SetBrushThickness(thickness int) {
+	e.State.BrushThickness = thickness
+	e.Notify(SettingsChanged, "brush thickness")
+}
+
+
+func (e *Editor) SetColor(c color.RGBA) {
+	e.State.CurrentColor = c
+	e.Notify(SettingsChanged, "color")
+}
+
+
+func (e *Editor) SetFillEnabled(enabled bool) {
+	e.State.FillEnabled = enabled
+	e.Notify(SettingsChanged, "fill")
+}
+
+
+func (e *Editor) SetFillColor(c color.Color) {
+	e.State.FillColor = c
+	e.Notify(SettingsChanged, "fill color")
+}
+
+
+func (e *Editor) SetFillImage(img [][]color.Color) {
+	e.State.FillImage = img
+	e.State.UseImageFill = img != nil
+	e.Notify(SettingsChanged, "fill image")
+}
+
+
+func (e *Editor) SetRaster(raster models.RasterAlgorithms) {
+	e.State.Raster = raster
+	e.Notify(SettingsChanged, "rasterization algorithms")
+}
@@ -2,7 +2,10 @@ module paint-drawer-pro
 
 go 1.24.0
 
-require fyne.io/fyne/v2 v2.6.0
+require (
+	fyne.io/fyne/v2 v2.6.0
+	golang.design/x/clipboard v0.7.0
+)
 
 require (
 	fyne.io/systray v1.11.0 
@@ -32,6 +35,7 @@ require (
 	github.com/srwiley/rasterx v0.0.0-20220730225603-2ab79fcdd4ef 
 	github.com/stretchr/testify v1.10.0 
 	github.com/yuin/goldmark v1.7.8 
+	golang.org/x/exp v0.0.0-20190731235908-ec7cb31e5a56 
 	golang.org/x/image v0.24.0 
 	golang.org/x/net v0.35.0 
 	golang.org/x/sys v0.30.0 
@@ -1,6 +1,11 @@
 package main
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"paint-drawer-pro/bench"
+	"paint-drawer-pro/render"
 	"paint-drawer-pro/ui"
 
 	"fyne.io/fyne/v2"
@@ -9,6 +14,25 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		var run func([]string, io.Writer) error
+		switch os.Args[1] {
+		case "bench":
+			run = bench.Run
+		case "render":
+			run = func(args []string, out io.Writer) error {
+				return render.Run(args, out, os.Stderr)
+			}
+		}
+		if run != nil {
+			if err := run(os.Args[2:], os.Stdout); err != nil {
+				fmt.Fprintln(os.Stderr, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
 	a := app.NewWithID("com.university.paintdrawerpro")
 	w := a.NewWindow("Paint Drawer Pro")
 	w.Resize(fyne.NewSize(1024, 768))
@@ -35,7 +59,7 @@ func main() {
 		nil, 
 		container.NewHBox(mainUI.StatusLabel), 
 		mainUI.ToolsContainer, 
-		nil, 
+		mainUI.SidePanel, 
 		paddedDrawingArea)
 	
 	w.SetContent(mainUI.Container)
@@ -0,0 +1,128 @@
+package models
+
+import (
+	"sort"
+)
+
+
+type AlignEdge int
+
+const (
+	AlignLeft AlignEdge = iota
+	AlignCenter
+	AlignRight
+	AlignTop
+	AlignMiddle
+	AlignBottom
+)
+
+
+var alignNames = map[AlignEdge]string{
+	AlignLeft:   "Align left",
+	AlignCenter: "Align center",
+	AlignRight:  "Align right",
+	AlignTop:    "Align top",
+	AlignMiddle: "Align middle",
+	AlignBottom: "Align bottom",
+}
+
+
+func (e AlignEdge) String() string {
+	return alignNames[e]
+}
+
+
+type DistributeAxis int
+
+const (
+	DistributeHorizontal DistributeAxis = iota
+	DistributeVertical
+)
+
+
+func (a DistributeAxis) String() string {
+	if a == DistributeVertical {
+		return "Distribute vertically"
+	}
+	return "Distribute horizontally"
+}
+
+
+func AlignOffsets(shapes []Shape, edge AlignEdge, refMin, refMax Point) []Point {
+	offsets := make([]Point, len(shapes))
+	for i, shape := range shapes {
+		lo, hi := ShapeBounds(shape)
+		switch edge {
+		case AlignLeft:
+			offsets[i].X = refMin.X - lo.X
+		case AlignCenter:
+			offsets[i].X = (refMin.X+refMax.X)/2 - (lo.X+hi.X)/2
+		case AlignRight:
+			offsets[i].X = refMax.X - hi.X
+		case AlignTop:
+			offsets[i].Y = refMin.Y - lo.Y
+		case AlignMiddle:
+			offsets[i].Y = (refMin.Y+refMax.Y)/2 - (lo.Y+hi.Y)/2
+		case AlignBottom:
+			offsets[i].Y = refMax.Y - hi.Y
+		}
+	}
+	return offsets
+}
+
+
+func DistributeOffsets(shapes []Shape, axis DistributeAxis) []Point {
+	offsets := make([]Point, len(shapes))
+	if len(shapes) < 3 {
+		return offsets
+	}
+
+	span := func(shape Shape) (float64, float64) {
+		lo, hi := ShapeBounds(shape)
+		if axis == DistributeVertical {
+			return lo.Y, hi.Y
+		}
+		return lo.X, hi.X
+	}
+
+	order := make([]int, len(shapes))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		loA, _ := span(shapes[order[a]])
+		loB, _ := span(shapes[order[b]])
+		return loA < loB
+	})
+
+	start, _ := span(shapes[order[0]])
+	end := start
+	total := 0.0
+	for _, i := range order {
+		lo, hi := span(shapes[i])
+		total += hi - lo
+		end = max(end, hi)
+	}
+	gap := (end - start - total) / float64(len(shapes)-1)
+
+	cursor := start
+	for _, i := range order {
+		lo, hi := span(shapes[i])
+		if axis == DistributeVertical {
+			offsets[i].Y = cursor - lo
+		} else {
+			offsets[i].X = cursor - lo
+		}
+		cursor += hi - lo + gap
+	}
+	return offsets
+}
+
+
+type AlignReference int
+
+const (
+	AlignToSelection AlignReference = iota
+	AlignToKeyShape
+	AlignToCanvas
+)
@@ -0,0 +1,103 @@
+package models
+
+import (
+	"image/color"
+	"reflect"
+	"testing"
+)
+
+
+func alignBox(x0, y0, x1, y1 float64) Shape {
+	return NewRectangle(Point{X: x0, Y: y0}, Point{X: x1, Y: y1}, color.RGBA{0, 0, 0, 255}, 1)
+}
+
+
+func TestAlignOffsets(t *testing.T) {
+	shapes := []Shape{alignBox(10, 10, 30, 20), alignBox(40, 0, 100, 50)}
+	refMin, refMax := Point{X: 0, Y: 0}, Point{X: 200, Y: 100}
+
+	tests := []struct {
+		edge AlignEdge
+		want []Point
+	}{
+		{edge: AlignLeft, want: []Point{{X: -10}, {X: -40}}},
+		{edge: AlignCenter, want: []Point{{X: 80}, {X: 30}}},
+		{edge: AlignRight, want: []Point{{X: 170}, {X: 100}}},
+		{edge: AlignTop, want: []Point{{Y: -10}, {Y: 0}}},
+		{edge: AlignMiddle, want: []Point{{Y: 35}, {Y: 25}}},
+		{edge: AlignBottom, want: []Point{{Y: 80}, {Y: 50}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.edge.String(), func(t *testing.T) {
+			if got := AlignOffsets(shapes, tt.edge, refMin, refMax); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("AlignOffsets = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+
+func TestDistributeOffsets(t *testing.T) {
+	tests := []struct {
+		name   string
+		shapes []Shape
+		axis   DistributeAxis
+		want   []Point
+	}{
+		{
+			name:   "two shapes stay put",
+			shapes: []Shape{alignBox(0, 0, 10, 10), alignBox(50, 0, 60, 10)},
+			want:   []Point{{}, {}},
+		},
+		{
+			name:   "three shapes",
+			shapes: []Shape{alignBox(0, 0, 10, 10), alignBox(15, 0, 25, 10), alignBox(50, 0, 60, 10)},
+			want:   []Point{{}, {X: 10}, {}},
+		},
+		{
+			name:   "three shapes out of order",
+			shapes: []Shape{alignBox(50, 0, 60, 10), alignBox(0, 0, 10, 10), alignBox(15, 0, 25, 10)},
+			want:   []Point{{}, {}, {X: 10}},
+		},
+		{
+			name:   "three shapes vertically",
+			shapes: []Shape{alignBox(0, 0, 10, 10), alignBox(0, 15, 10, 25), alignBox(0, 50, 10, 60)},
+			axis:   DistributeVertical,
+			want:   []Point{{}, {Y: 10}, {}},
+		},
+		{
+			name:   "overlapping shapes get equal negative gaps",
+			shapes: []Shape{alignBox(0, 0, 40, 10), alignBox(5, 0, 45, 10), alignBox(20, 0, 60, 10)},
+			want:   []Point{{}, {X: 5}, {}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DistributeOffsets(tt.shapes, tt.axis); !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("DistributeOffsets = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+
+func TestDistributeOverlapKeepsExtent(t *testing.T) {
+	shapes := []Shape{alignBox(0, 0, 40, 10), alignBox(5, 0, 45, 10), alignBox(20, 0, 60, 10)}
+	offsets := DistributeOffsets(shapes, DistributeHorizontal)
+
+	var spans [][2]float64
+	for i, shape := range shapes {
+		lo, hi := ShapeBounds(shape)
+		spans = append(spans, [2]float64{lo.X + offsets[i].X, hi.X + offsets[i].X})
+	}
+	if spans[0][0] != 0 || spans[2][1] != 60 {
+		t.Fatalf("distributed extent = %v..%v, want 0..60", spans[0][0], spans[2][1])
+	}
+	for i := 1; i < len(spans); i++ {
+		if gap := spans[i][0] - spans[i-1][1]; gap != -30 {
+			t.Fatalf("gap %d = %v, want -30", i, gap)
+		}
+	}
+}
@@ -6,7 +6,7 @@ import (
 )
 
 
-func NewCircle(center Point, radius int, color color.Color) *Circle {
+func NewCircle(center Point, radius float64, color color.Color) *Circle {
 	if radius <= 0 {
 		radius = 1
 	}
@@ -19,11 +19,12 @@ func NewCircle(center Point, radius int, color color.Color) *Circle {
 
 
 func (c *Circle) Draw(canvas [][]color.Color, antiAliasing bool) {
-	if antiAliasing {
-		drawXiaolinWuCircle(canvas, c.Center.X, c.Center.Y, c.Radius, c.Color)
-	} else {
-		drawMidpointCircle(canvas, c.Center.X, c.Center.Y, c.Radius, c.Color)
-	}
+	c.drawTraced(canvas, antiAliasing, RasterAlgorithms{}, nil)
+}
+
+
+func (c *Circle) drawTraced(canvas [][]color.Color, antiAliasing bool, defaults RasterAlgorithms, tracer *pixelTracer) {
+	drawCircle(canvas, tracer, c.resolved(defaults), antiAliasing, c.Center.X, c.Center.Y, c.Radius, c.Color)
 }
 
 
@@ -31,7 +32,7 @@ func (c *Circle) Contains(p Point) bool {
 	distSq := (p.X-c.Center.X)*(p.X-c.Center.X) + (p.Y-c.Center.Y)*(p.Y-c.Center.Y)
 	
 	radiusSq := c.Radius * c.Radius
-	return math.Abs(float64(distSq-radiusSq)) <= float64(5*5) 
+	return math.Abs(distSq-radiusSq) <= 5*5 
 }
 
 
@@ -44,7 +45,7 @@ func (c *Circle) GetControlPoints() []Point {
 }
 
 
-func (c *Circle) Move(deltaX, deltaY int) {
+func (c *Circle) Move(deltaX, deltaY float64) {
 	c.Center.X += deltaX
 	c.Center.Y += deltaY
 }
@@ -60,22 +61,36 @@ func (c *Circle) GetColor() color.Color {
 }
 
 
-func (c *Circle) Serialize() map[string]interface{} {
-	r, g, b, a := c.Color.RGBA()
-	return map[string]interface{}{
-		"type":    "circle",
-		"centerX": c.Center.X,
-		"centerY": c.Center.Y,
-		"radius":  c.Radius,
-		"color":   []uint32{r, g, b, a},
-	}
-}
-
-
 func (c *Circle) Clone() Shape {
-	return NewCircle(
+	clone := NewCircle(
 		Point{X: c.Center.X, Y: c.Center.Y},
 		c.Radius,
 		c.Color,
 	)
-}
\ No newline at end of file
+	clone.Raster = c.Raster
+	return clone
+}
+
+
+func (c *Circle) ToPath() *Path {
+	path := NewPath(c.Color, 1)
+	path.MoveTo(Point{X: c.Center.X + c.Radius, Y: c.Center.Y})
+	path.ArcTo(c.Center, c.Radius, 0, 2*math.Pi)
+	path.Close()
+	return path
+}
+
+
+func (c *Circle) Transform(m Matrix) Shape {
+	if m.IsSimilarity() {
+		c.Center = m.Apply(c.Center)
+		c.Radius = c.Radius * m.ScaleFactor()
+		if c.Radius <= 0 {
+			c.Radius = 1
+		}
+		return c
+	}
+	path := c.ToPath()
+	path.Raster = c.Raster
+	return path.Transform(m)
+}
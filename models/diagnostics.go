@@ -0,0 +1,282 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"paint-drawer-pro/algorithms"
+	"strings"
+)
+
+
+type Diagnostic struct {
+	Layer    int
+	Shape    int
+	Type     string
+	Field    string
+	Problem  string
+	Repaired bool
+}
+
+
+func (d Diagnostic) Location() string {
+	if d.Layer < 0 {
+		return "document"
+	}
+	location := fmt.Sprintf("layer %d", d.Layer+1)
+	if d.Shape >= 0 {
+		location += fmt.Sprintf(", shape %d", d.Shape+1)
+		if d.Type != "" {
+			location += fmt.Sprintf(" (%s)", d.Type)
+		}
+	}
+	return location
+}
+
+
+func (d Diagnostic) Action() string {
+	if d.Repaired {
+		return "repaired"
+	}
+	if d.Shape < 0 {
+		return "layer skipped"
+	}
+	return "shape skipped"
+}
+
+
+func (d Diagnostic) String() string {
+	field := ""
+	if d.Field != "" {
+		field = fmt.Sprintf(" field %s:", d.Field)
+	}
+	return fmt.Sprintf("%s:%s %s (%s)", d.Location(), field, d.Problem, d.Action())
+}
+
+
+type LoadReport struct {
+	Diagnostics []Diagnostic
+}
+
+
+func (r *LoadReport) Empty() bool {
+	return r == nil || len(r.Diagnostics) == 0
+}
+
+
+func (r *LoadReport) Skipped() int {
+	count := 0
+	for _, d := range r.Diagnostics {
+		if !d.Repaired {
+			count++
+		}
+	}
+	return count
+}
+
+
+func (r *LoadReport) Repaired() int {
+	return len(r.Diagnostics) - r.Skipped()
+}
+
+
+func (r *LoadReport) Summary() string {
+	if r.Empty() {
+		return "no problems found"
+	}
+	return fmt.Sprintf("%d problems: %d repaired, %d skipped", len(r.Diagnostics), r.Repaired(), r.Skipped())
+}
+
+
+func (r *LoadReport) add(layer, shape int, check *ShapeCheck) {
+	for _, issue := range check.Issues {
+		r.Diagnostics = append(r.Diagnostics, Diagnostic{
+			Layer:    layer,
+			Shape:    shape,
+			Type:     check.Type,
+			Field:    issue.Field,
+			Problem:  issue.Problem,
+			Repaired: issue.Repaired,
+		})
+	}
+}
+
+
+type ShapeIssue struct {
+	Field    string
+	Problem  string
+	Repaired bool
+}
+
+
+type ShapeCheck struct {
+	Type   string
+	Issues []ShapeIssue
+}
+
+
+func (c *ShapeCheck) repair(field, format string, args ...interface{}) {
+	c.Issues = append(c.Issues, ShapeIssue{Field: field, Problem: fmt.Sprintf(format, args...), Repaired: true})
+}
+
+
+func (c *ShapeCheck) reject(field, format string, args ...interface{}) error {
+	problem := fmt.Sprintf(format, args...)
+	c.Issues = append(c.Issues, ShapeIssue{Field: field, Problem: problem})
+	if field != "" {
+		return fmt.Errorf("%s: %s", field, problem)
+	}
+	return fmt.Errorf("%s", problem)
+}
+
+
+func (c *ShapeCheck) Rejected() bool {
+	for _, issue := range c.Issues {
+		if !issue.Repaired {
+			return true
+		}
+	}
+	return false
+}
+
+
+func (c *ShapeCheck) reported(field string) bool {
+	for _, issue := range c.Issues {
+		if issue.Field == field || strings.HasPrefix(issue.Field, field+".") {
+			return true
+		}
+	}
+	return false
+}
+
+
+func (c *ShapeCheck) nest(prefix string, child *ShapeCheck) {
+	for _, issue := range child.Issues {
+		field := prefix
+		if issue.Field != "" {
+			field += "." + issue.Field
+		}
+		problem := issue.Problem
+		if !issue.Repaired {
+			problem += "; child removed"
+		}
+		c.Issues = append(c.Issues, ShapeIssue{Field: field, Problem: problem, Repaired: true})
+	}
+}
+
+
+func decodeRecord(data []byte, record interface{}, check *ShapeCheck, required ...string) (map[string]interface{}, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil || raw == nil {
+		return nil, check.reject("", "not a JSON object")
+	}
+	for _, field := range required {
+		if value, ok := raw[field]; !ok || value == nil {
+			return nil, check.reject(field, "missing")
+		}
+	}
+
+	for attempts := len(raw); attempts >= 0; attempts-- {
+		err := json.Unmarshal(data, record)
+		typeErr, ok := err.(*json.UnmarshalTypeError)
+		if !ok {
+			if err != nil {
+				return nil, check.reject("", "%v", err)
+			}
+			return raw, nil
+		}
+
+		field := strings.SplitN(typeErr.Field, ".", 2)[0]
+		if field == "" {
+			return nil, check.reject("", "expected %s, found %s", typeErr.Type, typeErr.Value)
+		}
+		for _, name := range required {
+			if name == field {
+				return nil, check.reject(typeErr.Field, "expected %s, found %s", typeErr.Type, typeErr.Value)
+			}
+		}
+		check.repair(typeErr.Field, "expected %s, found %s; using the default", typeErr.Type, typeErr.Value)
+		delete(raw, field)
+		data, _ = json.Marshal(raw)
+	}
+	return raw, nil
+}
+
+
+func checkColor(raw map[string]interface{}, field string, c *ColorData, check *ShapeCheck) {
+	if _, ok := raw[field]; ok {
+		return
+	}
+	*c = ColorData{A: 255}
+	if !check.reported(field) {
+		check.repair(field, "missing; using black")
+	}
+}
+
+
+func checkThickness(raw map[string]interface{}, thickness *int, check *ShapeCheck) {
+	if *thickness >= 1 {
+		return
+	}
+	if _, ok := raw["thickness"]; ok {
+		check.repair("thickness", "%d is less than 1; using 1", *thickness)
+	} else if !check.reported("thickness") {
+		check.repair("thickness", "missing; using 1")
+	}
+	*thickness = 1
+}
+
+
+func checkRadius(radius *float64, check *ShapeCheck) {
+	if *radius >= 0 {
+		return
+	}
+	check.repair("radius", "%g is negative; using %g", *radius, -*radius)
+	*radius = -*radius
+}
+
+
+func checkFillImage(useImage bool, data *ImageData, check *ShapeCheck) (bool, [][]color.Color) {
+	if !useImage {
+		return false, nil
+	}
+	if data == nil {
+		check.repair("fillImage", "missing; using the fill color")
+		return false, nil
+	}
+	if data.Width <= 0 || data.Height <= 0 || len(data.Pixels) != data.Width*data.Height*4 {
+		check.repair("fillImage", "%d pixel bytes do not match a %dx%d image; using the fill color", len(data.Pixels), data.Width, data.Height)
+		return false, nil
+	}
+	return true, data.Image()
+}
+
+
+func checkFillColor(isFilled, useImage bool, fillColor *ColorData, check *ShapeCheck) bool {
+	if !isFilled || useImage || fillColor != nil {
+		return isFilled
+	}
+	if !check.reported("fillColor") {
+		check.repair("fillColor", "missing on a filled shape; fill disabled")
+	}
+	return false
+}
+
+
+func checkRaster(r *RasterAlgorithms, check *ShapeCheck) {
+	if r == nil {
+		return
+	}
+	if _, ok := algorithms.LookupLineAlgorithm(r.Line); r.Line != "" && !ok {
+		check.repair("raster.line", "unknown line algorithm %q; using the default", r.Line)
+		r.Line = ""
+	}
+	if _, ok := algorithms.LookupCircleAlgorithm(r.Circle); r.Circle != "" && !ok {
+		check.repair("raster.circle", "unknown circle algorithm %q; using the default", r.Circle)
+		r.Circle = ""
+	}
+	if _, ok := algorithms.LookupFillAlgorithm(r.Fill); r.Fill != "" && !ok {
+		check.repair("raster.fill", "unknown fill algorithm %q; using the default", r.Fill)
+		r.Fill = ""
+	}
+}
@@ -0,0 +1,87 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+
+func TestRepairShapeDiagnostics(t *testing.T) {
+	tests := []struct {
+		name     string
+		record   string
+		rejected bool
+		field    string
+		problem  string
+	}{
+		{
+			name:     "null entry",
+			record:   `null`,
+			rejected: true,
+			problem:  "not a JSON object",
+		},
+		{
+			name:     "array entry",
+			record:   `[1, 2]`,
+			rejected: true,
+			problem:  "not a JSON object",
+		},
+		{
+			name:     "non-string type",
+			record:   `{"type": 5}`,
+			rejected: true,
+			field:    "type",
+			problem:  "expected a string",
+		},
+		{
+			name:     "unknown type",
+			record:   `{"type": "star"}`,
+			rejected: true,
+			field:    "type",
+			problem:  `unknown shape type "star"`,
+		},
+		{
+			name:    "filled polygon without fill color",
+			record:  `{"type":"polygon","vertices":[{"X":0,"Y":0},{"X":10,"Y":0},{"X":5,"Y":5}],"color":{"R":0,"G":0,"B":0,"A":255},"thickness":1,"isFilled":true}`,
+			field:   "fillColor",
+			problem: "missing on a filled shape; fill disabled",
+		},
+		{
+			name:    "filled rectangle without fill color",
+			record:  `{"type":"rectangle","topLeft":{"X":0,"Y":0},"bottomRight":{"X":10,"Y":10},"color":{"R":0,"G":0,"B":0,"A":255},"thickness":1,"isFilled":true}`,
+			field:   "fillColor",
+			problem: "missing on a filled shape; fill disabled",
+		},
+		{
+			name:    "negative radius",
+			record:  `{"type":"circle","center":{"X":0,"Y":0},"radius":-4,"color":{"R":0,"G":0,"B":0,"A":255}}`,
+			field:   "radius",
+			problem: "-4 is negative; using 4",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shape, check := RepairShape([]byte(tt.record))
+			if tt.rejected != (shape == nil) {
+				t.Fatalf("rejected = %v, want %v (issues %v)", shape == nil, tt.rejected, check.Issues)
+			}
+			if len(check.Issues) != 1 {
+				t.Fatalf("issues = %v, want exactly one", check.Issues)
+			}
+			issue := check.Issues[0]
+			if issue.Field != tt.field || !strings.Contains(issue.Problem, tt.problem) {
+				t.Fatalf("issue = %+v, want field %q with %q", issue, tt.field, tt.problem)
+			}
+		})
+	}
+}
+
+
+func TestRepairedFillIsDisabled(t *testing.T) {
+	record := `{"type":"rectangle","topLeft":{"X":0,"Y":0},"bottomRight":{"X":10,"Y":10},"color":{"R":0,"G":0,"B":0,"A":255},"thickness":1,"isFilled":true}`
+	shape, _ := RepairShape([]byte(record))
+	if rectangle := shape.(*Rectangle); rectangle.IsFilled || rectangle.FillColor != nil {
+		t.Fatalf("repaired rectangle: IsFilled = %v, FillColor = %v", rectangle.IsFilled, rectangle.FillColor)
+	}
+}
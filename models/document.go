@@ -0,0 +1,249 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"paint-drawer-pro/algorithms"
+	"sort"
+)
+
+
+const DocumentVersion = 2
+
+
+type Document struct {
+	Shapes []Shape
+	Layers []*Layer
+	Raster RasterAlgorithms
+}
+
+
+type DocumentFile struct {
+	Version int               `json:"version"`
+	Raster  *RasterAlgorithms `json:"raster,omitempty"`
+	Layers  []LayerFile       `json:"layers"`
+}
+
+
+type LayerFile struct {
+	Name      string            `json:"name"`
+	Visible   bool              `json:"visible"`
+	Locked    bool              `json:"locked"`
+	Opacity   float64           `json:"opacity"`
+	BlendMode string            `json:"blendMode"`
+	Shapes    []json.RawMessage `json:"shapes"`
+	index     int
+}
+
+
+func (d *Document) State() *DrawingState {
+	return &DrawingState{Shapes: d.Shapes, Layers: d.Layers, ActiveLayer: len(d.Layers) - 1, Raster: d.Raster}
+}
+
+
+func LoadDocument(filePath string) (*Document, *LoadReport, error) {
+	
+	fileData, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error reading file: %v", err)
+	}
+	return ParseDocument(fileData)
+}
+
+
+func ParseDocument(fileData []byte) (*Document, *LoadReport, error) {
+	file, report, err := ReadDocumentFile(fileData)
+	if err != nil {
+		return nil, nil, err
+	}
+	
+	doc := &Document{Raster: rasterFrom(file.Raster)}
+	for i, layerFile := range file.Layers {
+		shapes := RepairShapes(layerFile.Shapes, layerFile.index, report)
+		layer := layerFile.Layer(i)
+		layer.Count = len(shapes)
+		doc.Layers = append(doc.Layers, layer)
+		doc.Shapes = append(doc.Shapes, shapes...)
+	}
+	if len(doc.Layers) == 0 {
+		return nil, nil, fmt.Errorf("document has no usable layers")
+	}
+	sort.SliceStable(report.Diagnostics, func(i, j int) bool {
+		return report.Diagnostics[i].Layer < report.Diagnostics[j].Layer
+	})
+	return doc, report, nil
+}
+
+
+func ReadDocumentFile(fileData []byte) (*DocumentFile, *LoadReport, error) {
+	
+	var data map[string]interface{}
+	err := json.Unmarshal(fileData, &data)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error parsing JSON: %v", err)
+	}
+	
+	version := documentVersion(data)
+	if version > DocumentVersion {
+		return nil, nil, fmt.Errorf("document version %d is newer than this version of the app supports (%d)", version, DocumentVersion)
+	}
+	if version < DocumentVersion {
+		if err := MigrateDocument(data, version); err != nil {
+			return nil, nil, err
+		}
+	}
+	
+	layers, ok := data["layers"].([]interface{})
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid document: layers is not a list")
+	}
+	
+	file := &DocumentFile{Version: DocumentVersion}
+	report := &LoadReport{}
+	file.Raster = readDocumentRaster(data["raster"], report)
+	for i, layerData := range layers {
+		encoded, _ := json.Marshal(layerData)
+		layerFile, check := readLayerFile(encoded)
+		report.add(i, -1, check)
+		if layerFile != nil {
+			layerFile.index = i
+			file.Layers = append(file.Layers, *layerFile)
+		}
+	}
+	return file, report, nil
+}
+
+
+func readDocumentRaster(value interface{}, report *LoadReport) *RasterAlgorithms {
+	if value == nil {
+		return nil
+	}
+
+	check := &ShapeCheck{}
+	encoded, _ := json.Marshal(value)
+	var raster RasterAlgorithms
+	if err := json.Unmarshal(encoded, &raster); err != nil {
+		check.repair("raster", "expected an object of algorithm names; using the defaults")
+		report.add(-1, -1, check)
+		return nil
+	}
+	checkRaster(&raster, check)
+	report.add(-1, -1, check)
+	return rasterData(raster)
+}
+
+
+func readLayerFile(data []byte) (*LayerFile, *ShapeCheck) {
+	defaults := NewLayer("")
+	layer := LayerFile{
+		Visible:   defaults.Visible,
+		Locked:    defaults.Locked,
+		Opacity:   defaults.Opacity,
+		BlendMode: string(defaults.BlendMode),
+	}
+	
+	check := &ShapeCheck{}
+	raw, err := decodeRecord(data, &layer, check)
+	if err != nil {
+		return nil, check
+	}
+	if _, ok := raw["shapes"]; !ok && !check.reported("shapes") {
+		check.repair("shapes", "missing; layer is empty")
+	}
+	if layer.Opacity < 0 || layer.Opacity > 1 {
+		clamped := math.Max(0, math.Min(1, layer.Opacity))
+		check.repair("opacity", "%g is outside 0..1; using %g", layer.Opacity, clamped)
+		layer.Opacity = clamped
+	}
+	if !knownBlendMode(algorithms.BlendMode(layer.BlendMode)) {
+		check.repair("blendMode", "unknown blend mode %q; using %s", layer.BlendMode, defaults.BlendMode)
+		layer.BlendMode = string(defaults.BlendMode)
+	}
+	return &layer, check
+}
+
+
+func knownBlendMode(mode algorithms.BlendMode) bool {
+	for _, known := range algorithms.BlendModes {
+		if known == mode {
+			return true
+		}
+	}
+	return false
+}
+
+
+func (l LayerFile) Layer(index int) *Layer {
+	layer := NewLayer(l.Name)
+	if layer.Name == "" {
+		layer.Name = fmt.Sprintf("Layer %d", index+1)
+	}
+	layer.Visible = l.Visible
+	layer.Locked = l.Locked
+	layer.Opacity = l.Opacity
+	if l.BlendMode != "" {
+		layer.BlendMode = algorithms.BlendMode(l.BlendMode)
+	}
+	return layer
+}
+
+
+func NewDocumentFile(state *DrawingState) (*DocumentFile, error) {
+	file := &DocumentFile{Version: DocumentVersion, Raster: rasterData(state.Raster), Layers: make([]LayerFile, 0, len(state.Layers))}
+	
+	for i, layer := range state.Layers {
+		shapes := state.LayerShapes(i)
+		for j, shape := range shapes {
+			if err := CheckRoundTrip(shape); err != nil {
+				return nil, fmt.Errorf("layer %d shape %d: %v", i+1, j+1, err)
+			}
+		}
+		records, err := EncodeShapes(shapes)
+		if err != nil {
+			return nil, fmt.Errorf("layer %d: %v", i+1, err)
+		}
+		
+		file.Layers = append(file.Layers, LayerFile{
+			Name:      layer.Name,
+			Visible:   layer.Visible,
+			Locked:    layer.Locked,
+			Opacity:   layer.Opacity,
+			BlendMode: string(layer.BlendMode),
+			Shapes:    records,
+		})
+	}
+	return file, nil
+}
+
+
+func MarshalDocument(state *DrawingState) ([]byte, error) {
+	file, err := NewDocumentFile(state)
+	if err != nil {
+		return nil, fmt.Errorf("error serializing shapes: %v", err)
+	}
+	
+	
+	jsonData, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error serializing shapes: %v", err)
+	}
+	return jsonData, nil
+}
+
+
+func SaveDocument(filePath string, state *DrawingState) error {
+	jsonData, err := MarshalDocument(state)
+	if err != nil {
+		return err
+	}
+	
+	
+	err = os.WriteFile(filePath, jsonData, 0644)
+	if err != nil {
+		return fmt.Errorf("error writing to file: %v", err)
+	}
+	
+	return nil
+}
@@ -2,32 +2,48 @@ package models
 
 import (
 	"image/color"
+	"math"
 	"paint-drawer-pro/algorithms"
 )
 
 
 
 
-func drawMidpointLine(canvas [][]color.Color, x0, y0, x1, y1 int, c color.Color) {
-	algorithms.MidpointLine(canvas, x0, y0, x1, y1, c)
+func drawMidpointLine(canvas [][]color.Color, tracer *pixelTracer, x0, y0, x1, y1 float64, c color.Color) {
+	tracer.traceLine(canvas, "MidpointLine", x0, y0, x1, y1, 1, func() {
+		algorithms.MidpointLine(canvas, roundPixel(x0), roundPixel(y0), roundPixel(x1), roundPixel(y1), c)
+	})
 }
 
 
-func drawThickLine(canvas [][]color.Color, x0, y0, x1, y1 int, c color.Color, thickness int) {
-	algorithms.ThickLine(canvas, x0, y0, x1, y1, c, thickness)
+func drawThickLine(canvas [][]color.Color, tracer *pixelTracer, x0, y0, x1, y1 float64, c color.Color, thickness int) {
+	tracer.traceLine(canvas, "ThickLine", x0, y0, x1, y1, thickness, func() {
+		algorithms.ThickLine(canvas, roundPixel(x0), roundPixel(y0), roundPixel(x1), roundPixel(y1), c, thickness)
+	})
 }
 
 
-func drawMidpointCircle(canvas [][]color.Color, centerX, centerY, radius int, c color.Color) {
-	algorithms.MidpointCircle(canvas, centerX, centerY, radius, c)
+func drawMidpointCircle(canvas [][]color.Color, tracer *pixelTracer, centerX, centerY, radius float64, c color.Color) {
+	tracer.traceCircle(canvas, "MidpointCircle", centerX, centerY, radius, func() {
+		algorithms.MidpointCircle(canvas, roundPixel(centerX), roundPixel(centerY), roundPixel(radius), c)
+	})
 }
 
 
-func drawXiaolinWuLine(canvas [][]color.Color, x0, y0, x1, y1 int, c color.Color) {
-	algorithms.XiaolinWuLine(canvas, x0, y0, x1, y1, c)
+func drawXiaolinWuLine(canvas [][]color.Color, tracer *pixelTracer, x0, y0, x1, y1 float64, c color.Color) {
+	tracer.traceLine(canvas, "XiaolinWuLine", x0, y0, x1, y1, 1, func() {
+		algorithms.XiaolinWuLine(canvas, x0, y0, x1, y1, c)
+	})
 }
 
 
-func drawXiaolinWuCircle(canvas [][]color.Color, centerX, centerY, radius int, c color.Color) {
-	algorithms.XiaolinWuCircle(canvas, centerX, centerY, radius, c)
-}
\ No newline at end of file
+func drawXiaolinWuCircle(canvas [][]color.Color, tracer *pixelTracer, centerX, centerY, radius float64, c color.Color) {
+	tracer.traceCircle(canvas, "XiaolinWuCircle", centerX, centerY, radius, func() {
+		algorithms.XiaolinWuCircle(canvas, centerX, centerY, radius, c)
+	})
+}
+
+
+func roundPixel(v float64) int {
+	return int(math.Round(v))
+}
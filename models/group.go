@@ -0,0 +1,170 @@
+package models
+
+import (
+	"fmt"
+	"image/color"
+)
+
+
+type Group struct {
+	Children []Shape
+}
+
+
+func NewGroup(children []Shape) *Group {
+	return &Group{Children: append([]Shape{}, children...)}
+}
+
+
+func (g *Group) Draw(canvas [][]color.Color, antiAliasing bool) {
+	g.drawTraced(canvas, antiAliasing, RasterAlgorithms{}, nil)
+}
+
+
+func (g *Group) drawTraced(canvas [][]color.Color, antiAliasing bool, defaults RasterAlgorithms, tracer *pixelTracer) {
+	for _, child := range g.Children {
+		drawShape(child, canvas, antiAliasing, defaults, tracer)
+	}
+}
+
+
+func (g *Group) Contains(p Point) bool {
+	for i := len(g.Children) - 1; i >= 0; i-- {
+		if g.Children[i].Contains(p) {
+			return true
+		}
+	}
+	return false
+}
+
+
+func (g *Group) GetControlPoints() []Point {
+	var points []Point
+	for _, child := range g.Children {
+		points = append(points, child.GetControlPoints()...)
+	}
+	return points
+}
+
+
+func (g *Group) Move(deltaX, deltaY float64) {
+	for _, child := range g.Children {
+		child.Move(deltaX, deltaY)
+	}
+}
+
+
+func (g *Group) SetColor(c color.Color) {
+	for _, child := range g.Children {
+		child.SetColor(c)
+	}
+}
+
+
+func (g *Group) GetColor() color.Color {
+	if len(g.Children) == 0 {
+		return nil
+	}
+	return g.Children[0].GetColor()
+}
+
+
+func (g *Group) Clone() Shape {
+	children := make([]Shape, len(g.Children))
+	for i, child := range g.Children {
+		children[i] = child.Clone()
+	}
+	return &Group{Children: children}
+}
+
+
+func (g *Group) ToPath() *Path {
+	path := NewPath(g.GetColor(), 1)
+	for _, child := range g.Children {
+		path.Commands = append(path.Commands, child.ToPath().Commands...)
+	}
+	return path
+}
+
+
+func (g *Group) Transform(m Matrix) Shape {
+	for i, child := range g.Children {
+		g.Children[i] = child.Transform(m)
+	}
+	return g
+}
+
+
+func (g *Group) Leaves() []Shape {
+	var leaves []Shape
+	for _, child := range g.Children {
+		if nested, ok := child.(*Group); ok {
+			leaves = append(leaves, nested.Leaves()...)
+		} else {
+			leaves = append(leaves, child)
+		}
+	}
+	return leaves
+}
+
+
+func FlattenGroups(shapes []Shape) []Shape {
+	var leaves []Shape
+	for _, shape := range shapes {
+		if group, ok := shape.(*Group); ok {
+			leaves = append(leaves, group.Leaves()...)
+		} else {
+			leaves = append(leaves, shape)
+		}
+	}
+	return leaves
+}
+
+
+func (s *DrawingState) SharedLayer(shapes []Shape) (int, bool) {
+	if len(shapes) == 0 {
+		return -1, false
+	}
+	layer := s.LayerOfShape(shapes[0])
+	for _, shape := range shapes[1:] {
+		if s.LayerOfShape(shape) != layer {
+			return -1, false
+		}
+	}
+	return layer, true
+}
+
+
+func NewGroupCommand(state *DrawingState, shapes []Shape) (*BatchCommand, int) {
+	batch := &BatchCommand{Label: fmt.Sprintf("Group %d shapes", len(shapes))}
+	for i := len(shapes) - 1; i >= 0; i-- {
+		batch.Commands = append(batch.Commands, NewRemoveShapeCommand(state, shapes[i]))
+	}
+
+	top := IndexOfShape(state.Shapes, shapes[len(shapes)-1])
+	index := top - (len(shapes) - 1)
+	batch.Commands = append(batch.Commands, &AddShapeCommand{
+		Shape: NewGroup(shapes).Clone(),
+		Index: index,
+		Layer: state.LayerOfIndex(top),
+	})
+	return batch, index
+}
+
+
+func NewUngroupCommand(state *DrawingState, groups []*Group) *BatchCommand {
+	batch := &BatchCommand{Label: "Ungroup"}
+	for i := len(groups) - 1; i >= 0; i-- {
+		index := IndexOfShape(state.Shapes, groups[i])
+		layer := state.LayerOfIndex(index)
+		batch.Commands = append(batch.Commands, NewRemoveShapeCommand(state, groups[i]))
+		for j, child := range groups[i].Children {
+			batch.Commands = append(batch.Commands, &AddShapeCommand{
+				Shape: child.Clone(),
+				Index: index + j,
+				Layer: layer,
+			})
+		}
+	}
+	return batch
+}
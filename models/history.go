@@ -0,0 +1,460 @@
+package models
+
+import (
+	"image/color"
+	"time"
+)
+
+
+const (
+	DefaultHistoryBudget = 64 << 20
+	CoalesceWindow       = 750 * time.Millisecond
+)
+
+
+type Command interface {
+	Do(state *DrawingState)
+	Undo(state *DrawingState)
+	Name() string
+	Size() int
+}
+
+
+type Coalescer interface {
+	Coalesce(next Command) bool
+}
+
+
+type History struct {
+	Budget     int
+	undoStack  []Command
+	redoStack  []Command
+	used       int
+	lastRecord time.Time
+	sealed     bool
+}
+
+
+func NewHistory(budget int) *History {
+	if budget <= 0 {
+		budget = DefaultHistoryBudget
+	}
+	return &History{Budget: budget, sealed: true}
+}
+
+
+func (h *History) Execute(state *DrawingState, cmd Command) {
+	cmd.Do(state)
+	h.Record(cmd)
+}
+
+
+func (h *History) Record(cmd Command) {
+	h.clearRedo()
+
+	now := time.Now()
+	if !h.sealed && len(h.undoStack) > 0 && now.Sub(h.lastRecord) < CoalesceWindow {
+		top := h.undoStack[len(h.undoStack)-1]
+		if coalescer, ok := top.(Coalescer); ok {
+			before := top.Size()
+			if coalescer.Coalesce(cmd) {
+				h.used += top.Size() - before
+				h.lastRecord = now
+				h.trim()
+				return
+			}
+		}
+	}
+
+	h.undoStack = append(h.undoStack, cmd)
+	h.used += cmd.Size()
+	h.lastRecord = now
+	h.sealed = false
+	h.trim()
+}
+
+
+func (h *History) Seal() {
+	h.sealed = true
+}
+
+
+func (h *History) Undo(state *DrawingState) (Command, bool) {
+	if len(h.undoStack) == 0 {
+		return nil, false
+	}
+
+	cmd := h.undoStack[len(h.undoStack)-1]
+	h.undoStack = h.undoStack[:len(h.undoStack)-1]
+	cmd.Undo(state)
+	h.redoStack = append(h.redoStack, cmd)
+	h.sealed = true
+	return cmd, true
+}
+
+
+func (h *History) Redo(state *DrawingState) (Command, bool) {
+	if len(h.redoStack) == 0 {
+		return nil, false
+	}
+
+	cmd := h.redoStack[len(h.redoStack)-1]
+	h.redoStack = h.redoStack[:len(h.redoStack)-1]
+	cmd.Do(state)
+	h.undoStack = append(h.undoStack, cmd)
+	h.sealed = true
+	return cmd, true
+}
+
+
+func (h *History) CanUndo() bool {
+	return len(h.undoStack) > 0
+}
+
+
+func (h *History) CanRedo() bool {
+	return len(h.redoStack) > 0
+}
+
+
+func (h *History) Clear() {
+	h.undoStack = nil
+	h.redoStack = nil
+	h.used = 0
+	h.sealed = true
+}
+
+
+func (h *History) MemoryUsed() int {
+	return h.used
+}
+
+
+func (h *History) clearRedo() {
+	for _, cmd := range h.redoStack {
+		h.used -= cmd.Size()
+	}
+	h.redoStack = nil
+}
+
+
+func (h *History) trim() {
+	for h.used > h.Budget && len(h.undoStack) > 1 {
+		h.used -= h.undoStack[0].Size()
+		h.undoStack[0] = nil
+		h.undoStack = h.undoStack[1:]
+	}
+}
+
+
+type AddShapeCommand struct {
+	Shape Shape
+	Index int
+	Layer int
+}
+
+
+func NewAddShapeCommand(state *DrawingState, shape Shape) *AddShapeCommand {
+	return &AddShapeCommand{Shape: shape.Clone(), Index: state.ActiveInsertIndex(), Layer: state.ActiveLayer}
+}
+
+
+func (c *AddShapeCommand) Do(state *DrawingState) {
+	state.Shapes = insertShape(state.Shapes, c.Index, c.Shape.Clone())
+	state.adjustLayerCount(c.Layer, 1)
+}
+
+
+func (c *AddShapeCommand) Undo(state *DrawingState) {
+	if c.Index < 0 || c.Index >= len(state.Shapes) {
+		return
+	}
+	state.Deselect(state.Shapes[c.Index])
+	state.Shapes = removeShape(state.Shapes, c.Index)
+	state.adjustLayerCount(c.Layer, -1)
+}
+
+
+func (c *AddShapeCommand) Name() string {
+	return "Add shape"
+}
+
+
+func (c *AddShapeCommand) Size() int {
+	return ShapeSize(c.Shape)
+}
+
+
+type RemoveShapeCommand struct {
+	Shape Shape
+	Index int
+	Layer int
+}
+
+
+func NewRemoveShapeCommand(state *DrawingState, shape Shape) *RemoveShapeCommand {
+	index := IndexOfShape(state.Shapes, shape)
+	return &RemoveShapeCommand{Shape: shape.Clone(), Index: index, Layer: state.LayerOfIndex(index)}
+}
+
+
+func (c *RemoveShapeCommand) Do(state *DrawingState) {
+	if c.Index < 0 || c.Index >= len(state.Shapes) {
+		return
+	}
+	state.Deselect(state.Shapes[c.Index])
+	state.Shapes = removeShape(state.Shapes, c.Index)
+	state.adjustLayerCount(c.Layer, -1)
+}
+
+
+func (c *RemoveShapeCommand) Undo(state *DrawingState) {
+	if c.Index < 0 {
+		return
+	}
+	state.Shapes = insertShape(state.Shapes, c.Index, c.Shape.Clone())
+	state.adjustLayerCount(c.Layer, 1)
+}
+
+
+func (c *RemoveShapeCommand) Name() string {
+	return "Delete shape"
+}
+
+
+func (c *RemoveShapeCommand) Size() int {
+	return ShapeSize(c.Shape)
+}
+
+
+type ReplaceShapeCommand struct {
+	Index  int
+	Before Shape
+	After  Shape
+	Label  string
+	Key    string
+}
+
+
+func NewReplaceShapeCommand(index int, before, after Shape, label string) *ReplaceShapeCommand {
+	return &ReplaceShapeCommand{
+		Index:  index,
+		Before: before.Clone(),
+		After:  after.Clone(),
+		Label:  label,
+	}
+}
+
+
+func (c *ReplaceShapeCommand) Do(state *DrawingState) {
+	c.install(state, c.After)
+}
+
+
+func (c *ReplaceShapeCommand) Undo(state *DrawingState) {
+	c.install(state, c.Before)
+}
+
+
+func (c *ReplaceShapeCommand) install(state *DrawingState, snapshot Shape) {
+	if c.Index < 0 || c.Index >= len(state.Shapes) {
+		return
+	}
+	replacement := snapshot.Clone()
+	state.replaceInSelection(state.Shapes[c.Index], replacement)
+	state.Shapes[c.Index] = replacement
+}
+
+
+func (c *ReplaceShapeCommand) Coalesce(next Command) bool {
+	other, ok := next.(*ReplaceShapeCommand)
+	if !ok || c.Key == "" || other.Key != c.Key || other.Index != c.Index {
+		return false
+	}
+	c.After = other.After
+	return true
+}
+
+
+func (c *ReplaceShapeCommand) Name() string {
+	return c.Label
+}
+
+
+func (c *ReplaceShapeCommand) Size() int {
+	return ShapeSize(c.Before) + ShapeSize(c.After)
+}
+
+
+type SetShapesCommand struct {
+	Before       []Shape
+	After        []Shape
+	BeforeLayers []*Layer
+	AfterLayers  []*Layer
+	BeforeActive int
+	AfterActive  int
+	Label        string
+}
+
+
+func NewSetShapesCommand(state *DrawingState, after []Shape, label string) *SetShapesCommand {
+	layers := CloneLayers(state.Layers)
+	for _, layer := range layers {
+		layer.Count = 0
+	}
+	active := state.ActiveLayer
+	if active >= 0 && active < len(layers) {
+		layers[active].Count = len(after)
+	}
+	return NewSetLayersCommand(state, after, layers, active, label)
+}
+
+
+func NewSetLayersCommand(state *DrawingState, shapes []Shape, layers []*Layer, active int, label string) *SetShapesCommand {
+	return &SetShapesCommand{
+		Before:       cloneShapes(state.Shapes),
+		After:        cloneShapes(shapes),
+		BeforeLayers: CloneLayers(state.Layers),
+		AfterLayers:  CloneLayers(layers),
+		BeforeActive: state.ActiveLayer,
+		AfterActive:  active,
+		Label:        label,
+	}
+}
+
+
+func (c *SetShapesCommand) Do(state *DrawingState) {
+	state.Shapes = cloneShapes(c.After)
+	state.Layers = CloneLayers(c.AfterLayers)
+	state.ActiveLayer = c.AfterActive
+	state.ClearSelection()
+}
+
+
+func (c *SetShapesCommand) Undo(state *DrawingState) {
+	state.Shapes = cloneShapes(c.Before)
+	state.Layers = CloneLayers(c.BeforeLayers)
+	state.ActiveLayer = c.BeforeActive
+	state.ClearSelection()
+}
+
+
+func (c *SetShapesCommand) Name() string {
+	return c.Label
+}
+
+
+func (c *SetShapesCommand) Size() int {
+	size := 0
+	for _, s := range c.Before {
+		size += ShapeSize(s)
+	}
+	for _, s := range c.After {
+		size += ShapeSize(s)
+	}
+	return size
+}
+
+
+type BatchCommand struct {
+	Commands []Command
+	Label    string
+}
+
+
+func (c *BatchCommand) Do(state *DrawingState) {
+	for _, cmd := range c.Commands {
+		cmd.Do(state)
+	}
+}
+
+
+func (c *BatchCommand) Undo(state *DrawingState) {
+	for i := len(c.Commands) - 1; i >= 0; i-- {
+		c.Commands[i].Undo(state)
+	}
+}
+
+
+func (c *BatchCommand) Name() string {
+	return c.Label
+}
+
+
+func (c *BatchCommand) Size() int {
+	size := 0
+	for _, cmd := range c.Commands {
+		size += cmd.Size()
+	}
+	return size
+}
+
+
+func ShapeSize(s Shape) int {
+	if s == nil {
+		return 0
+	}
+
+	size := 64 + 16*len(s.GetControlPoints())
+	switch shape := s.(type) {
+	case *Polygon:
+		size += imageSize(shape.FillImage)
+	case *Rectangle:
+		size += imageSize(shape.FillImage)
+	case *Path:
+		for _, cmd := range shape.Commands {
+			size += 48 + 16*len(cmd.Points)
+		}
+	case *Group:
+		for _, child := range shape.Children {
+			size += ShapeSize(child)
+		}
+	}
+	return size
+}
+
+
+func imageSize(img [][]color.Color) int {
+	if len(img) == 0 {
+		return 0
+	}
+	return len(img) * len(img[0]) * 24
+}
+
+
+func IndexOfShape(shapes []Shape, shape Shape) int {
+	for i, s := range shapes {
+		if s == shape {
+			return i
+		}
+	}
+	return -1
+}
+
+
+func cloneShapes(shapes []Shape) []Shape {
+	clones := make([]Shape, len(shapes))
+	for i, s := range shapes {
+		clones[i] = s.Clone()
+	}
+	return clones
+}
+
+
+func insertShape(shapes []Shape, index int, shape Shape) []Shape {
+	if index < 0 || index > len(shapes) {
+		index = len(shapes)
+	}
+	shapes = append(shapes, nil)
+	copy(shapes[index+1:], shapes[index:])
+	shapes[index] = shape
+	return shapes
+}
+
+
+func removeShape(shapes []Shape, index int) []Shape {
+	if index < 0 || index >= len(shapes) {
+		return shapes
+	}
+	return append(shapes[:index], shapes[index+1:]...)
+}
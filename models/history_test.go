@@ -0,0 +1,194 @@
+package models
+
+import (
+	"image/color"
+	"testing"
+)
+
+
+func historyLine(x float64) *Line {
+	return NewLine(Point{X: x, Y: 0}, Point{X: x + 10, Y: 10}, color.RGBA{0, 0, 0, 255}, 1, "regular")
+}
+
+
+func historyState(shapes ...Shape) *DrawingState {
+	layer := NewLayer("Layer 1")
+	layer.Count = len(shapes)
+	return &DrawingState{Shapes: shapes, Layers: []*Layer{layer}, SelectedVertex: -1}
+}
+
+
+func moveCommand(state *DrawingState, x float64, key string) *ReplaceShapeCommand {
+	cmd := NewReplaceShapeCommand(0, state.Shapes[0], historyLine(x), "Move shape")
+	cmd.Key = key
+	return cmd
+}
+
+
+func expectMemory(t *testing.T, h *History) {
+	t.Helper()
+	want := 0
+	for _, cmd := range h.undoStack {
+		want += cmd.Size()
+	}
+	for _, cmd := range h.redoStack {
+		want += cmd.Size()
+	}
+	if h.MemoryUsed() != want {
+		t.Fatalf("MemoryUsed() = %d, want %d", h.MemoryUsed(), want)
+	}
+}
+
+
+func TestHistoryCoalescing(t *testing.T) {
+	tests := []struct {
+		name    string
+		between func(h *History)
+		keys    [2]string
+		steps   int
+	}{
+		{
+			name:  "same key inside the window",
+			keys:  [2]string{"move", "move"},
+			steps: 1,
+		},
+		{
+			name:    "sealed between records",
+			between: func(h *History) { h.Seal() },
+			keys:    [2]string{"move", "move"},
+			steps:   2,
+		},
+		{
+			name:    "window passed",
+			between: func(h *History) { h.lastRecord = h.lastRecord.Add(-CoalesceWindow) },
+			keys:    [2]string{"move", "move"},
+			steps:   2,
+		},
+		{
+			name:  "different keys",
+			keys:  [2]string{"move", "resize"},
+			steps: 2,
+		},
+		{
+			name:  "no key",
+			keys:  [2]string{"", ""},
+			steps: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := historyState(historyLine(0))
+			h := NewHistory(DefaultHistoryBudget)
+
+			h.Execute(state, moveCommand(state, 10, tt.keys[0]))
+			if tt.between != nil {
+				tt.between(h)
+			}
+			h.Execute(state, moveCommand(state, 20, tt.keys[1]))
+			expectMemory(t, h)
+
+			if len(h.undoStack) != tt.steps {
+				t.Fatalf("undo stack has %d steps, want %d", len(h.undoStack), tt.steps)
+			}
+			for i := 0; i < tt.steps; i++ {
+				if _, ok := h.Undo(state); !ok {
+					t.Fatalf("undo %d failed", i+1)
+				}
+			}
+			if h.CanUndo() {
+				t.Fatalf("history has more than %d steps", tt.steps)
+			}
+			if got := state.Shapes[0].(*Line).Start.X; got != 0 {
+				t.Fatalf("undo restored x = %v, want 0", got)
+			}
+			expectMemory(t, h)
+		})
+	}
+}
+
+
+func TestHistoryTrimKeepsNewest(t *testing.T) {
+	state := historyState()
+	size := NewAddShapeCommand(state, historyLine(0)).Size()
+	h := NewHistory(3*size + size/2)
+
+	for i := 0; i < 6; i++ {
+		h.Execute(state, NewAddShapeCommand(state, historyLine(float64(10*i))))
+		expectMemory(t, h)
+		if h.MemoryUsed() > h.Budget {
+			t.Fatalf("after %d commands MemoryUsed() = %d exceeds budget %d", i+1, h.MemoryUsed(), h.Budget)
+		}
+	}
+	if len(h.undoStack) != 3 {
+		t.Fatalf("undo stack has %d commands, want 3", len(h.undoStack))
+	}
+
+	for i := 5; i >= 3; i-- {
+		cmd, ok := h.Undo(state)
+		if !ok {
+			t.Fatalf("undo of command %d failed", i)
+		}
+		if got := cmd.(*AddShapeCommand).Shape.(*Line).Start.X; got != float64(10*i) {
+			t.Fatalf("undo removed the shape at x = %v, want %v: trim dropped a newer command", got, float64(10*i))
+		}
+	}
+	if h.CanUndo() {
+		t.Fatalf("trimmed commands are still undoable")
+	}
+	if len(state.Shapes) != 3 {
+		t.Fatalf("%d shapes remain after undoing the kept commands, want 3", len(state.Shapes))
+	}
+	expectMemory(t, h)
+}
+
+
+func TestHistoryTrimNeverDropsLastCommand(t *testing.T) {
+	state := historyState()
+	h := NewHistory(1)
+
+	h.Execute(state, NewAddShapeCommand(state, historyLine(0)))
+	h.Execute(state, NewAddShapeCommand(state, historyLine(10)))
+	if len(h.undoStack) != 1 {
+		t.Fatalf("undo stack has %d commands, want only the newest", len(h.undoStack))
+	}
+	expectMemory(t, h)
+
+	cmd, ok := h.Undo(state)
+	if !ok {
+		t.Fatalf("the newest command was trimmed")
+	}
+	if got := cmd.(*AddShapeCommand).Shape.(*Line).Start.X; got != 10 {
+		t.Fatalf("undo removed the shape at x = %v, want 10", got)
+	}
+}
+
+
+func TestHistoryRecordClearsRedo(t *testing.T) {
+	state := historyState()
+	h := NewHistory(DefaultHistoryBudget)
+
+	h.Execute(state, NewAddShapeCommand(state, historyLine(0)))
+	h.Execute(state, NewAddShapeCommand(state, historyLine(10)))
+	h.Undo(state)
+	if !h.CanRedo() {
+		t.Fatalf("undo did not make a redo step available")
+	}
+	expectMemory(t, h)
+
+	h.Execute(state, NewAddShapeCommand(state, historyLine(20)))
+	if h.CanRedo() {
+		t.Fatalf("a new command left the redo stack in place")
+	}
+	expectMemory(t, h)
+	if _, ok := h.Redo(state); ok {
+		t.Fatalf("Redo succeeded after a new command")
+	}
+
+	if len(state.Shapes) != 2 {
+		t.Fatalf("document has %d shapes, want 2", len(state.Shapes))
+	}
+	if got := state.Shapes[1].(*Line).Start.X; got != 20 {
+		t.Fatalf("second shape is at x = %v, want 20", got)
+	}
+}
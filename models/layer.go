@@ -0,0 +1,242 @@
+package models
+
+import (
+	"paint-drawer-pro/algorithms"
+)
+
+
+type Layer struct {
+	Name      string
+	Visible   bool
+	Locked    bool
+	Opacity   float64
+	BlendMode algorithms.BlendMode
+	Count     int
+}
+
+
+func NewLayer(name string) *Layer {
+	return &Layer{
+		Name:      name,
+		Visible:   true,
+		Opacity:   1,
+		BlendMode: algorithms.BlendNormal,
+	}
+}
+
+
+func CloneLayers(layers []*Layer) []*Layer {
+	clones := make([]*Layer, len(layers))
+	for i, layer := range layers {
+		clone := *layer
+		clones[i] = &clone
+	}
+	return clones
+}
+
+
+func (s *DrawingState) LayerRange(layer int) (int, int) {
+	start := 0
+	for i := 0; i < layer && i < len(s.Layers); i++ {
+		start += s.Layers[i].Count
+	}
+	if layer < 0 || layer >= len(s.Layers) {
+		return start, start
+	}
+	return start, start + s.Layers[layer].Count
+}
+
+
+func (s *DrawingState) LayerShapes(layer int) []Shape {
+	start, end := s.LayerRange(layer)
+	if end > len(s.Shapes) {
+		end = len(s.Shapes)
+	}
+	if start >= end {
+		return nil
+	}
+	return s.Shapes[start:end]
+}
+
+
+func (s *DrawingState) LayerOfIndex(index int) int {
+	start := 0
+	for i, layer := range s.Layers {
+		if index >= start && index < start+layer.Count {
+			return i
+		}
+		start += layer.Count
+	}
+	return -1
+}
+
+
+func (s *DrawingState) LayerOfShape(shape Shape) int {
+	return s.LayerOfIndex(IndexOfShape(s.Shapes, shape))
+}
+
+
+func (s *DrawingState) ActiveInsertIndex() int {
+	if s.ActiveLayer < 0 || s.ActiveLayer >= len(s.Layers) {
+		return len(s.Shapes)
+	}
+	_, end := s.LayerRange(s.ActiveLayer)
+	return end
+}
+
+
+func (s *DrawingState) IsSelectable(shape Shape) bool {
+	layer := s.LayerOfShape(shape)
+	if layer < 0 {
+		return len(s.Layers) == 0
+	}
+	return s.Layers[layer].Visible && !s.Layers[layer].Locked
+}
+
+
+func (s *DrawingState) SelectableShapes() []Shape {
+	if len(s.Layers) == 0 {
+		return s.Shapes
+	}
+
+	var shapes []Shape
+	start := 0
+	for _, layer := range s.Layers {
+		end := min(start+layer.Count, len(s.Shapes))
+		if layer.Visible && !layer.Locked && start < end {
+			shapes = append(shapes, s.Shapes[start:end]...)
+		}
+		start += layer.Count
+	}
+	return shapes
+}
+
+
+func (s *DrawingState) VisibleShapes() []Shape {
+	if len(s.Layers) == 0 {
+		return s.Shapes
+	}
+
+	var shapes []Shape
+	start := 0
+	for _, layer := range s.Layers {
+		end := min(start+layer.Count, len(s.Shapes))
+		if layer.Visible && start < end {
+			shapes = append(shapes, s.Shapes[start:end]...)
+		}
+		start += layer.Count
+	}
+	return shapes
+}
+
+
+func (s *DrawingState) adjustLayerCount(layer, delta int) {
+	if layer >= 0 && layer < len(s.Layers) {
+		s.Layers[layer].Count += delta
+	}
+}
+
+
+func (s *DrawingState) LayerSegments() [][]Shape {
+	segments := make([][]Shape, len(s.Layers))
+	for i := range s.Layers {
+		segments[i] = append([]Shape{}, s.LayerShapes(i)...)
+	}
+	return segments
+}
+
+
+func JoinLayerSegments(segments [][]Shape, layers []*Layer) []Shape {
+	var shapes []Shape
+	for i, segment := range segments {
+		layers[i].Count = len(segment)
+		shapes = append(shapes, segment...)
+	}
+	return shapes
+}
+
+
+type LayerPropertiesCommand struct {
+	Index  int
+	Before Layer
+	After  Layer
+	Label  string
+	Key    string
+}
+
+
+func NewLayerPropertiesCommand(index int, before, after Layer, label string) *LayerPropertiesCommand {
+	return &LayerPropertiesCommand{Index: index, Before: before, After: after, Label: label}
+}
+
+
+func (c *LayerPropertiesCommand) Do(state *DrawingState) {
+	c.apply(state, c.After)
+}
+
+
+func (c *LayerPropertiesCommand) Undo(state *DrawingState) {
+	c.apply(state, c.Before)
+}
+
+
+func (c *LayerPropertiesCommand) apply(state *DrawingState, props Layer) {
+	if c.Index < 0 || c.Index >= len(state.Layers) {
+		return
+	}
+	props.Count = state.Layers[c.Index].Count
+	*state.Layers[c.Index] = props
+}
+
+
+func (c *LayerPropertiesCommand) Coalesce(next Command) bool {
+	other, ok := next.(*LayerPropertiesCommand)
+	if !ok || c.Key == "" || other.Key != c.Key || other.Index != c.Index {
+		return false
+	}
+	c.After = other.After
+	return true
+}
+
+
+func (c *LayerPropertiesCommand) Name() string {
+	return c.Label
+}
+
+
+func (c *LayerPropertiesCommand) Size() int {
+	return 128
+}
+
+
+func NewAddLayerCommand(state *DrawingState, layer *Layer, index int) *SetShapesCommand {
+	layers := CloneLayers(state.Layers)
+	segments := state.LayerSegments()
+	layers = append(layers[:index], append([]*Layer{layer}, layers[index:]...)...)
+	segments = append(segments[:index], append([][]Shape{nil}, segments[index:]...)...)
+
+	shapes := JoinLayerSegments(segments, layers)
+	return NewSetLayersCommand(state, shapes, layers, index, "Add layer")
+}
+
+
+func NewDeleteLayerCommand(state *DrawingState, index int) *SetShapesCommand {
+	layers := CloneLayers(state.Layers)
+	segments := state.LayerSegments()
+	layers = append(layers[:index], layers[index+1:]...)
+	segments = append(segments[:index], segments[index+1:]...)
+
+	shapes := JoinLayerSegments(segments, layers)
+	return NewSetLayersCommand(state, shapes, layers, min(index, len(layers)-1), "Delete layer")
+}
+
+
+func NewMoveLayerCommand(state *DrawingState, index, target int) *SetShapesCommand {
+	layers := CloneLayers(state.Layers)
+	segments := state.LayerSegments()
+	layers[index], layers[target] = layers[target], layers[index]
+	segments[index], segments[target] = segments[target], segments[index]
+
+	shapes := JoinLayerSegments(segments, layers)
+	return NewSetLayersCommand(state, shapes, layers, target, "Reorder layers")
+}
@@ -0,0 +1,278 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+
+func shapeKey(shape Shape) string {
+	if group, ok := shape.(*Group); ok {
+		keys := make([]string, len(group.Children))
+		for i, child := range group.Children {
+			keys[i] = shapeKey(child)
+		}
+		return "[" + strings.Join(keys, " ") + "]"
+	}
+	return fmt.Sprint(shape.(*Line).Start.X)
+}
+
+
+func layerContents(state *DrawingState) map[string]string {
+	contents := make(map[string]string, len(state.Layers))
+	for i, layer := range state.Layers {
+		keys := []string{}
+		for _, shape := range state.LayerShapes(i) {
+			keys = append(keys, shapeKey(shape))
+		}
+		contents[layer.Name] = strings.Join(keys, " ")
+	}
+	return contents
+}
+
+
+func layerNames(state *DrawingState) []string {
+	names := make([]string, len(state.Layers))
+	for i, layer := range state.Layers {
+		names[i] = layer.Name
+	}
+	return names
+}
+
+
+func expectLayers(t *testing.T, state *DrawingState, names []string, contents map[string]string) {
+	t.Helper()
+	total := 0
+	for _, layer := range state.Layers {
+		if layer.Count < 0 {
+			t.Fatalf("layer %s has count %d", layer.Name, layer.Count)
+		}
+		total += layer.Count
+	}
+	if total != len(state.Shapes) {
+		t.Fatalf("layer counts add up to %d, document has %d shapes", total, len(state.Shapes))
+	}
+	if got := layerNames(state); !reflect.DeepEqual(got, names) {
+		t.Fatalf("layers = %v, want %v", got, names)
+	}
+	if got := layerContents(state); !reflect.DeepEqual(got, contents) {
+		t.Fatalf("layer contents = %v, want %v", got, contents)
+	}
+}
+
+
+func layeredState() *DrawingState {
+	state := &DrawingState{SelectedVertex: -1}
+	segments := [][]Shape{
+		{historyLine(0), historyLine(10)},
+		{historyLine(20), NewGroup([]Shape{historyLine(50), historyLine(60)})},
+		{},
+		{historyLine(30), historyLine(40)},
+	}
+	for _, name := range []string{"A", "B", "C", "D"} {
+		state.Layers = append(state.Layers, NewLayer(name))
+	}
+	state.Shapes = JoinLayerSegments(segments, state.Layers)
+	return state
+}
+
+
+func shapeAt(state *DrawingState, key string) Shape {
+	for _, shape := range state.Shapes {
+		if shapeKey(shape) == key {
+			return shape
+		}
+	}
+	return nil
+}
+
+
+func TestLayerMembershipThroughCommands(t *testing.T) {
+	names := []string{"A", "B", "C", "D"}
+	initial := map[string]string{"A": "0 10", "B": "20 [50 60]", "C": "", "D": "30 40"}
+
+	tests := []struct {
+		name     string
+		command  func(state *DrawingState) Command
+		names    []string
+		contents map[string]string
+	}{
+		{
+			name: "add shape to a middle layer",
+			command: func(state *DrawingState) Command {
+				state.ActiveLayer = 1
+				return NewAddShapeCommand(state, historyLine(70))
+			},
+			contents: map[string]string{"A": "0 10", "B": "20 [50 60] 70", "C": "", "D": "30 40"},
+		},
+		{
+			name: "add shape to an empty layer",
+			command: func(state *DrawingState) Command {
+				state.ActiveLayer = 2
+				return NewAddShapeCommand(state, historyLine(70))
+			},
+			contents: map[string]string{"A": "0 10", "B": "20 [50 60]", "C": "70", "D": "30 40"},
+		},
+		{
+			name: "remove the top shape of a layer",
+			command: func(state *DrawingState) Command {
+				return NewRemoveShapeCommand(state, shapeAt(state, "10"))
+			},
+			contents: map[string]string{"A": "0", "B": "20 [50 60]", "C": "", "D": "30 40"},
+		},
+		{
+			name: "remove the last shapes in a layer",
+			command: func(state *DrawingState) Command {
+				return &BatchCommand{Commands: []Command{
+					NewRemoveShapeCommand(state, shapeAt(state, "40")),
+					NewRemoveShapeCommand(state, shapeAt(state, "30")),
+				}}
+			},
+			contents: map[string]string{"A": "0 10", "B": "20 [50 60]", "C": "", "D": ""},
+		},
+		{
+			name: "group shapes in the bottom layer",
+			command: func(state *DrawingState) Command {
+				cmd, _ := NewGroupCommand(state, []Shape{shapeAt(state, "0"), shapeAt(state, "10")})
+				return cmd
+			},
+			contents: map[string]string{"A": "[0 10]", "B": "20 [50 60]", "C": "", "D": "30 40"},
+		},
+		{
+			name: "group shapes in the top layer",
+			command: func(state *DrawingState) Command {
+				cmd, _ := NewGroupCommand(state, []Shape{shapeAt(state, "30"), shapeAt(state, "40")})
+				return cmd
+			},
+			contents: map[string]string{"A": "0 10", "B": "20 [50 60]", "C": "", "D": "[30 40]"},
+		},
+		{
+			name: "ungroup",
+			command: func(state *DrawingState) Command {
+				return NewUngroupCommand(state, []*Group{shapeAt(state, "[50 60]").(*Group)})
+			},
+			contents: map[string]string{"A": "0 10", "B": "20 50 60", "C": "", "D": "30 40"},
+		},
+		{
+			name: "bring to front across layers",
+			command: func(state *DrawingState) Command {
+				order := state.ZOrder([]Shape{shapeAt(state, "0"), shapeAt(state, "20")}, BringToFront)
+				return &ReorderShapesCommand{Order: order}
+			},
+			contents: map[string]string{"A": "10 0", "B": "[50 60] 20", "C": "", "D": "30 40"},
+		},
+		{
+			name: "add layer",
+			command: func(state *DrawingState) Command {
+				return NewAddLayerCommand(state, NewLayer("E"), 2)
+			},
+			names:    []string{"A", "B", "E", "C", "D"},
+			contents: map[string]string{"A": "0 10", "B": "20 [50 60]", "E": "", "C": "", "D": "30 40"},
+		},
+		{
+			name: "delete a non-empty middle layer",
+			command: func(state *DrawingState) Command {
+				return NewDeleteLayerCommand(state, 1)
+			},
+			names:    []string{"A", "C", "D"},
+			contents: map[string]string{"A": "0 10", "C": "", "D": "30 40"},
+		},
+		{
+			name: "delete the non-empty top layer",
+			command: func(state *DrawingState) Command {
+				return NewDeleteLayerCommand(state, 3)
+			},
+			names:    []string{"A", "B", "C"},
+			contents: map[string]string{"A": "0 10", "B": "20 [50 60]", "C": ""},
+		},
+		{
+			name: "move a non-empty layer up",
+			command: func(state *DrawingState) Command {
+				return NewMoveLayerCommand(state, 0, 1)
+			},
+			names:    []string{"B", "A", "C", "D"},
+			contents: map[string]string{"A": "0 10", "B": "20 [50 60]", "C": "", "D": "30 40"},
+		},
+		{
+			name: "move a non-empty layer past an empty one",
+			command: func(state *DrawingState) Command {
+				return NewMoveLayerCommand(state, 3, 2)
+			},
+			names:    []string{"A", "B", "D", "C"},
+			contents: map[string]string{"A": "0 10", "B": "20 [50 60]", "C": "", "D": "30 40"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := layeredState()
+			expectLayers(t, state, names, initial)
+
+			cmd := tt.command(state)
+			cmd.Do(state)
+			wantNames := tt.names
+			if wantNames == nil {
+				wantNames = names
+			}
+			expectLayers(t, state, wantNames, tt.contents)
+
+			cmd.Undo(state)
+			expectLayers(t, state, names, initial)
+
+			cmd.Do(state)
+			expectLayers(t, state, wantNames, tt.contents)
+		})
+	}
+}
+
+
+func TestLayerShapeOrder(t *testing.T) {
+	state := layeredState()
+	cmd := NewMoveLayerCommand(state, 0, 3)
+	cmd.Do(state)
+
+	keys := make([]string, len(state.Shapes))
+	for i, shape := range state.Shapes {
+		keys[i] = shapeKey(shape)
+	}
+	want := "30 40 20 [50 60] 0 10"
+	if got := strings.Join(keys, " "); got != want {
+		t.Fatalf("shape order = %s, want %s", got, want)
+	}
+	if state.ActiveLayer != 3 || state.Layers[3].Name != "A" {
+		t.Fatalf("active layer = %d (%s), want the moved layer at 3", state.ActiveLayer, state.Layers[state.ActiveLayer].Name)
+	}
+}
+
+
+func TestSharedLayer(t *testing.T) {
+	state := layeredState()
+
+	tests := []struct {
+		name  string
+		keys  []string
+		layer int
+		ok    bool
+	}{
+		{name: "same layer", keys: []string{"30", "40"}, layer: 3, ok: true},
+		{name: "single shape", keys: []string{"20"}, layer: 1, ok: true},
+		{name: "adjacent layers", keys: []string{"10", "20"}, layer: -1, ok: false},
+		{name: "bottom and top", keys: []string{"0", "40"}, layer: -1, ok: false},
+		{name: "empty", layer: -1, ok: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var shapes []Shape
+			for _, key := range tt.keys {
+				shapes = append(shapes, shapeAt(state, key))
+			}
+			layer, ok := state.SharedLayer(shapes)
+			if layer != tt.layer || ok != tt.ok {
+				t.Fatalf("SharedLayer = %d, %v, want %d, %v", layer, ok, tt.layer, tt.ok)
+			}
+		})
+	}
+}
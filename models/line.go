@@ -12,6 +12,7 @@ type Line struct {
 	Color     color.Color
 	Thickness int
 	PenType   string 
+	RasterChoice
 }
 
 
@@ -30,43 +31,35 @@ func NewLine(start, end Point, color color.Color, thickness int, penType string)
 
 
 func (l *Line) Draw(canvas [][]color.Color, antiAliasing bool) {
-	if l.PenType == "regular" {
+	l.drawTraced(canvas, antiAliasing, RasterAlgorithms{}, nil)
+}
+
 
-		if antiAliasing {
-			drawXiaolinWuLine(canvas, l.Start.X, l.Start.Y, l.End.X, l.End.Y, l.Color)
-		} else {
-			drawMidpointLine(canvas, l.Start.X, l.Start.Y, l.End.X, l.End.Y, l.Color)
-		}
-	} else { 
-		if antiAliasing {
-			drawXiaolinWuLine(canvas, l.Start.X, l.Start.Y, l.End.X, l.End.Y, l.Color)
-		} else {
-			if l.Thickness == 1 {
-				drawMidpointLine(canvas, l.Start.X, l.Start.Y, l.End.X, l.End.Y, l.Color)
-			} else {
-				drawThickLine(canvas, l.Start.X, l.Start.Y, l.End.X, l.End.Y, l.Color, l.Thickness)
-			}
-		}
+func (l *Line) drawTraced(canvas [][]color.Color, antiAliasing bool, defaults RasterAlgorithms, tracer *pixelTracer) {
+	thickness := l.Thickness
+	if l.PenType == "regular" {
+		thickness = 1
 	}
+	drawLine(canvas, tracer, l.resolved(defaults), antiAliasing, l.Start.X, l.Start.Y, l.End.X, l.End.Y, l.Color, thickness)
 }
 
 
 func (l *Line) Contains(p Point) bool {
 	
-	lineLen := math.Sqrt(float64((l.End.X-l.Start.X)*(l.End.X-l.Start.X) + (l.End.Y-l.Start.Y)*(l.End.Y-l.Start.Y)))
+	lineLen := math.Sqrt((l.End.X-l.Start.X)*(l.End.X-l.Start.X) + (l.End.Y-l.Start.Y)*(l.End.Y-l.Start.Y))
 	if lineLen == 0 {
 
-		return math.Abs(float64(p.X-l.Start.X)) <= 5 && math.Abs(float64(p.Y-l.Start.Y)) <= 5
+		return math.Abs(p.X-l.Start.X) <= 5 && math.Abs(p.Y-l.Start.Y) <= 5
 	}
 	
 	
-	t := float64((p.X-l.Start.X)*(l.End.X-l.Start.X) + (p.Y-l.Start.Y)*(l.End.Y-l.Start.Y)) / (lineLen * lineLen)
+	t := ((p.X-l.Start.X)*(l.End.X-l.Start.X) + (p.Y-l.Start.Y)*(l.End.Y-l.Start.Y)) / (lineLen * lineLen)
 	t = math.Max(0, math.Min(1, t))
 	
-	nearestX := l.Start.X + int(float64(l.End.X-l.Start.X)*t)
-	nearestY := l.Start.Y + int(float64(l.End.Y-l.Start.Y)*t)
+	nearestX := l.Start.X + (l.End.X-l.Start.X)*t
+	nearestY := l.Start.Y + (l.End.Y-l.Start.Y)*t
 	
-	dist := math.Sqrt(float64((p.X-nearestX)*(p.X-nearestX) + (p.Y-nearestY)*(p.Y-nearestY)))
+	dist := math.Sqrt((p.X-nearestX)*(p.X-nearestX) + (p.Y-nearestY)*(p.Y-nearestY))
 	return dist <= float64(l.Thickness+5) 
 }
 
@@ -76,7 +69,7 @@ func (l *Line) GetControlPoints() []Point {
 }
 
 
-func (l *Line) Move(deltaX, deltaY int) {
+func (l *Line) Move(deltaX, deltaY float64) {
 	l.Start.X += deltaX
 	l.Start.Y += deltaY
 	l.End.X += deltaX
@@ -94,27 +87,40 @@ func (l *Line) GetColor() color.Color {
 }
 
 
-func (l *Line) Serialize() map[string]interface{} {
-	r, g, b, a := l.Color.RGBA()
-	return map[string]interface{}{
-		"type":      "line",
-		"startX":    l.Start.X,
-		"startY":    l.Start.Y,
-		"endX":      l.End.X,
-		"endY":      l.End.Y,
-		"color":     []uint32{r, g, b, a},
-		"thickness": l.Thickness,
-		"penType":   l.PenType,
-	}
+func (l *Line) GetThickness() int {
+	return l.Thickness
+}
+
+
+func (l *Line) SetThickness(thickness int) {
+	l.Thickness = thickness
 }
 
 
 func (l *Line) Clone() Shape {
-	return NewLine(
+	clone := NewLine(
 		Point{X: l.Start.X, Y: l.Start.Y},
 		Point{X: l.End.X, Y: l.End.Y},
 		l.Color,
 		l.Thickness,
 		l.PenType,
 	)
-}
\ No newline at end of file
+	clone.Raster = l.Raster
+	return clone
+}
+
+
+func (l *Line) ToPath() *Path {
+	thickness := 1
+	if l.PenType != "regular" {
+		thickness = l.Thickness
+	}
+	return polylinePath([]Point{l.Start, l.End}, false, l.Color, thickness)
+}
+
+
+func (l *Line) Transform(m Matrix) Shape {
+	l.Start = m.Apply(l.Start)
+	l.End = m.Apply(l.End)
+	return l
+}
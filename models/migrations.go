@@ -0,0 +1,142 @@
+package models
+
+import (
+	"fmt"
+)
+
+
+type Migration func(data map[string]interface{}) error
+
+
+var migrations = map[int]Migration{
+	0: migrateShapesToLayers,
+	1: migrateLegacyShapes,
+}
+
+
+func documentVersion(data map[string]interface{}) int {
+	if version, ok := data["version"].(float64); ok {
+		return int(version)
+	}
+	if _, ok := data["layers"]; ok {
+		return 1
+	}
+	return 0
+}
+
+
+func MigrateDocument(data map[string]interface{}, from int) error {
+	for version := from; version < DocumentVersion; version++ {
+		migrate, ok := migrations[version]
+		if !ok {
+			return fmt.Errorf("no migration from document version %d", version)
+		}
+		if err := migrate(data); err != nil {
+			return fmt.Errorf("migrating document from version %d: %v", version, err)
+		}
+		data["version"] = version + 1
+	}
+	return nil
+}
+
+
+func migrateShapesToLayers(data map[string]interface{}) error {
+	shapes, ok := data["shapes"].([]interface{})
+	if !ok {
+		return fmt.Errorf("invalid shapes data format")
+	}
+
+	layer := NewLayer("Layer 1")
+	data["layers"] = []interface{}{
+		map[string]interface{}{
+			"name":      layer.Name,
+			"visible":   layer.Visible,
+			"locked":    layer.Locked,
+			"opacity":   layer.Opacity,
+			"blendMode": string(layer.BlendMode),
+			"shapes":    shapes,
+		},
+	}
+	delete(data, "shapes")
+	return nil
+}
+
+
+func migrateLegacyShapes(data map[string]interface{}) error {
+	layers, ok := data["layers"].([]interface{})
+	if !ok {
+		return fmt.Errorf("invalid layers data format")
+	}
+
+	defaults := NewLayer("")
+	for _, layerData := range layers {
+		layer, ok := layerData.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		setDefault(layer, "visible", defaults.Visible)
+		setDefault(layer, "opacity", defaults.Opacity)
+		setDefault(layer, "blendMode", string(defaults.BlendMode))
+
+		shapes, _ := layer["shapes"].([]interface{})
+		for _, shape := range shapes {
+			if shapeMap, ok := shape.(map[string]interface{}); ok {
+				migrateLegacyShape(shapeMap)
+			}
+		}
+	}
+	return nil
+}
+
+
+func migrateLegacyShape(shape map[string]interface{}) {
+	joinPoint(shape, "center", "centerX", "centerY")
+	joinPoint(shape, "start", "startX", "startY")
+	joinPoint(shape, "end", "endX", "endY")
+	migrateLegacyColor(shape, "color")
+	migrateLegacyColor(shape, "fillColor")
+
+	children, _ := shape["children"].([]interface{})
+	for _, child := range children {
+		if childMap, ok := child.(map[string]interface{}); ok {
+			migrateLegacyShape(childMap)
+		}
+	}
+}
+
+
+func setDefault(data map[string]interface{}, key string, value interface{}) {
+	if _, ok := data[key]; !ok {
+		data[key] = value
+	}
+}
+
+
+func joinPoint(shape map[string]interface{}, key, xKey, yKey string) {
+	x, hasX := shape[xKey]
+	y, hasY := shape[yKey]
+	if !hasX && !hasY {
+		return
+	}
+	if _, exists := shape[key]; !exists {
+		shape[key] = map[string]interface{}{"X": x, "Y": y}
+	}
+	delete(shape, xKey)
+	delete(shape, yKey)
+}
+
+
+func migrateLegacyColor(shape map[string]interface{}, key string) {
+	channels, ok := shape[key].([]interface{})
+	if !ok || len(channels) != 4 {
+		return
+	}
+
+	names := []string{"R", "G", "B", "A"}
+	converted := map[string]interface{}{}
+	for i, channel := range channels {
+		value, _ := channel.(float64)
+		converted[names[i]] = float64(uint32(value) >> 8)
+	}
+	shape[key] = converted
+}
@@ -0,0 +1,153 @@
+package models
+
+import (
+	"encoding/json"
+	"image/color"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+
+func TestLegacyDocumentsMigrate(t *testing.T) {
+	black := color.RGBA{0, 0, 0, 255}
+
+	legacyPolygon := NewPolygon([]Point{{X: 10, Y: 10}, {X: 40, Y: 10}, {X: 25, Y: 40}}, black, 1)
+	legacyPolygon.SetFillColor(color.RGBA{0, 128, 0, 255})
+
+	legacyRectangle := NewRectangle(Point{X: 5, Y: 5}, Point{X: 95, Y: 45}, black, 2)
+	legacyRectangle.SetFillColor(color.RGBA{255, 255, 0, 255})
+
+	legacyPill := NewPill(Point{X: 10, Y: 20}, 8, color.RGBA{128, 0, 128, 255})
+	legacyPill.End = Point{X: 70, Y: 20}
+	legacyPill.Step = 3
+
+	lockedInk := NewLayer("Ink")
+	lockedInk.Locked = true
+
+	tests := []struct {
+		file    string
+		version int
+		layers  []*Layer
+		shapes  []Shape
+	}{
+		{
+			file:    "v0.json",
+			version: 0,
+			layers:  []*Layer{NewLayer("Layer 1")},
+			shapes: []Shape{
+				NewCircle(Point{X: 50, Y: 60}, 20, color.RGBA{255, 0, 0, 255}),
+				NewLine(Point{X: 0, Y: 0}, Point{X: 100, Y: 50}, color.RGBA{0, 0, 255, 255}, 3, "brush"),
+				legacyPolygon,
+			},
+		},
+		{
+			file:    "v1.json",
+			version: 1,
+			layers:  []*Layer{NewLayer("Background"), lockedInk},
+			shapes: []Shape{
+				legacyRectangle,
+				legacyPill,
+				NewGroup([]Shape{NewCircle(Point{X: 30, Y: 30}, 5, color.RGBA{0, 255, 0, 255})}),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join("testdata", tt.file)
+
+			data, err := readFixture(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if version := documentVersion(data); version != tt.version {
+				t.Fatalf("documentVersion = %d, want %d", version, tt.version)
+			}
+
+			doc, report, err := LoadDocument(path)
+			if err != nil {
+				t.Fatalf("LoadDocument: %v", err)
+			}
+			if !report.Empty() {
+				t.Fatalf("legacy document reported problems: %v", report.Diagnostics)
+			}
+
+			if len(doc.Layers) != len(tt.layers) {
+				t.Fatalf("loaded %d layers, want %d", len(doc.Layers), len(tt.layers))
+			}
+			for i, want := range tt.layers {
+				got := *doc.Layers[i]
+				got.Count = 0
+				if got != *want {
+					t.Errorf("layer %d = %+v, want %+v", i, got, *want)
+				}
+			}
+
+			if len(doc.Shapes) != len(tt.shapes) {
+				t.Fatalf("loaded %d shapes, want %d", len(doc.Shapes), len(tt.shapes))
+			}
+			for i, want := range tt.shapes {
+				if !reflect.DeepEqual(doc.Shapes[i], want) {
+					t.Errorf("shape %d differs in %s\n got: %#v\nwant: %#v", i, differingField(doc.Shapes[i], want), doc.Shapes[i], want)
+				}
+			}
+
+			saved, err := MarshalDocument(doc.State())
+			if err != nil {
+				t.Fatalf("MarshalDocument: %v", err)
+			}
+			resaved, _, err := ParseDocument(saved)
+			if err != nil {
+				t.Fatalf("ParseDocument: %v", err)
+			}
+			if !reflect.DeepEqual(resaved.Shapes, doc.Shapes) {
+				t.Fatalf("migrated document changed after a save and load")
+			}
+		})
+	}
+}
+
+
+func TestDocumentVersion(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want int
+	}{
+		{name: "top-level shapes", data: `{"shapes": []}`, want: 0},
+		{name: "layers without version", data: `{"layers": []}`, want: 1},
+		{name: "explicit version", data: `{"version": 2, "layers": []}`, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.data), &data); err != nil {
+				t.Fatal(err)
+			}
+			if got := documentVersion(data); got != tt.want {
+				t.Fatalf("documentVersion = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+
+func TestNewerDocumentIsRefused(t *testing.T) {
+	if _, _, err := ParseDocument([]byte(`{"version": 99, "layers": []}`)); err == nil {
+		t.Fatalf("ParseDocument accepted a document from a newer version")
+	}
+}
+
+
+func readFixture(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var document map[string]interface{}
+	err = json.Unmarshal(data, &document)
+	return document, err
+}
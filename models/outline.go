@@ -0,0 +1,113 @@
+package models
+
+import (
+	"image/color"
+	"math"
+	"paint-drawer-pro/algorithms"
+)
+
+
+type Outliner interface {
+	Outline(join algorithms.JoinType) *Polygon
+}
+
+
+func (l *Line) Outline(join algorithms.JoinType) *Polygon {
+	width := 1
+	if l.PenType != "regular" {
+		width = l.Thickness
+	}
+	outline := algorithms.StrokeOutline(
+		toAlgorithmPoints([]Point{l.Start, l.End}),
+		false, float64(width), join, algorithms.RoundCap, algorithms.DefaultMiterLimit,
+	)
+	return newOutlinePolygon(outline, l.Color)
+}
+
+
+func (p *Pill) Outline(join algorithms.JoinType) *Polygon {
+	outline := algorithms.StrokeOutline(
+		toAlgorithmPoints([]Point{p.Start, p.End}),
+		false, 2*p.Radius, join, algorithms.RoundCap, algorithms.DefaultMiterLimit,
+	)
+	return newOutlinePolygon(outline, p.Color)
+}
+
+
+func (p *Polygon) Outline(join algorithms.JoinType) *Polygon {
+	outline := algorithms.StrokeOutline(
+		toAlgorithmPoints(p.Vertices),
+		true, float64(p.Thickness), join, algorithms.ButtCap, algorithms.DefaultMiterLimit,
+	)
+	return newOutlinePolygon(outline, p.Color)
+}
+
+
+func (r *Rectangle) Outline(join algorithms.JoinType) *Polygon {
+	outline := algorithms.StrokeOutline(
+		toAlgorithmPoints(r.GetVertices()),
+		true, float64(r.Thickness), join, algorithms.ButtCap, algorithms.DefaultMiterLimit,
+	)
+	return newOutlinePolygon(outline, r.Color)
+}
+
+
+func (c *Circle) Outline(join algorithms.JoinType) *Polygon {
+	segments := int(c.Radius * 2)
+	if segments < 16 {
+		segments = 16
+	}
+	points := make([]Point, segments)
+	for i := 0; i < segments; i++ {
+		angle := 2.0 * math.Pi * float64(i) / float64(segments)
+		points[i] = Point{
+			X: c.Center.X + c.Radius*math.Cos(angle),
+			Y: c.Center.Y + c.Radius*math.Sin(angle),
+		}
+	}
+	outline := algorithms.StrokeOutline(
+		toAlgorithmPoints(points),
+		true, 1, join, algorithms.ButtCap, algorithms.DefaultMiterLimit,
+	)
+	return newOutlinePolygon(outline, c.Color)
+}
+
+
+func (p *Polygon) Offset(distance float64, join algorithms.JoinType) *Polygon {
+	offset := algorithms.OffsetPolygon(toAlgorithmPoints(p.Vertices), distance, join, algorithms.DefaultMiterLimit)
+	if len(offset) < 3 {
+		return nil
+	}
+
+	result := p.Clone().(*Polygon)
+	result.Vertices = fromAlgorithmPoints(offset)
+	return result
+}
+
+
+func newOutlinePolygon(outline []algorithms.Point, c color.Color) *Polygon {
+	if len(outline) < 3 {
+		return nil
+	}
+	poly := NewPolygon(fromAlgorithmPoints(outline), c, 1)
+	poly.SetFillColor(c)
+	return poly
+}
+
+
+func toAlgorithmPoints(points []Point) []algorithms.Point {
+	algPoints := make([]algorithms.Point, len(points))
+	for i, p := range points {
+		algPoints[i] = algorithms.Point{X: p.X, Y: p.Y}
+	}
+	return algPoints
+}
+
+
+func fromAlgorithmPoints(points []algorithms.Point) []Point {
+	modelPoints := make([]Point, len(points))
+	for i, p := range points {
+		modelPoints[i] = Point{X: p.X, Y: p.Y}
+	}
+	return modelPoints
+}
@@ -0,0 +1,431 @@
+package models
+
+import (
+	"image/color"
+	"math"
+	"paint-drawer-pro/algorithms"
+)
+
+
+type PathCommandType string
+
+const (
+	MoveToCommand  PathCommandType = "M"
+	LineToCommand  PathCommandType = "L"
+	QuadToCommand  PathCommandType = "Q"
+	CubicToCommand PathCommandType = "C"
+	ArcToCommand   PathCommandType = "A"
+	CloseCommand   PathCommandType = "Z"
+)
+
+
+type PathCommand struct {
+	Type       PathCommandType
+	Points     []Point
+	Radius     float64
+	StartAngle float64
+	Sweep      float64
+}
+
+
+type Subpath struct {
+	Points []Point
+	Closed bool
+}
+
+
+type Path struct {
+	Commands  []PathCommand
+	Color     color.Color
+	Thickness int
+	FillColor color.Color
+	IsFilled  bool
+	RasterChoice
+}
+
+
+func NewPath(color color.Color, thickness int) *Path {
+	if thickness <= 0 {
+		thickness = 1
+	}
+	return &Path{
+		Commands:  []PathCommand{},
+		Color:     color,
+		Thickness: thickness,
+	}
+}
+
+
+func (p *Path) MoveTo(pt Point) *Path {
+	p.Commands = append(p.Commands, PathCommand{Type: MoveToCommand, Points: []Point{pt}})
+	return p
+}
+
+
+func (p *Path) LineTo(pt Point) *Path {
+	p.Commands = append(p.Commands, PathCommand{Type: LineToCommand, Points: []Point{pt}})
+	return p
+}
+
+
+func (p *Path) QuadTo(control, pt Point) *Path {
+	p.Commands = append(p.Commands, PathCommand{Type: QuadToCommand, Points: []Point{control, pt}})
+	return p
+}
+
+
+func (p *Path) CubicTo(control1, control2, pt Point) *Path {
+	p.Commands = append(p.Commands, PathCommand{Type: CubicToCommand, Points: []Point{control1, control2, pt}})
+	return p
+}
+
+
+func (p *Path) ArcTo(center Point, radius, startAngle, sweep float64) *Path {
+	p.Commands = append(p.Commands, PathCommand{
+		Type:       ArcToCommand,
+		Points:     []Point{center},
+		Radius:     radius,
+		StartAngle: startAngle,
+		Sweep:      sweep,
+	})
+	return p
+}
+
+
+func (p *Path) Close() *Path {
+	p.Commands = append(p.Commands, PathCommand{Type: CloseCommand})
+	return p
+}
+
+
+func (p *Path) Subpaths() []Subpath {
+	var subpaths []Subpath
+	var current []Point
+
+	flush := func(closed bool) {
+		if len(current) > 1 {
+			subpaths = append(subpaths, Subpath{Points: current, Closed: closed})
+		}
+		current = nil
+	}
+
+	last := func() Point {
+		if len(current) == 0 {
+			return Point{}
+		}
+		return current[len(current)-1]
+	}
+
+	for _, cmd := range p.Commands {
+		switch cmd.Type {
+		case MoveToCommand:
+			flush(false)
+			current = []Point{cmd.Points[0]}
+		case LineToCommand:
+			current = appendPathPoint(current, cmd.Points[0])
+		case QuadToCommand:
+			start := last()
+			steps := curveSteps(start, cmd.Points[0], cmd.Points[1])
+			for i := 1; i <= steps; i++ {
+				t := float64(i) / float64(steps)
+				mt := 1 - t
+				x := mt*mt*start.X + 2*mt*t*cmd.Points[0].X + t*t*cmd.Points[1].X
+				y := mt*mt*start.Y + 2*mt*t*cmd.Points[0].Y + t*t*cmd.Points[1].Y
+				current = appendPathPoint(current, Point{X: x, Y: y})
+			}
+		case CubicToCommand:
+			start := last()
+			steps := curveSteps(start, cmd.Points[0], cmd.Points[1], cmd.Points[2])
+			for i := 1; i <= steps; i++ {
+				t := float64(i) / float64(steps)
+				mt := 1 - t
+				x := mt*mt*mt*start.X + 3*mt*mt*t*cmd.Points[0].X +
+					3*mt*t*t*cmd.Points[1].X + t*t*t*cmd.Points[2].X
+				y := mt*mt*mt*start.Y + 3*mt*mt*t*cmd.Points[0].Y +
+					3*mt*t*t*cmd.Points[1].Y + t*t*t*cmd.Points[2].Y
+				current = appendPathPoint(current, Point{X: x, Y: y})
+			}
+		case ArcToCommand:
+			center := cmd.Points[0]
+			steps := int(math.Ceil(math.Abs(cmd.Sweep) * math.Max(cmd.Radius, 4) / 4))
+			if steps < 8 {
+				steps = 8
+			}
+			for i := 0; i <= steps; i++ {
+				angle := cmd.StartAngle + cmd.Sweep*float64(i)/float64(steps)
+				current = appendPathPoint(current, Point{
+					X: center.X + cmd.Radius*math.Cos(angle),
+					Y: center.Y + cmd.Radius*math.Sin(angle),
+				})
+			}
+		case CloseCommand:
+			if len(current) > 1 && current[0] == current[len(current)-1] {
+				current = current[:len(current)-1]
+			}
+			start := Point{}
+			if len(current) > 0 {
+				start = current[0]
+			}
+			flush(true)
+			current = []Point{start}
+		}
+	}
+
+	flush(false)
+
+	return subpaths
+}
+
+
+func appendPathPoint(points []Point, pt Point) []Point {
+	if len(points) > 0 && points[len(points)-1] == pt {
+		return points
+	}
+	return append(points, pt)
+}
+
+
+func curveSteps(points ...Point) int {
+	length := 0.0
+	for i := 1; i < len(points); i++ {
+		dx := points[i].X - points[i-1].X
+		dy := points[i].Y - points[i-1].Y
+		length += math.Sqrt(dx*dx + dy*dy)
+	}
+	steps := int(length / 4)
+	if steps < 8 {
+		steps = 8
+	}
+	return steps
+}
+
+
+func (p *Path) Draw(canvas [][]color.Color, antiAliasing bool) {
+	p.drawTraced(canvas, antiAliasing, RasterAlgorithms{}, nil)
+}
+
+
+func (p *Path) drawTraced(canvas [][]color.Color, antiAliasing bool, defaults RasterAlgorithms, tracer *pixelTracer) {
+	subpaths := p.Subpaths()
+	raster := p.resolved(defaults)
+
+	if p.IsFilled && p.FillColor != nil {
+		rings := make([][]algorithms.Point, 0, len(subpaths))
+		for _, sp := range subpaths {
+			rings = append(rings, toAlgorithmPoints(sp.Points))
+		}
+		if !drawNamedFill(canvas, tracer, raster, rings, p.FillColor) {
+			tracer.traceRings(canvas, "EdgeTableFillRings", rings, func() {
+				algorithms.EdgeTableFillRings(canvas, rings, p.FillColor)
+			})
+		}
+	}
+
+	for _, sp := range subpaths {
+		count := len(sp.Points) - 1
+		if sp.Closed {
+			count = len(sp.Points)
+		}
+		for i := 0; i < count; i++ {
+			start := sp.Points[i]
+			end := sp.Points[(i+1)%len(sp.Points)]
+
+			drawLine(canvas, tracer, raster, antiAliasing, start.X, start.Y, end.X, end.Y, p.Color, p.Thickness)
+		}
+	}
+}
+
+
+func (p *Path) Contains(pt Point) bool {
+	subpaths := p.Subpaths()
+
+	for _, sp := range subpaths {
+		count := len(sp.Points) - 1
+		if sp.Closed {
+			count = len(sp.Points)
+		}
+		for i := 0; i < count; i++ {
+			start := sp.Points[i]
+			end := sp.Points[(i+1)%len(sp.Points)]
+			if distanceToSegment(pt, start, end) <= float64(p.Thickness+5) {
+				return true
+			}
+		}
+	}
+
+	if p.IsFilled {
+		inside := false
+		for _, sp := range subpaths {
+			if pointInRing(pt, sp.Points) {
+				inside = !inside
+			}
+		}
+		return inside
+	}
+
+	return false
+}
+
+
+func distanceToSegment(p, a, b Point) float64 {
+	dx := b.X - a.X
+	dy := b.Y - a.Y
+	lenSq := dx*dx + dy*dy
+	if lenSq == 0 {
+		return math.Hypot(p.X-a.X, p.Y-a.Y)
+	}
+	t := ((p.X-a.X)*dx + (p.Y-a.Y)*dy) / lenSq
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(p.X-(a.X+t*dx), p.Y-(a.Y+t*dy))
+}
+
+
+func pointInRing(p Point, ring []Point) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Y > p.Y) != (b.Y > p.Y) {
+			x := (b.X-a.X)*(p.Y-a.Y)/(b.Y-a.Y) + a.X
+			if p.X < x {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+
+func (p *Path) GetControlPoints() []Point {
+	var points []Point
+	for _, cmd := range p.Commands {
+		points = append(points, cmd.Points...)
+	}
+	return points
+}
+
+
+func (p *Path) Move(deltaX, deltaY float64) {
+	for i := range p.Commands {
+		for j := range p.Commands[i].Points {
+			p.Commands[i].Points[j].X += deltaX
+			p.Commands[i].Points[j].Y += deltaY
+		}
+	}
+}
+
+
+func (p *Path) SetColor(c color.Color) {
+	p.Color = c
+}
+
+
+func (p *Path) GetColor() color.Color {
+	return p.Color
+}
+
+
+func (p *Path) GetThickness() int {
+	return p.Thickness
+}
+
+
+func (p *Path) SetThickness(thickness int) {
+	p.Thickness = thickness
+}
+
+
+func (p *Path) SetFillColor(c color.Color) {
+	p.FillColor = c
+	p.IsFilled = true
+}
+
+
+func (p *Path) DisableFill() {
+	p.IsFilled = false
+}
+
+
+func (p *Path) Clone() Shape {
+	clone := NewPath(p.Color, p.Thickness)
+	clone.Raster = p.Raster
+	clone.FillColor = p.FillColor
+	clone.IsFilled = p.IsFilled
+	clone.Commands = make([]PathCommand, len(p.Commands))
+	for i, cmd := range p.Commands {
+		clone.Commands[i] = cmd
+		clone.Commands[i].Points = append([]Point(nil), cmd.Points...)
+	}
+	return clone
+}
+
+
+func (p *Path) ToPath() *Path {
+	return p.Clone().(*Path)
+}
+
+
+func polylinePath(points []Point, closed bool, c color.Color, thickness int) *Path {
+	path := NewPath(c, thickness)
+	for i, pt := range points {
+		if i == 0 {
+			path.MoveTo(pt)
+		} else {
+			path.LineTo(pt)
+		}
+	}
+	if closed {
+		path.Close()
+	}
+	return path
+}
+
+
+func (p *Path) Transform(m Matrix) Shape {
+	p.expandArcs()
+	for i := range p.Commands {
+		for j := range p.Commands[i].Points {
+			p.Commands[i].Points[j] = m.Apply(p.Commands[i].Points[j])
+		}
+	}
+	return p
+}
+
+
+func (p *Path) expandArcs() {
+	commands := make([]PathCommand, 0, len(p.Commands))
+	for _, cmd := range p.Commands {
+		if cmd.Type != ArcToCommand {
+			commands = append(commands, cmd)
+			continue
+		}
+
+		center := cmd.Points[0]
+		cx, cy, r := center.X, center.Y, cmd.Radius
+		at := func(angle float64) Point {
+			return Point{X: cx + r*math.Cos(angle), Y: cy + r*math.Sin(angle)}
+		}
+
+		commands = append(commands, PathCommand{Type: LineToCommand, Points: []Point{at(cmd.StartAngle)}})
+
+		segments := int(math.Ceil(math.Abs(cmd.Sweep) / (math.Pi / 2)))
+		if segments < 1 {
+			segments = 1
+		}
+		step := cmd.Sweep / float64(segments)
+		k := 4.0 / 3.0 * math.Tan(step/4)
+		for i := 0; i < segments; i++ {
+			a0 := cmd.StartAngle + step*float64(i)
+			a1 := a0 + step
+			c1 := Point{
+				X: cx + r*(math.Cos(a0)-k*math.Sin(a0)),
+				Y: cy + r*(math.Sin(a0)+k*math.Cos(a0)),
+			}
+			c2 := Point{
+				X: cx + r*(math.Cos(a1)+k*math.Sin(a1)),
+				Y: cy + r*(math.Sin(a1)-k*math.Cos(a1)),
+			}
+			commands = append(commands, PathCommand{Type: CubicToCommand, Points: []Point{c1, c2, at(a1)}})
+		}
+	}
+	p.Commands = commands
+}
@@ -10,13 +10,14 @@ import (
 type Pill struct {
 	Start    Point     
 	End      Point     
-	Radius   int       
+	Radius   float64   
 	Color    color.Color
 	Step     int       
+	RasterChoice
 }
 
 
-func NewPill(start Point, radius int, color color.Color) *Pill {
+func NewPill(start Point, radius float64, color color.Color) *Pill {
 	return &Pill{
 		Start:   start,
 		End:     start, 
@@ -28,80 +29,79 @@ func NewPill(start Point, radius int, color color.Color) *Pill {
 
 
 func (p *Pill) Draw(canvas [][]color.Color, antiAliasing bool) {
+	p.drawTraced(canvas, antiAliasing, RasterAlgorithms{}, nil)
+}
+
+
+func (p *Pill) drawTraced(canvas [][]color.Color, antiAliasing bool, defaults RasterAlgorithms, tracer *pixelTracer) {
+    raster := p.resolved(defaults)
     if p.Step == 1 {	
-        drawMidpointCircle(canvas, p.Start.X, p.Start.Y, 5, p.Color)
+        drawCircle(canvas, tracer, raster, false, p.Start.X, p.Start.Y, 5, p.Color)
         return
     }
     
     if p.Step == 2 {
-        if antiAliasing {
-            drawXiaolinWuCircle(canvas, p.Start.X, p.Start.Y, p.Radius, p.Color)
-        } else {
-            drawMidpointCircle(canvas, p.Start.X, p.Start.Y, p.Radius, p.Color)
-        }
+        drawCircle(canvas, tracer, raster, antiAliasing, p.Start.X, p.Start.Y, p.Radius, p.Color)
         return
     }
 
     dx := p.End.X - p.Start.X
     dy := p.End.Y - p.Start.Y
-    length := math.Sqrt(float64(dx*dx + dy*dy))
+    length := math.Sqrt(dx*dx + dy*dy)
     
-    if length < float64(p.Radius) {
-        if antiAliasing {
-            drawXiaolinWuCircle(canvas, p.Start.X, p.Start.Y, p.Radius, p.Color)
-        } else {
-            drawMidpointCircle(canvas, p.Start.X, p.Start.Y, p.Radius, p.Color)
-        }
+    if length < p.Radius {
+        drawCircle(canvas, tracer, raster, antiAliasing, p.Start.X, p.Start.Y, p.Radius, p.Color)
         return
     }
     
-    dirX := float64(dx) / length
-    dirY := float64(dy) / length
+    dirX := dx / length
+    dirY := dy / length
     
     perpX := -dirY
     perpY := dirX
 
-    rectStartX := p.Start.X + int(dirX*float64(p.Radius))
-    rectStartY := p.Start.Y + int(dirY*float64(p.Radius))
+    rectStartX := p.Start.X + dirX*p.Radius
+    rectStartY := p.Start.Y + dirY*p.Radius
     
-    rectEndX := p.End.X - int(dirX*float64(p.Radius))
-    rectEndY := p.End.Y - int(dirY*float64(p.Radius))
+    rectEndX := p.End.X - dirX*p.Radius
+    rectEndY := p.End.Y - dirY*p.Radius
     
     topLeft := Point{
-        X: rectStartX + int(perpX*float64(p.Radius)),
-        Y: rectStartY + int(perpY*float64(p.Radius)),
+        X: rectStartX + perpX*p.Radius,
+        Y: rectStartY + perpY*p.Radius,
     }
     
     bottomLeft := Point{
-        X: rectStartX - int(perpX*float64(p.Radius)),
-        Y: rectStartY - int(perpY*float64(p.Radius)),
+        X: rectStartX - perpX*p.Radius,
+        Y: rectStartY - perpY*p.Radius,
     }
     
     topRight := Point{
-        X: rectEndX + int(perpX*float64(p.Radius)),
-        Y: rectEndY + int(perpY*float64(p.Radius)),
+        X: rectEndX + perpX*p.Radius,
+        Y: rectEndY + perpY*p.Radius,
     }
     
     bottomRight := Point{
-        X: rectEndX - int(perpX*float64(p.Radius)),
-        Y: rectEndY - int(perpY*float64(p.Radius)),
+        X: rectEndX - perpX*p.Radius,
+        Y: rectEndY - perpY*p.Radius,
     }
     
-    if antiAliasing {
-        drawXiaolinWuLine(canvas, topLeft.X, topLeft.Y, topRight.X, topRight.Y, p.Color)
-        drawXiaolinWuLine(canvas, bottomLeft.X, bottomLeft.Y, bottomRight.X, bottomRight.Y, p.Color)
-    } else {
-        drawMidpointLine(canvas, topLeft.X, topLeft.Y, topRight.X, topRight.Y, p.Color)
-        drawMidpointLine(canvas, bottomLeft.X, bottomLeft.Y, bottomRight.X, bottomRight.Y, p.Color)
-    }
+    drawLine(canvas, tracer, raster, antiAliasing, topLeft.X, topLeft.Y, topRight.X, topRight.Y, p.Color, 1)
+    drawLine(canvas, tracer, raster, antiAliasing, bottomLeft.X, bottomLeft.Y, bottomRight.X, bottomRight.Y, p.Color, 1)
     
-	drawSemicircleOutline(canvas, p.Start.X, p.Start.Y, p.Radius, -dirX, -dirY, p.Color, antiAliasing)
-	drawSemicircleOutline(canvas, p.End.X, p.End.Y, p.Radius, dirX, dirY, p.Color, antiAliasing)
+	ends := [][]algorithms.Point{{
+		{X: math.Min(p.Start.X, p.End.X) - p.Radius - 1, Y: math.Min(p.Start.Y, p.End.Y) - p.Radius - 1},
+		{X: math.Max(p.Start.X, p.End.X) + p.Radius + 1, Y: math.Max(p.Start.Y, p.End.Y) + p.Radius + 1},
+	}}
+	tracer.traceRings(canvas, "Semicircle sampling", ends, func() {
+		drawSemicircleOutline(canvas, p.Start.X, p.Start.Y, p.Radius, -dirX, -dirY, p.Color, antiAliasing)
+		drawSemicircleOutline(canvas, p.End.X, p.End.Y, p.Radius, dirX, dirY, p.Color, antiAliasing)
+	})
 }
 
 
-func drawSemicircleOutline(canvas [][]color.Color, centerX, centerY, radius int, dirX, dirY float64, c color.Color, antiAliasing bool) {
-	numSegments := radius * 8 
+func drawSemicircleOutline(canvas [][]color.Color, centerX, centerY, radius float64, dirX, dirY float64, c color.Color, antiAliasing bool) {
+	numSegments := int(radius * 8) 
 	
 	if numSegments < 16 {
 			numSegments = 16 
@@ -109,8 +109,8 @@ func drawSemicircleOutline(canvas [][]color.Color, centerX, centerY, radius int,
 	
 	for i := 0; i <= numSegments; i++ {
 			angle := 2.0 * math.Pi * float64(i) / float64(numSegments)
-			x := centerX + int(float64(radius) * math.Cos(angle))
-			y := centerY + int(float64(radius) * math.Sin(angle))
+			x := roundPixel(centerX + radius*math.Cos(angle))
+			y := roundPixel(centerY + radius*math.Sin(angle))
 				
 			vx := math.Cos(angle)
 			vy := math.Sin(angle)
@@ -127,34 +127,34 @@ func drawSemicircleOutline(canvas [][]color.Color, centerX, centerY, radius int,
 func (p *Pill) Contains(point Point) bool {
 	
 	distSq1 := (point.X-p.Start.X)*(point.X-p.Start.X) + (point.Y-p.Start.Y)*(point.Y-p.Start.Y)
-	if math.Abs(float64(distSq1)-float64(p.Radius*p.Radius)) <= float64(5*5) {
+	if math.Abs(distSq1-p.Radius*p.Radius) <= 5*5 {
 		return true
 	}
 	
 	
 	distSq2 := (point.X-p.End.X)*(point.X-p.End.X) + (point.Y-p.End.Y)*(point.Y-p.End.Y)
-	if math.Abs(float64(distSq2)-float64(p.Radius*p.Radius)) <= float64(5*5) {
+	if math.Abs(distSq2-p.Radius*p.Radius) <= 5*5 {
 		return true
 	}
 	
 	
 	dx := p.End.X - p.Start.X
 	dy := p.End.Y - p.Start.Y
-	length := math.Sqrt(float64(dx*dx + dy*dy))
+	length := math.Sqrt(dx*dx + dy*dy)
 	
 	if length > 0 {
-		dirX := float64(dx) / length
-		dirY := float64(dy) / length
+		dirX := dx / length
+		dirY := dy / length
 		
 		perpX := -dirY
 		perpY := dirX
 		
-		vx := float64(point.X - p.Start.X)
-		vy := float64(point.Y - p.Start.Y)
+		vx := point.X - p.Start.X
+		vy := point.Y - p.Start.Y
 		projDir := vx*dirX + vy*dirY
 		if projDir >= 0 && projDir <= length {
 				projPerp := math.Abs(vx*perpX + vy*perpY)
-				return projPerp <= float64(p.Radius+5)
+				return projPerp <= p.Radius+5
 		}
 	}
 	
@@ -171,7 +171,7 @@ func (p *Pill) GetControlPoints() []Point {
 }
 
 
-func (p *Pill) Move(deltaX, deltaY int) {
+func (p *Pill) Move(deltaX, deltaY float64) {
 	p.Start.X += deltaX
 	p.Start.Y += deltaY
 	p.End.X += deltaX
@@ -189,26 +189,55 @@ func (p *Pill) GetColor() color.Color {
 }
 
 
-func (p *Pill) Serialize() map[string]interface{} {
-	r, g, b, a := p.Color.RGBA()
-	return map[string]interface{}{
-		"type":    "pill",
-		"startX":  p.Start.X,
-		"startY":  p.Start.Y,
-		"endX":    p.End.X, 
-		"endY":    p.End.Y,
-		"radius":  p.Radius,
-		"color":   []uint32{r, g, b, a},
+func (p *Pill) Clone() Shape {
+	return &Pill{
+		Start:        Point{X: p.Start.X, Y: p.Start.Y},
+		End:          Point{X: p.End.X, Y: p.End.Y},
+		Radius:       p.Radius,
+		Color:        p.Color,
+		Step:         p.Step,
+		RasterChoice: p.RasterChoice,
 	}
 }
 
 
-func (p *Pill) Clone() Shape {
-	return &Pill{
-		Start:  Point{X: p.Start.X, Y: p.Start.Y},
-		End:    Point{X: p.End.X, Y: p.End.Y},
-		Radius: p.Radius,
-		Color:  p.Color,
-		Step:   p.Step,
+func (p *Pill) ToPath() *Path {
+	path := NewPath(p.Color, 1)
+
+	dx := p.End.X - p.Start.X
+	dy := p.End.Y - p.Start.Y
+	length := math.Sqrt(dx*dx + dy*dy)
+	if length == 0 {
+		path.MoveTo(Point{X: p.Start.X + p.Radius, Y: p.Start.Y})
+		path.ArcTo(p.Start, p.Radius, 0, 2*math.Pi)
+		path.Close()
+		return path
+	}
+
+	perpX := -dy / length
+	perpY := dx / length
+	angle := math.Atan2(perpY, perpX)
+	offsetX := perpX * p.Radius
+	offsetY := perpY * p.Radius
+
+	path.MoveTo(Point{X: p.Start.X + offsetX, Y: p.Start.Y + offsetY})
+	path.LineTo(Point{X: p.End.X + offsetX, Y: p.End.Y + offsetY})
+	path.ArcTo(p.End, p.Radius, angle, -math.Pi)
+	path.LineTo(Point{X: p.Start.X - offsetX, Y: p.Start.Y - offsetY})
+	path.ArcTo(p.Start, p.Radius, angle+math.Pi, -math.Pi)
+	path.Close()
+	return path
+}
+
+
+func (p *Pill) Transform(m Matrix) Shape {
+	if m.IsSimilarity() {
+		p.Start = m.Apply(p.Start)
+		p.End = m.Apply(p.End)
+		p.Radius = p.Radius * m.ScaleFactor()
+		return p
 	}
-}
\ No newline at end of file
+	path := p.ToPath()
+	path.Raster = p.Raster
+	return path.Transform(m)
+}
@@ -21,34 +21,31 @@ func NewPolygon(vertices []Point, color color.Color, thickness int) *Polygon {
 
 
 func (p *Polygon) Draw(canvas [][]color.Color, antiAliasing bool) {
+	p.drawTraced(canvas, antiAliasing, RasterAlgorithms{}, nil)
+}
+
+
+func (p *Polygon) drawTraced(canvas [][]color.Color, antiAliasing bool, defaults RasterAlgorithms, tracer *pixelTracer) {
 	if len(p.Vertices) < 3 {
 		return 
 	}
 
 	
+	raster := p.resolved(defaults)
 	if p.IsFilled {
-		p.drawFill(canvas)
+		p.drawFill(canvas, tracer, raster)
 	}
 	
 	
 	for i := 0; i < len(p.Vertices); i++ {
 		start := p.Vertices[i]
 		end := p.Vertices[(i+1)%len(p.Vertices)]
-
-		if antiAliasing {
-			drawXiaolinWuLine(canvas, start.X, start.Y, end.X, end.Y, p.Color)
-		} else {
-			if p.Thickness == 1 {
-				drawMidpointLine(canvas, start.X, start.Y, end.X, end.Y, p.Color)
-			} else {
-				drawThickLine(canvas, start.X, start.Y, end.X, end.Y, p.Color, p.Thickness)
-			}
-		}
+		drawLine(canvas, tracer, raster, antiAliasing, start.X, start.Y, end.X, end.Y, p.Color, p.Thickness)
 	}
 }
 
 
-func (p *Polygon) drawFill(canvas [][]color.Color) {
+func (p *Polygon) drawFill(canvas [][]color.Color, tracer *pixelTracer, raster RasterAlgorithms) {
 	
 	algVertices := make([]algorithms.Point, len(p.Vertices))
 	for i, v := range p.Vertices {
@@ -56,9 +53,13 @@ func (p *Polygon) drawFill(canvas [][]color.Color) {
 	}
 	
 	if p.UseImage && p.FillImage != nil {
-		algorithms.FillPolygonWithImage(canvas, algVertices, p.FillImage)
-	} else {
-		algorithms.EdgeTableFill(canvas, algVertices, p.FillColor)
+		tracer.traceRings(canvas, "FillPolygonWithImage", [][]algorithms.Point{algVertices}, func() {
+			algorithms.FillPolygonWithImage(canvas, algVertices, p.FillImage)
+		})
+	} else if !drawNamedFill(canvas, tracer, raster, [][]algorithms.Point{algVertices}, p.FillColor) {
+		tracer.traceRings(canvas, "EdgeTableFill", [][]algorithms.Point{algVertices}, func() {
+			algorithms.EdgeTableFill(canvas, algVertices, p.FillColor)
+		})
 	}
 }
 
@@ -79,20 +80,20 @@ func (p *Polygon) Contains(pt Point) bool {
 		end := p.Vertices[(i+1)%len(p.Vertices)]
 
 
-		lineLen := math.Sqrt(float64((end.X-start.X)*(end.X-start.X) + (end.Y-start.Y)*(end.Y-start.Y)))
+		lineLen := math.Sqrt((end.X-start.X)*(end.X-start.X) + (end.Y-start.Y)*(end.Y-start.Y))
 		if lineLen == 0 {
 			continue
 		}
 
-		t := float64((pt.X-start.X)*(end.X-start.X) + (pt.Y-start.Y)*(end.Y-start.Y)) / (lineLen * lineLen)
+		t := ((pt.X-start.X)*(end.X-start.X) + (pt.Y-start.Y)*(end.Y-start.Y)) / (lineLen * lineLen)
 		if t < 0 || t > 1 {
 			continue
 		}
 
-		nearestX := start.X + int(float64(end.X-start.X)*t)
-		nearestY := start.Y + int(float64(end.Y-start.Y)*t)
+		nearestX := start.X + (end.X-start.X)*t
+		nearestY := start.Y + (end.Y-start.Y)*t
 
-		dist := math.Sqrt(float64((pt.X-nearestX)*(pt.X-nearestX) + (pt.Y-nearestY)*(pt.Y-nearestY)))
+		dist := math.Sqrt((pt.X-nearestX)*(pt.X-nearestX) + (pt.Y-nearestY)*(pt.Y-nearestY))
 		if dist <= float64(p.Thickness+5) {
 			return true
 		}
@@ -107,7 +108,7 @@ func (p *Polygon) GetControlPoints() []Point {
 }
 
 
-func (p *Polygon) Move(deltaX, deltaY int) {
+func (p *Polygon) Move(deltaX, deltaY float64) {
 	for i := range p.Vertices {
 		p.Vertices[i].X += deltaX
 		p.Vertices[i].Y += deltaY
@@ -125,49 +126,13 @@ func (p *Polygon) GetColor() color.Color {
 }
 
 
-func (p *Polygon) Serialize() map[string]interface{} {
-	serMap := map[string]interface{}{
-		"type":      "polygon",
-		"thickness": p.Thickness,
-		"isFilled":  p.IsFilled,
-		"useImage":  p.UseImage,
-	}
-	
-	
-	vertices := make([]map[string]interface{}, len(p.Vertices))
-	for i, vertex := range p.Vertices {
-		vertices[i] = map[string]interface{}{
-			"X": vertex.X,
-			"Y": vertex.Y,
-		}
-	}
-	serMap["vertices"] = vertices
-	
-	
-	if p.Color != nil {
-		r, g, b, a := p.Color.RGBA()
-		serMap["color"] = map[string]interface{}{
-			"R": uint8(r),
-			"G": uint8(g),
-			"B": uint8(b),
-			"A": uint8(a),
-		}
-	}
-	
-	if p.IsFilled && !p.UseImage && p.FillColor != nil {
-		r, g, b, a := p.FillColor.RGBA()
-		serMap["fillColor"] = map[string]interface{}{
-			"R": uint8(r),
-			"G": uint8(g),
-			"B": uint8(b),
-			"A": uint8(a),
-		}
-	}
-	
-	
-	
-	
-	return serMap
+func (p *Polygon) GetThickness() int {
+	return p.Thickness
+}
+
+
+func (p *Polygon) SetThickness(thickness int) {
+	p.Thickness = thickness
 }
 
 
@@ -181,6 +146,7 @@ func (p *Polygon) Clone() Shape {
 	clone.FillColor = p.FillColor
 	clone.IsFilled = p.IsFilled
 	clone.UseImage = p.UseImage
+	clone.Raster = p.Raster
 	
 	
 	if p.UseImage && p.FillImage != nil {
@@ -245,4 +211,86 @@ func (p *Polygon) IsConvex() bool {
 
 func (p *Polygon) GetVertices() []Point {
 	return p.Vertices
-}
\ No newline at end of file
+}
+
+
+func (p *Polygon) ToPath() *Path {
+	path := polylinePath(p.Vertices, true, p.Color, p.Thickness)
+	if p.IsFilled && !p.UseImage && p.FillColor != nil {
+		path.SetFillColor(p.FillColor)
+	}
+	return path
+}
+
+
+func (p *Polygon) Transform(m Matrix) Shape {
+	for i := range p.Vertices {
+		p.Vertices[i] = m.Apply(p.Vertices[i])
+	}
+	return p
+}
+
+
+func (p *Polygon) EdgeAt(pt Point) (int, Point, bool) {
+	bestIndex := -1
+	bestDist := float64(p.Thickness + 5)
+	var bestPoint Point
+
+	for i := 0; i < len(p.Vertices); i++ {
+		start := p.Vertices[i]
+		end := p.Vertices[(i+1)%len(p.Vertices)]
+
+		dx := end.X - start.X
+		dy := end.Y - start.Y
+		lenSq := dx*dx + dy*dy
+		if lenSq == 0 {
+			continue
+		}
+
+		t := ((pt.X-start.X)*dx + (pt.Y-start.Y)*dy) / lenSq
+		t = math.Max(0, math.Min(1, t))
+		nearest := Point{X: start.X + dx*t, Y: start.Y + dy*t}
+
+		dist := math.Hypot(pt.X-nearest.X, pt.Y-nearest.Y)
+		if dist <= bestDist {
+			bestDist = dist
+			bestIndex = i
+			bestPoint = nearest
+		}
+	}
+
+	return bestIndex, bestPoint, bestIndex >= 0
+}
+
+
+func (p *Polygon) InsertVertex(edgeIndex int, pt Point) int {
+	if edgeIndex < 0 || edgeIndex >= len(p.Vertices) {
+		return -1
+	}
+	index := edgeIndex + 1
+	p.Vertices = append(p.Vertices, Point{})
+	copy(p.Vertices[index+1:], p.Vertices[index:])
+	p.Vertices[index] = pt
+	return index
+}
+
+
+func (p *Polygon) RemoveVertex(index int) bool {
+	if len(p.Vertices) <= 3 || index < 0 || index >= len(p.Vertices) {
+		return false
+	}
+	p.Vertices = append(p.Vertices[:index], p.Vertices[index+1:]...)
+	return true
+}
+
+
+func (p *Polygon) MoveEdge(edgeIndex int, deltaX, deltaY float64) {
+	if edgeIndex < 0 || edgeIndex >= len(p.Vertices) {
+		return
+	}
+	next := (edgeIndex + 1) % len(p.Vertices)
+	p.Vertices[edgeIndex].X += deltaX
+	p.Vertices[edgeIndex].Y += deltaY
+	p.Vertices[next].X += deltaX
+	p.Vertices[next].Y += deltaY
+}
@@ -0,0 +1,127 @@
+package models
+
+import (
+	"image/color"
+	"math"
+	"paint-drawer-pro/algorithms"
+)
+
+
+type PixelSource struct {
+	Shape     Shape
+	Algorithm string
+}
+
+
+type pixelTracer struct {
+	shape   Shape
+	sources [][]PixelSource
+}
+
+
+type tracedShape interface {
+	drawTraced(canvas [][]color.Color, antiAliasing bool, defaults RasterAlgorithms, tracer *pixelTracer)
+}
+
+
+func DrawShape(shape Shape, canvas [][]color.Color, antiAliasing bool, defaults RasterAlgorithms) {
+	drawShape(shape, canvas, antiAliasing, defaults, nil)
+}
+
+
+func drawShape(shape Shape, canvas [][]color.Color, antiAliasing bool, defaults RasterAlgorithms, tracer *pixelTracer) {
+	if traced, ok := shape.(tracedShape); ok {
+		traced.drawTraced(canvas, antiAliasing, defaults, tracer)
+		return
+	}
+	tracer.trace(canvas, "direct pixel writes", Point{X: 0, Y: 0}, Point{X: math.Inf(1), Y: math.Inf(1)}, func() {
+		shape.Draw(canvas, antiAliasing)
+	})
+}
+
+
+func (t *pixelTracer) trace(canvas [][]color.Color, name string, minP, maxP Point, draw func()) {
+	if t == nil || len(canvas) == 0 {
+		draw()
+		return
+	}
+
+	y0 := clampPixel(math.Floor(minP.Y)-1, len(canvas))
+	y1 := clampPixel(math.Ceil(maxP.Y)+1, len(canvas))
+	x0 := clampPixel(math.Floor(minP.X)-1, len(canvas[0]))
+	x1 := clampPixel(math.Ceil(maxP.X)+1, len(canvas[0]))
+	if x0 > x1 || y0 > y1 {
+		draw()
+		return
+	}
+
+	before := make([]color.Color, 0, (x1-x0+1)*(y1-y0+1))
+	for y := y0; y <= y1; y++ {
+		before = append(before, canvas[y][x0:x1+1]...)
+	}
+	draw()
+
+	i := 0
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			if canvas[y][x] != before[i] {
+				t.sources[y][x] = PixelSource{Shape: t.shape, Algorithm: name}
+			}
+			i++
+		}
+	}
+}
+
+
+func (t *pixelTracer) traceLine(canvas [][]color.Color, name string, x0, y0, x1, y1 float64, thickness int, draw func()) {
+	margin := float64(thickness)/2 + 1
+	t.trace(canvas, name,
+		Point{X: math.Min(x0, x1) - margin, Y: math.Min(y0, y1) - margin},
+		Point{X: math.Max(x0, x1) + margin, Y: math.Max(y0, y1) + margin},
+		draw)
+}
+
+
+func (t *pixelTracer) traceCircle(canvas [][]color.Color, name string, centerX, centerY, radius float64, draw func()) {
+	r := math.Abs(radius) + 1
+	t.trace(canvas, name, Point{X: centerX - r, Y: centerY - r}, Point{X: centerX + r, Y: centerY + r}, draw)
+}
+
+
+func (t *pixelTracer) traceRings(canvas [][]color.Color, name string, rings [][]algorithms.Point, draw func()) {
+	minP := Point{X: math.Inf(1), Y: math.Inf(1)}
+	maxP := Point{X: math.Inf(-1), Y: math.Inf(-1)}
+	for _, ring := range rings {
+		for _, p := range ring {
+			minP.X, minP.Y = math.Min(minP.X, p.X), math.Min(minP.Y, p.Y)
+			maxP.X, maxP.Y = math.Max(maxP.X, p.X), math.Max(maxP.Y, p.Y)
+		}
+	}
+	t.trace(canvas, name, minP, maxP, draw)
+}
+
+
+func clampPixel(v float64, size int) int {
+	if math.IsNaN(v) || v < 0 {
+		return 0
+	}
+	if v > float64(size-1) {
+		return size - 1
+	}
+	return int(v)
+}
+
+
+func TraceProvenance(canvas [][]color.Color, shapes []Shape, antiAliasing bool, defaults RasterAlgorithms) [][]PixelSource {
+	sources := make([][]PixelSource, len(canvas))
+	for y := range canvas {
+		sources[y] = make([]PixelSource, len(canvas[y]))
+	}
+
+	tracer := &pixelTracer{sources: sources}
+	for _, shape := range shapes {
+		tracer.shape = shape
+		drawShape(shape, canvas, antiAliasing, defaults, tracer)
+	}
+	return sources
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"image/color"
+	"paint-drawer-pro/algorithms"
+)
+
+
+type RasterAlgorithms struct {
+	Line   string `json:"line,omitempty"`
+	Circle string `json:"circle,omitempty"`
+	Fill   string `json:"fill,omitempty"`
+}
+
+
+func (r RasterAlgorithms) IsZero() bool {
+	return r == RasterAlgorithms{}
+}
+
+
+func (r RasterAlgorithms) Resolve(fallback RasterAlgorithms) RasterAlgorithms {
+	if r.Line == "" {
+		r.Line = fallback.Line
+	}
+	if r.Circle == "" {
+		r.Circle = fallback.Circle
+	}
+	if r.Fill == "" {
+		r.Fill = fallback.Fill
+	}
+	return r
+}
+
+
+type RasterConfigurable interface {
+	GetRaster() RasterAlgorithms
+	SetRaster(r RasterAlgorithms)
+}
+
+
+type RasterChoice struct {
+	Raster RasterAlgorithms
+}
+
+
+func (rc *RasterChoice) GetRaster() RasterAlgorithms {
+	return rc.Raster
+}
+
+
+func (rc *RasterChoice) SetRaster(r RasterAlgorithms) {
+	rc.Raster = r
+}
+
+
+func (rc *RasterChoice) resolved(defaults RasterAlgorithms) RasterAlgorithms {
+	return rc.Raster.Resolve(defaults)
+}
+
+
+func SetShapeRaster(shape Shape, r RasterAlgorithms) {
+	switch s := shape.(type) {
+	case *Group:
+		for _, child := range s.Children {
+			SetShapeRaster(child, r)
+		}
+	case RasterConfigurable:
+		s.SetRaster(r)
+	}
+}
+
+
+func drawLine(canvas [][]color.Color, tracer *pixelTracer, raster RasterAlgorithms, antiAliasing bool, x0, y0, x1, y1 float64, c color.Color, thickness int) {
+	plotter, ok := algorithms.LookupLinePlotter(raster.Line)
+	if !ok {
+		if thickness > 1 && !antiAliasing {
+			drawThickLine(canvas, tracer, x0, y0, x1, y1, c, thickness)
+		} else if antiAliasing {
+			drawXiaolinWuLine(canvas, tracer, x0, y0, x1, y1, c)
+		} else {
+			drawMidpointLine(canvas, tracer, x0, y0, x1, y1, c)
+		}
+		return
+	}
+	tracer.traceLine(canvas, raster.Line+" line", x0, y0, x1, y1, thickness, func() {
+		algorithms.StrokeLine(canvas, plotter, x0, y0, x1, y1, c, thickness)
+	})
+}
+
+
+func drawCircle(canvas [][]color.Color, tracer *pixelTracer, raster RasterAlgorithms, antiAliasing bool, centerX, centerY, radius float64, c color.Color) {
+	algorithm, ok := algorithms.LookupCircleAlgorithm(raster.Circle)
+	if !ok {
+		if antiAliasing {
+			drawXiaolinWuCircle(canvas, tracer, centerX, centerY, radius, c)
+		} else {
+			drawMidpointCircle(canvas, tracer, centerX, centerY, radius, c)
+		}
+		return
+	}
+	tracer.traceCircle(canvas, raster.Circle+" circle", centerX, centerY, radius, func() {
+		algorithm(canvas, centerX, centerY, radius, c)
+	})
+}
+
+
+func drawNamedFill(canvas [][]color.Color, tracer *pixelTracer, raster RasterAlgorithms, rings [][]algorithms.Point, c color.Color) bool {
+	algorithm, ok := algorithms.LookupFillAlgorithm(raster.Fill)
+	if !ok {
+		return false
+	}
+	tracer.traceRings(canvas, raster.Fill+" fill", rings, func() {
+		algorithm(canvas, rings, c)
+	})
+	return true
+}
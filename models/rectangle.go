@@ -3,6 +3,7 @@ package models
 import (
 	"image/color"
 	"math"
+	"paint-drawer-pro/algorithms"
 )
 
 
@@ -15,6 +16,7 @@ type Rectangle struct {
 	IsFilled    bool
 	FillImage   [][]color.Color
 	UseImage    bool
+	RasterChoice
 }
 
 
@@ -46,9 +48,17 @@ func NewRectangle(topLeft, bottomRight Point, color color.Color, thickness int)
 
 
 func (r *Rectangle) Draw(canvas [][]color.Color, antiAliasing bool) {
+	r.drawTraced(canvas, antiAliasing, RasterAlgorithms{}, nil)
+}
+
+
+func (r *Rectangle) drawTraced(canvas [][]color.Color, antiAliasing bool, defaults RasterAlgorithms, tracer *pixelTracer) {
 	
-	if r.IsFilled {
-		r.drawFill(canvas)
+	raster := r.resolved(defaults)
+	if r.IsFilled && (r.UseImage || !drawNamedFill(canvas, tracer, raster, [][]algorithms.Point{toAlgorithmPoints(r.GetVertices())}, r.FillColor)) {
+		tracer.trace(canvas, "Rectangle scanline fill", r.TopLeft, r.BottomRight, func() {
+			r.drawFill(canvas)
+		})
 	}
 	
 	
@@ -56,32 +66,18 @@ func (r *Rectangle) Draw(canvas [][]color.Color, antiAliasing bool) {
 	bottomLeft := Point{X: r.TopLeft.X, Y: r.BottomRight.Y}
 	
 	
-	if antiAliasing {
-		drawXiaolinWuLine(canvas, r.TopLeft.X, r.TopLeft.Y, topRight.X, topRight.Y, r.Color)
-		drawXiaolinWuLine(canvas, topRight.X, topRight.Y, r.BottomRight.X, r.BottomRight.Y, r.Color)
-		drawXiaolinWuLine(canvas, r.BottomRight.X, r.BottomRight.Y, bottomLeft.X, bottomLeft.Y, r.Color)
-		drawXiaolinWuLine(canvas, bottomLeft.X, bottomLeft.Y, r.TopLeft.X, r.TopLeft.Y, r.Color)
-	} else {
-		if r.Thickness == 1 {
-			drawMidpointLine(canvas, r.TopLeft.X, r.TopLeft.Y, topRight.X, topRight.Y, r.Color)
-			drawMidpointLine(canvas, topRight.X, topRight.Y, r.BottomRight.X, r.BottomRight.Y, r.Color)
-			drawMidpointLine(canvas, r.BottomRight.X, r.BottomRight.Y, bottomLeft.X, bottomLeft.Y, r.Color)
-			drawMidpointLine(canvas, bottomLeft.X, bottomLeft.Y, r.TopLeft.X, r.TopLeft.Y, r.Color)
-		} else {
-			drawThickLine(canvas, r.TopLeft.X, r.TopLeft.Y, topRight.X, topRight.Y, r.Color, r.Thickness)
-			drawThickLine(canvas, topRight.X, topRight.Y, r.BottomRight.X, r.BottomRight.Y, r.Color, r.Thickness)
-			drawThickLine(canvas, r.BottomRight.X, r.BottomRight.Y, bottomLeft.X, bottomLeft.Y, r.Color, r.Thickness)
-			drawThickLine(canvas, bottomLeft.X, bottomLeft.Y, r.TopLeft.X, r.TopLeft.Y, r.Color, r.Thickness)
-		}
-	}
+	drawLine(canvas, tracer, raster, antiAliasing, r.TopLeft.X, r.TopLeft.Y, topRight.X, topRight.Y, r.Color, r.Thickness)
+	drawLine(canvas, tracer, raster, antiAliasing, topRight.X, topRight.Y, r.BottomRight.X, r.BottomRight.Y, r.Color, r.Thickness)
+	drawLine(canvas, tracer, raster, antiAliasing, r.BottomRight.X, r.BottomRight.Y, bottomLeft.X, bottomLeft.Y, r.Color, r.Thickness)
+	drawLine(canvas, tracer, raster, antiAliasing, bottomLeft.X, bottomLeft.Y, r.TopLeft.X, r.TopLeft.Y, r.Color, r.Thickness)
 }
 
 
 func (r *Rectangle) drawFill(canvas [][]color.Color) {
-	startX := r.TopLeft.X + 1
-	endX := r.BottomRight.X - 1
-	startY := r.TopLeft.Y + 1
-	endY := r.BottomRight.Y - 1
+	startX := roundPixel(r.TopLeft.X) + 1
+	endX := roundPixel(r.BottomRight.X) - 1
+	startY := roundPixel(r.TopLeft.Y) + 1
+	endY := roundPixel(r.BottomRight.Y) - 1
 	
 	
 	if startX >= endX || startY >= endY {
@@ -126,25 +122,25 @@ func (r *Rectangle) Contains(p Point) bool {
 	
 	
 	if p.X >= r.TopLeft.X && p.X <= r.BottomRight.X && 
-	   math.Abs(float64(p.Y-r.TopLeft.Y)) <= float64(r.Thickness+5) {
+	   math.Abs(p.Y-r.TopLeft.Y) <= float64(r.Thickness+5) {
 		return true
 	}
 	
 	
 	if p.Y >= r.TopLeft.Y && p.Y <= r.BottomRight.Y && 
-	   math.Abs(float64(p.X-r.BottomRight.X)) <= float64(r.Thickness+5) {
+	   math.Abs(p.X-r.BottomRight.X) <= float64(r.Thickness+5) {
 		return true
 	}
 	
 	
 	if p.X >= r.TopLeft.X && p.X <= r.BottomRight.X && 
-	   math.Abs(float64(p.Y-r.BottomRight.Y)) <= float64(r.Thickness+5) {
+	   math.Abs(p.Y-r.BottomRight.Y) <= float64(r.Thickness+5) {
 		return true
 	}
 	
 	
 	if p.Y >= r.TopLeft.Y && p.Y <= r.BottomRight.Y && 
-	   math.Abs(float64(p.X-r.TopLeft.X)) <= float64(r.Thickness+5) {
+	   math.Abs(p.X-r.TopLeft.X) <= float64(r.Thickness+5) {
 		return true
 	}
 	
@@ -168,7 +164,7 @@ func (r *Rectangle) GetControlPoints() []Point {
 }
 
 
-func (r *Rectangle) Move(deltaX, deltaY int) {
+func (r *Rectangle) Move(deltaX, deltaY float64) {
 	r.TopLeft.X += deltaX
 	r.TopLeft.Y += deltaY
 	r.BottomRight.X += deltaX
@@ -186,6 +182,16 @@ func (r *Rectangle) GetColor() color.Color {
 }
 
 
+func (r *Rectangle) GetThickness() int {
+	return r.Thickness
+}
+
+
+func (r *Rectangle) SetThickness(thickness int) {
+	r.Thickness = thickness
+}
+
+
 func (r *Rectangle) SetFillColor(c color.Color) {
 	r.FillColor = c
 	r.IsFilled = true
@@ -220,53 +226,6 @@ func (r *Rectangle) GetVertices() []Point {
 }
 
 
-func (r *Rectangle) Serialize() map[string]interface{} {
-	serMap := map[string]interface{}{
-		"type":      "rectangle",
-		"thickness": r.Thickness,
-		"isFilled":  r.IsFilled,
-		"useImage":  r.UseImage,
-	}
-	
-	
-	serMap["topLeft"] = map[string]interface{}{
-		"X": r.TopLeft.X,
-		"Y": r.TopLeft.Y,
-	}
-	
-	serMap["bottomRight"] = map[string]interface{}{
-		"X": r.BottomRight.X,
-		"Y": r.BottomRight.Y,
-	}
-	
-	
-	if r.Color != nil {
-		r, g, b, a := r.Color.RGBA()
-		serMap["color"] = map[string]interface{}{
-			"R": uint8(r),
-			"G": uint8(g),
-			"B": uint8(b),
-			"A": uint8(a),
-		}
-	}
-	
-	if r.IsFilled && !r.UseImage && r.FillColor != nil {
-		fr, fg, fb, fa := r.FillColor.RGBA()
-		serMap["fillColor"] = map[string]interface{}{
-			"R": uint8(fr),
-			"G": uint8(fg),
-			"B": uint8(fb),
-			"A": uint8(fa),
-		}
-	}
-	
-	
-	
-	
-	return serMap
-}
-
-
 func (r *Rectangle) Clone() Shape {
 	newRect := &Rectangle{
 		TopLeft:     Point{X: r.TopLeft.X, Y: r.TopLeft.Y},
@@ -277,6 +236,7 @@ func (r *Rectangle) Clone() Shape {
 		IsFilled:    r.IsFilled,
 		UseImage:    r.UseImage,
 	}
+	newRect.Raster = r.Raster
 	
 	if r.UseImage && r.FillImage != nil {
 		height := len(r.FillImage)
@@ -357,3 +317,31 @@ func (r *Rectangle) ResizeByCorner(cornerType ResizePointType, newPoint Point) {
 		r.TopLeft.Y, r.BottomRight.Y = r.BottomRight.Y, r.TopLeft.Y
 	}
 }
+
+
+func (r *Rectangle) ToPath() *Path {
+	path := polylinePath(r.GetVertices(), true, r.Color, r.Thickness)
+	if r.IsFilled && !r.UseImage && r.FillColor != nil {
+		path.SetFillColor(r.FillColor)
+	}
+	return path
+}
+
+
+func (r *Rectangle) Transform(m Matrix) Shape {
+	if m.IsAxisAligned() {
+		topLeft := m.Apply(r.TopLeft)
+		bottomRight := m.Apply(r.BottomRight)
+		r.TopLeft = Point{X: min(topLeft.X, bottomRight.X), Y: min(topLeft.Y, bottomRight.Y)}
+		r.BottomRight = Point{X: max(topLeft.X, bottomRight.X), Y: max(topLeft.Y, bottomRight.Y)}
+		return r
+	}
+
+	poly := NewPolygon(r.GetVertices(), r.Color, r.Thickness)
+	poly.FillColor = r.FillColor
+	poly.IsFilled = r.IsFilled
+	poly.FillImage = r.FillImage
+	poly.UseImage = r.UseImage
+	poly.Raster = r.Raster
+	return poly.Transform(m)
+}
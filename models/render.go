@@ -0,0 +1,58 @@
+package models
+
+import (
+	"image"
+	"image/color"
+	"paint-drawer-pro/algorithms"
+)
+
+
+func RenderLayers(img *image.RGBA, state *DrawingState, view Viewport, antiAliasing bool) {
+	bounds := img.Bounds()
+	for i, layer := range state.Layers {
+		shapes := state.LayerShapes(i)
+		if !layer.Visible || len(shapes) == 0 {
+			continue
+		}
+
+		canvas := NewLayerCanvas(bounds.Dx(), bounds.Dy())
+		for _, shape := range shapes {
+			DrawShape(view.ViewShape(shape), canvas, antiAliasing, state.Raster)
+		}
+		CompositeLayer(img, canvas, layer)
+	}
+}
+
+
+func CompositeLayer(img *image.RGBA, canvas [][]color.Color, layer *Layer) {
+	direct := layer.BlendMode == algorithms.BlendNormal && layer.Opacity >= 1
+	for y := range canvas {
+		for x, c := range canvas[y] {
+			if c == nil {
+				continue
+			}
+			_, _, _, a := c.RGBA()
+			if a == 0 {
+				continue
+			}
+			if direct && a == 0xffff {
+				img.Set(x, y, c)
+				continue
+			}
+			img.SetRGBA(x, y, algorithms.CompositePixel(img.RGBAAt(x, y), c, layer.Opacity, layer.BlendMode))
+		}
+	}
+}
+
+
+func NewLayerCanvas(w, h int) [][]color.Color {
+	canvas := make([][]color.Color, h)
+	transparent := color.RGBA{}
+	for y := range canvas {
+		canvas[y] = make([]color.Color, w)
+		for x := range canvas[y] {
+			canvas[y][x] = transparent
+		}
+	}
+	return canvas
+}
@@ -1,5 +1,9 @@
 package models
 
+import (
+	"math"
+)
+
 
 type ResizePointType int
 
@@ -10,3 +14,179 @@ const (
 	BottomRight
 	BottomLeft
 )
+
+
+type HandleKind int
+
+const (
+	CornerHandle HandleKind = iota
+	EndpointHandle
+	VertexHandle
+	RadiusHandle
+	ControlHandle
+)
+
+
+const HandleSelectionRadius = 10
+
+
+type Handle struct {
+	ID       int
+	Kind     HandleKind
+	Position Point
+}
+
+
+type Editable interface {
+	GetHandles() []Handle
+	MoveHandle(id int, p Point)
+}
+
+
+func HandleAt(e Editable, p Point) (Handle, bool) {
+	handles := e.GetHandles()
+	for i := len(handles) - 1; i >= 0; i-- {
+		dx := handles[i].Position.X - p.X
+		dy := handles[i].Position.Y - p.Y
+		if dx*dx+dy*dy <= HandleSelectionRadius*HandleSelectionRadius {
+			return handles[i], true
+		}
+	}
+	return Handle{}, false
+}
+
+
+func (r *Rectangle) GetHandles() []Handle {
+	corners := r.GetControlPoints()
+	return []Handle{
+		{ID: int(TopLeft), Kind: CornerHandle, Position: corners[0]},
+		{ID: int(TopRight), Kind: CornerHandle, Position: corners[1]},
+		{ID: int(BottomRight), Kind: CornerHandle, Position: corners[2]},
+		{ID: int(BottomLeft), Kind: CornerHandle, Position: corners[3]},
+	}
+}
+
+
+func (r *Rectangle) MoveHandle(id int, p Point) {
+	r.ResizeByCorner(ResizePointType(id), p)
+}
+
+
+func (c *Circle) GetHandles() []Handle {
+	return []Handle{
+		{ID: 0, Kind: RadiusHandle, Position: Point{X: c.Center.X + c.Radius, Y: c.Center.Y}},
+	}
+}
+
+
+func (c *Circle) MoveHandle(id int, p Point) {
+	if id != 0 {
+		return
+	}
+	radius := math.Hypot(p.X-c.Center.X, p.Y-c.Center.Y)
+	if radius < 1 {
+		radius = 1
+	}
+	c.Radius = radius
+}
+
+
+func (l *Line) GetHandles() []Handle {
+	return []Handle{
+		{ID: 0, Kind: EndpointHandle, Position: l.Start},
+		{ID: 1, Kind: EndpointHandle, Position: l.End},
+	}
+}
+
+
+func (l *Line) MoveHandle(id int, p Point) {
+	switch id {
+	case 0:
+		l.Start = p
+	case 1:
+		l.End = p
+	}
+}
+
+
+func (p *Polygon) GetHandles() []Handle {
+	handles := make([]Handle, len(p.Vertices))
+	for i, v := range p.Vertices {
+		handles[i] = Handle{ID: i, Kind: VertexHandle, Position: v}
+	}
+	return handles
+}
+
+
+func (p *Polygon) MoveHandle(id int, pt Point) {
+	if id >= 0 && id < len(p.Vertices) {
+		p.Vertices[id] = pt
+	}
+}
+
+
+func (p *Pill) radiusHandlePosition() Point {
+	dx := p.End.X - p.Start.X
+	dy := p.End.Y - p.Start.Y
+	length := math.Hypot(dx, dy)
+	if length == 0 {
+		return Point{X: p.Start.X, Y: p.Start.Y - p.Radius}
+	}
+	return Point{
+		X: p.Start.X - dy/length*p.Radius,
+		Y: p.Start.Y + dx/length*p.Radius,
+	}
+}
+
+
+func (p *Pill) GetHandles() []Handle {
+	return []Handle{
+		{ID: 0, Kind: EndpointHandle, Position: p.Start},
+		{ID: 1, Kind: EndpointHandle, Position: p.End},
+		{ID: 2, Kind: RadiusHandle, Position: p.radiusHandlePosition()},
+	}
+}
+
+
+func (p *Pill) MoveHandle(id int, pt Point) {
+	switch id {
+	case 0:
+		p.Start = pt
+	case 1:
+		p.End = pt
+	case 2:
+		radius := math.Hypot(pt.X-p.Start.X, pt.Y-p.Start.Y)
+		if radius < 1 {
+			radius = 1
+		}
+		p.Radius = radius
+	}
+}
+
+
+func (p *Path) GetHandles() []Handle {
+	var handles []Handle
+	id := 0
+	for _, cmd := range p.Commands {
+		for i, pt := range cmd.Points {
+			kind := VertexHandle
+			if i < len(cmd.Points)-1 {
+				kind = ControlHandle
+			}
+			handles = append(handles, Handle{ID: id, Kind: kind, Position: pt})
+			id++
+		}
+	}
+	return handles
+}
+
+
+func (p *Path) MoveHandle(id int, pt Point) {
+	for i := range p.Commands {
+		if id < len(p.Commands[i].Points) {
+			p.Commands[i].Points[id] = pt
+			return
+		}
+		id -= len(p.Commands[i].Points)
+	}
+}
@@ -0,0 +1,176 @@
+package models
+
+
+type MarqueeMode int
+
+const (
+	MarqueeInside MarqueeMode = iota
+	MarqueeTouching
+)
+
+
+func (s *DrawingState) Select(shape Shape) {
+	s.SelectedShape = shape
+	s.Selection = nil
+	if shape != nil {
+		s.Selection = []Shape{shape}
+	}
+}
+
+
+func (s *DrawingState) SetSelection(shapes []Shape) {
+	s.Selection = append([]Shape{}, shapes...)
+	s.SelectedShape = nil
+	if len(s.Selection) > 0 {
+		s.SelectedShape = s.Selection[len(s.Selection)-1]
+	}
+}
+
+
+func (s *DrawingState) ClearSelection() {
+	s.SelectedShape = nil
+	s.Selection = nil
+}
+
+
+func (s *DrawingState) SelectAll() {
+	s.SetSelection(s.SelectableShapes())
+}
+
+
+func (s *DrawingState) IsSelected(shape Shape) bool {
+	return IndexOfShape(s.Selection, shape) >= 0
+}
+
+
+func (s *DrawingState) ToggleSelection(shape Shape) {
+	if s.IsSelected(shape) {
+		s.Deselect(shape)
+		return
+	}
+	s.Selection = append(s.Selection, shape)
+	s.SelectedShape = shape
+}
+
+
+func (s *DrawingState) Deselect(shape Shape) {
+	if i := IndexOfShape(s.Selection, shape); i >= 0 {
+		s.Selection = append(s.Selection[:i:i], s.Selection[i+1:]...)
+	}
+	if s.SelectedShape == shape {
+		s.SelectedShape = nil
+		if len(s.Selection) > 0 {
+			s.SelectedShape = s.Selection[len(s.Selection)-1]
+		}
+	}
+}
+
+
+func (s *DrawingState) SelectedShapes() []Shape {
+	if len(s.Selection) == 0 && s.SelectedShape != nil {
+		return []Shape{s.SelectedShape}
+	}
+	return s.Selection
+}
+
+
+func (s *DrawingState) SelectionInStackOrder() []Shape {
+	var ordered []Shape
+	for _, shape := range s.Shapes {
+		if s.IsSelected(shape) || (len(s.Selection) == 0 && shape == s.SelectedShape) {
+			ordered = append(ordered, shape)
+		}
+	}
+	return ordered
+}
+
+
+func (s *DrawingState) replaceInSelection(old, replacement Shape) {
+	if i := IndexOfShape(s.Selection, old); i >= 0 {
+		s.Selection[i] = replacement
+	}
+	if s.SelectedShape == old {
+		s.SelectedShape = replacement
+	}
+}
+
+
+func (s *DrawingState) SelectionBounds() (Point, Point, bool) {
+	selected := s.SelectedShapes()
+	if len(selected) == 0 {
+		return Point{}, Point{}, false
+	}
+
+	minP, maxP := ShapeBounds(selected[0])
+	for _, shape := range selected[1:] {
+		lo, hi := ShapeBounds(shape)
+		minP = Point{X: min(minP.X, lo.X), Y: min(minP.Y, lo.Y)}
+		maxP = Point{X: max(maxP.X, hi.X), Y: max(maxP.Y, hi.Y)}
+	}
+	return minP, maxP, true
+}
+
+
+func ShapesInRect(shapes []Shape, a, b Point, mode MarqueeMode) []Shape {
+	rectMin := Point{X: min(a.X, b.X), Y: min(a.Y, b.Y)}
+	rectMax := Point{X: max(a.X, b.X), Y: max(a.Y, b.Y)}
+
+	var result []Shape
+	for _, shape := range shapes {
+		lo, hi := ShapeBounds(shape)
+		inside := lo.X >= rectMin.X && lo.Y >= rectMin.Y && hi.X <= rectMax.X && hi.Y <= rectMax.Y
+		touching := lo.X <= rectMax.X && hi.X >= rectMin.X && lo.Y <= rectMax.Y && hi.Y >= rectMin.Y
+		if (mode == MarqueeInside && inside) || (mode == MarqueeTouching && touching) {
+			result = append(result, shape)
+		}
+	}
+	return result
+}
+
+
+type LassoMode int
+
+const (
+	LassoGeometry LassoMode = iota
+	LassoBounds
+)
+
+
+func ShapesInLasso(shapes []Shape, loop []Point, mode LassoMode) []Shape {
+	if len(loop) < 3 {
+		return nil
+	}
+
+	var result []Shape
+	for _, shape := range shapes {
+		points := lassoTestPoints(shape, mode)
+		if len(points) == 0 {
+			continue
+		}
+		inside := true
+		for _, p := range points {
+			if !pointInRing(p, loop) {
+				inside = false
+				break
+			}
+		}
+		if inside {
+			result = append(result, shape)
+		}
+	}
+	return result
+}
+
+
+func lassoTestPoints(shape Shape, mode LassoMode) []Point {
+	if mode == LassoBounds {
+		lo, hi := ShapeBounds(shape)
+		return []Point{lo, {X: hi.X, Y: lo.Y}, hi, {X: lo.X, Y: hi.Y}}
+	}
+
+	points := shape.GetControlPoints()
+	for _, sp := range shape.ToPath().Subpaths() {
+		points = append(points, sp.Points...)
+	}
+	return points
+}
@@ -0,0 +1,650 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"image/color"
+	"reflect"
+)
+
+
+type ColorData struct {
+	R uint8
+	G uint8
+	B uint8
+	A uint8
+}
+
+
+func NewColorData(c color.Color) ColorData {
+	if c == nil {
+		return ColorData{}
+	}
+	rgba := color.RGBAModel.Convert(c).(color.RGBA)
+	return ColorData{R: rgba.R, G: rgba.G, B: rgba.B, A: rgba.A}
+}
+
+
+func (c ColorData) Color() color.Color {
+	return color.RGBA{c.R, c.G, c.B, c.A}
+}
+
+
+func fillColorData(c color.Color) *ColorData {
+	if c == nil {
+		return nil
+	}
+	data := NewColorData(c)
+	return &data
+}
+
+
+type ImageData struct {
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Pixels []byte `json:"pixels"`
+}
+
+
+func NewImageData(img [][]color.Color) (*ImageData, error) {
+	if len(img) == 0 || len(img[0]) == 0 {
+		return nil, fmt.Errorf("fill image is empty")
+	}
+	data := &ImageData{Width: len(img[0]), Height: len(img)}
+	data.Pixels = make([]byte, 0, data.Width*data.Height*4)
+	for y, row := range img {
+		if len(row) != data.Width {
+			return nil, fmt.Errorf("fill image row %d has %d pixels, want %d", y, len(row), data.Width)
+		}
+		for _, c := range row {
+			pixel := NewColorData(c)
+			data.Pixels = append(data.Pixels, pixel.R, pixel.G, pixel.B, pixel.A)
+		}
+	}
+	return data, nil
+}
+
+
+func (d *ImageData) Image() [][]color.Color {
+	img := make([][]color.Color, d.Height)
+	for y := range img {
+		img[y] = make([]color.Color, d.Width)
+		for x := range img[y] {
+			i := (y*d.Width + x) * 4
+			img[y][x] = color.RGBA{d.Pixels[i], d.Pixels[i+1], d.Pixels[i+2], d.Pixels[i+3]}
+		}
+	}
+	return img
+}
+
+
+func fillImageData(useImage bool, img [][]color.Color) (*ImageData, error) {
+	if !useImage {
+		return nil, nil
+	}
+	return NewImageData(img)
+}
+
+
+func rasterData(r RasterAlgorithms) *RasterAlgorithms {
+	if r.IsZero() {
+		return nil
+	}
+	return &r
+}
+
+
+func rasterFrom(r *RasterAlgorithms) RasterAlgorithms {
+	if r == nil {
+		return RasterAlgorithms{}
+	}
+	return *r
+}
+
+
+var pathCommandPoints = map[PathCommandType]int{
+	MoveToCommand:  1,
+	LineToCommand:  1,
+	QuadToCommand:  2,
+	CubicToCommand: 3,
+	ArcToCommand:   1,
+	CloseCommand:   0,
+}
+
+
+type ShapeHeader struct {
+	Type string `json:"type"`
+}
+
+
+type CircleRecord struct {
+	Type   string            `json:"type"`
+	Center Point             `json:"center"`
+	Radius float64           `json:"radius"`
+	Color  ColorData         `json:"color"`
+	Raster *RasterAlgorithms `json:"raster,omitempty"`
+}
+
+
+type LineRecord struct {
+	Type      string            `json:"type"`
+	Start     Point             `json:"start"`
+	End       Point             `json:"end"`
+	Color     ColorData         `json:"color"`
+	Thickness int               `json:"thickness"`
+	PenType   string            `json:"penType"`
+	Raster    *RasterAlgorithms `json:"raster,omitempty"`
+}
+
+
+type PillRecord struct {
+	Type   string            `json:"type"`
+	Start  Point             `json:"start"`
+	End    Point             `json:"end"`
+	Radius float64           `json:"radius"`
+	Color  ColorData         `json:"color"`
+	Raster *RasterAlgorithms `json:"raster,omitempty"`
+}
+
+
+type PolygonRecord struct {
+	Type      string            `json:"type"`
+	Vertices  []Point           `json:"vertices"`
+	Color     ColorData         `json:"color"`
+	Thickness int               `json:"thickness"`
+	IsFilled  bool              `json:"isFilled"`
+	UseImage  bool              `json:"useImage"`
+	FillColor *ColorData        `json:"fillColor,omitempty"`
+	FillImage *ImageData        `json:"fillImage,omitempty"`
+	Raster    *RasterAlgorithms `json:"raster,omitempty"`
+}
+
+
+type RectangleRecord struct {
+	Type        string            `json:"type"`
+	TopLeft     Point             `json:"topLeft"`
+	BottomRight Point             `json:"bottomRight"`
+	Color       ColorData         `json:"color"`
+	Thickness   int               `json:"thickness"`
+	IsFilled    bool              `json:"isFilled"`
+	UseImage    bool              `json:"useImage"`
+	FillColor   *ColorData        `json:"fillColor,omitempty"`
+	FillImage   *ImageData        `json:"fillImage,omitempty"`
+	Raster      *RasterAlgorithms `json:"raster,omitempty"`
+}
+
+
+type PathCommandRecord struct {
+	Op         string  `json:"op"`
+	Points     []Point `json:"points"`
+	Radius     float64 `json:"radius,omitempty"`
+	StartAngle float64 `json:"startAngle,omitempty"`
+	Sweep      float64 `json:"sweep,omitempty"`
+}
+
+
+type PathRecord struct {
+	Type      string              `json:"type"`
+	Commands  []PathCommandRecord `json:"commands"`
+	Color     ColorData           `json:"color"`
+	Thickness int                 `json:"thickness"`
+	IsFilled  bool                `json:"isFilled"`
+	FillColor *ColorData          `json:"fillColor,omitempty"`
+	Raster    *RasterAlgorithms   `json:"raster,omitempty"`
+}
+
+
+type GroupRecord struct {
+	Type     string            `json:"type"`
+	Children []json.RawMessage `json:"children"`
+}
+
+
+type ShapeCodec interface {
+	Encode(shape Shape) (interface{}, error)
+	Decode(data []byte, check *ShapeCheck) (Shape, error)
+}
+
+
+var (
+	shapeCodecs     = map[string]ShapeCodec{}
+	shapeTypeNames  = map[reflect.Type]string{}
+	shapeCodecNames []string
+)
+
+
+func RegisterShapeCodec(name string, sample Shape, codec ShapeCodec) {
+	if _, exists := shapeCodecs[name]; !exists {
+		shapeCodecNames = append(shapeCodecNames, name)
+	}
+	shapeCodecs[name] = codec
+	shapeTypeNames[reflect.TypeOf(sample)] = name
+}
+
+
+func ShapeTypes() []string {
+	return append([]string(nil), shapeCodecNames...)
+}
+
+
+func ShapeTypeName(shape Shape) (string, bool) {
+	name, ok := shapeTypeNames[reflect.TypeOf(shape)]
+	return name, ok
+}
+
+
+func EncodeShape(shape Shape) (json.RawMessage, error) {
+	name, ok := ShapeTypeName(shape)
+	if !ok {
+		return nil, fmt.Errorf("no codec registered for %T", shape)
+	}
+	record, err := shapeCodecs[name].Encode(shape)
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s: %v", name, err)
+	}
+	return json.Marshal(record)
+}
+
+
+func EncodeShapes(shapes []Shape) ([]json.RawMessage, error) {
+	records := make([]json.RawMessage, 0, len(shapes))
+	for i, shape := range shapes {
+		record, err := EncodeShape(shape)
+		if err != nil {
+			return nil, fmt.Errorf("shape %d: %v", i, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+
+func DecodeShape(data []byte) (Shape, error) {
+	shape, check := RepairShape(data)
+	if len(check.Issues) > 0 {
+		issue := check.Issues[0]
+		if issue.Field != "" {
+			return nil, fmt.Errorf("decoding %s: %s: %s", check.Type, issue.Field, issue.Problem)
+		}
+		return nil, fmt.Errorf("decoding %s: %s", check.Type, issue.Problem)
+	}
+	return shape, nil
+}
+
+
+func RepairShape(data []byte) (Shape, *ShapeCheck) {
+	check := &ShapeCheck{}
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil || raw == nil {
+		check.reject("", "not a JSON object")
+		return nil, check
+	}
+	var header ShapeHeader
+	if err := json.Unmarshal(data, &header); err != nil {
+		check.reject("type", "expected a string")
+		return nil, check
+	}
+	check.Type = header.Type
+	codec, ok := shapeCodecs[header.Type]
+	if !ok {
+		check.reject("type", "unknown shape type %q", header.Type)
+		return nil, check
+	}
+	shape, err := codec.Decode(data, check)
+	if err != nil {
+		if !check.Rejected() {
+			check.reject("", "%v", err)
+		}
+		return nil, check
+	}
+	return shape, check
+}
+
+
+func RepairShapes(records []json.RawMessage, layer int, report *LoadReport) []Shape {
+	shapes := make([]Shape, 0, len(records))
+	for i, record := range records {
+		shape, check := RepairShape(record)
+		report.add(layer, i, check)
+		if shape != nil {
+			shapes = append(shapes, shape)
+		}
+	}
+	return shapes
+}
+
+
+func CheckRoundTrip(shape Shape) error {
+	encoded, err := EncodeShape(shape)
+	if err != nil {
+		return err
+	}
+	decoded, err := DecodeShape(encoded)
+	if err != nil {
+		return err
+	}
+	if !reflect.DeepEqual(decoded, shape.Clone()) {
+		name, _ := ShapeTypeName(shape)
+		return fmt.Errorf("%s does not survive a save and load: %s changes", name, differingField(decoded, shape.Clone()))
+	}
+	return nil
+}
+
+
+func differingField(a, b Shape) string {
+	va := reflect.Indirect(reflect.ValueOf(a))
+	vb := reflect.Indirect(reflect.ValueOf(b))
+	if va.Kind() != reflect.Struct || va.Type() != vb.Type() {
+		return "type"
+	}
+	for i := 0; i < va.NumField(); i++ {
+		if !reflect.DeepEqual(va.Field(i).Interface(), vb.Field(i).Interface()) {
+			return va.Type().Field(i).Name
+		}
+	}
+	return "shape"
+}
+
+
+type circleCodec struct{}
+
+
+func (circleCodec) Encode(shape Shape) (interface{}, error) {
+	c := shape.(*Circle)
+	return CircleRecord{
+		Type:   "circle",
+		Center: c.Center,
+		Radius: c.Radius,
+		Color:  NewColorData(c.Color),
+		Raster: rasterData(c.Raster),
+	}, nil
+}
+
+
+func (circleCodec) Decode(data []byte, check *ShapeCheck) (Shape, error) {
+	var record CircleRecord
+	raw, err := decodeRecord(data, &record, check, "center", "radius")
+	if err != nil {
+		return nil, err
+	}
+	checkRadius(&record.Radius, check)
+	checkColor(raw, "color", &record.Color, check)
+	checkRaster(record.Raster, check)
+	circle := NewCircle(record.Center, record.Radius, record.Color.Color())
+	circle.Raster = rasterFrom(record.Raster)
+	return circle, nil
+}
+
+
+type lineCodec struct{}
+
+
+func (lineCodec) Encode(shape Shape) (interface{}, error) {
+	l := shape.(*Line)
+	return LineRecord{
+		Type:      "line",
+		Start:     l.Start,
+		End:       l.End,
+		Color:     NewColorData(l.Color),
+		Thickness: l.Thickness,
+		PenType:   l.PenType,
+		Raster:    rasterData(l.Raster),
+	}, nil
+}
+
+
+func (lineCodec) Decode(data []byte, check *ShapeCheck) (Shape, error) {
+	var record LineRecord
+	raw, err := decodeRecord(data, &record, check, "start", "end")
+	if err != nil {
+		return nil, err
+	}
+	checkColor(raw, "color", &record.Color, check)
+	checkThickness(raw, &record.Thickness, check)
+	if record.PenType != "regular" && record.PenType != "brush" {
+		check.repair("penType", "unknown pen type %q; using brush", record.PenType)
+		record.PenType = "brush"
+	}
+	checkRaster(record.Raster, check)
+	line := NewLine(record.Start, record.End, record.Color.Color(), record.Thickness, record.PenType)
+	line.Raster = rasterFrom(record.Raster)
+	return line, nil
+}
+
+
+type pillCodec struct{}
+
+
+func (pillCodec) Encode(shape Shape) (interface{}, error) {
+	p := shape.(*Pill)
+	return PillRecord{
+		Type:   "pill",
+		Start:  p.Start,
+		End:    p.End,
+		Radius: p.Radius,
+		Color:  NewColorData(p.Color),
+		Raster: rasterData(p.Raster),
+	}, nil
+}
+
+
+func (pillCodec) Decode(data []byte, check *ShapeCheck) (Shape, error) {
+	var record PillRecord
+	raw, err := decodeRecord(data, &record, check, "start", "end", "radius")
+	if err != nil {
+		return nil, err
+	}
+	checkRadius(&record.Radius, check)
+	checkColor(raw, "color", &record.Color, check)
+	checkRaster(record.Raster, check)
+	pill := NewPill(record.Start, record.Radius, record.Color.Color())
+	pill.End = record.End
+	pill.Step = 3
+	pill.Raster = rasterFrom(record.Raster)
+	return pill, nil
+}
+
+
+type polygonCodec struct{}
+
+
+func (polygonCodec) Encode(shape Shape) (interface{}, error) {
+	p := shape.(*Polygon)
+	fillImage, err := fillImageData(p.UseImage, p.FillImage)
+	if err != nil {
+		return nil, err
+	}
+	return PolygonRecord{
+		Type:      "polygon",
+		Vertices:  append([]Point{}, p.Vertices...),
+		Color:     NewColorData(p.Color),
+		Thickness: p.Thickness,
+		IsFilled:  p.IsFilled,
+		UseImage:  p.UseImage,
+		FillColor: fillColorData(p.FillColor),
+		FillImage: fillImage,
+		Raster:    rasterData(p.Raster),
+	}, nil
+}
+
+
+func (polygonCodec) Decode(data []byte, check *ShapeCheck) (Shape, error) {
+	var record PolygonRecord
+	raw, err := decodeRecord(data, &record, check, "vertices")
+	if err != nil {
+		return nil, err
+	}
+	if len(record.Vertices) < 3 {
+		return nil, check.reject("vertices", "%d vertices, need at least 3", len(record.Vertices))
+	}
+	checkColor(raw, "color", &record.Color, check)
+	checkThickness(raw, &record.Thickness, check)
+	checkRaster(record.Raster, check)
+	polygon := NewPolygon(record.Vertices, record.Color.Color(), record.Thickness)
+	polygon.UseImage, polygon.FillImage = checkFillImage(record.UseImage, record.FillImage, check)
+	polygon.IsFilled = checkFillColor(record.IsFilled, polygon.UseImage, record.FillColor, check)
+	if record.FillColor != nil {
+		polygon.FillColor = record.FillColor.Color()
+	}
+	polygon.Raster = rasterFrom(record.Raster)
+	return polygon, nil
+}
+
+
+type rectangleCodec struct{}
+
+
+func (rectangleCodec) Encode(shape Shape) (interface{}, error) {
+	r := shape.(*Rectangle)
+	fillImage, err := fillImageData(r.UseImage, r.FillImage)
+	if err != nil {
+		return nil, err
+	}
+	return RectangleRecord{
+		Type:        "rectangle",
+		TopLeft:     r.TopLeft,
+		BottomRight: r.BottomRight,
+		Color:       NewColorData(r.Color),
+		Thickness:   r.Thickness,
+		IsFilled:    r.IsFilled,
+		UseImage:    r.UseImage,
+		FillColor:   fillColorData(r.FillColor),
+		FillImage:   fillImage,
+		Raster:      rasterData(r.Raster),
+	}, nil
+}
+
+
+func (rectangleCodec) Decode(data []byte, check *ShapeCheck) (Shape, error) {
+	var record RectangleRecord
+	raw, err := decodeRecord(data, &record, check, "topLeft", "bottomRight")
+	if err != nil {
+		return nil, err
+	}
+	checkColor(raw, "color", &record.Color, check)
+	checkThickness(raw, &record.Thickness, check)
+	checkRaster(record.Raster, check)
+	rectangle := NewRectangle(record.TopLeft, record.BottomRight, record.Color.Color(), record.Thickness)
+	rectangle.UseImage, rectangle.FillImage = checkFillImage(record.UseImage, record.FillImage, check)
+	rectangle.IsFilled = checkFillColor(record.IsFilled, rectangle.UseImage, record.FillColor, check)
+	if record.FillColor != nil {
+		rectangle.FillColor = record.FillColor.Color()
+	}
+	rectangle.Raster = rasterFrom(record.Raster)
+	return rectangle, nil
+}
+
+
+type pathCodec struct{}
+
+
+func (pathCodec) Encode(shape Shape) (interface{}, error) {
+	p := shape.(*Path)
+	commands := make([]PathCommandRecord, len(p.Commands))
+	for i, cmd := range p.Commands {
+		commands[i] = PathCommandRecord{
+			Op:     string(cmd.Type),
+			Points: append([]Point{}, cmd.Points...),
+		}
+		if cmd.Type == ArcToCommand {
+			commands[i].Radius = cmd.Radius
+			commands[i].StartAngle = cmd.StartAngle
+			commands[i].Sweep = cmd.Sweep
+		}
+	}
+	return PathRecord{
+		Type:      "path",
+		Commands:  commands,
+		Color:     NewColorData(p.Color),
+		Thickness: p.Thickness,
+		IsFilled:  p.IsFilled,
+		FillColor: fillColorData(p.FillColor),
+		Raster:    rasterData(p.Raster),
+	}, nil
+}
+
+
+func (pathCodec) Decode(data []byte, check *ShapeCheck) (Shape, error) {
+	var record PathRecord
+	raw, err := decodeRecord(data, &record, check, "commands")
+	if err != nil {
+		return nil, err
+	}
+	checkColor(raw, "color", &record.Color, check)
+	checkThickness(raw, &record.Thickness, check)
+	checkRaster(record.Raster, check)
+
+	path := NewPath(record.Color.Color(), record.Thickness)
+	for i, cmd := range record.Commands {
+		command := PathCommand{Type: PathCommandType(cmd.Op)}
+		if len(cmd.Points) > 0 {
+			command.Points = cmd.Points
+		}
+		if want, ok := pathCommandPoints[command.Type]; !ok {
+			check.repair(fmt.Sprintf("commands[%d]", i), "unknown path command %q; command dropped", cmd.Op)
+			continue
+		} else if len(cmd.Points) != want {
+			check.repair(fmt.Sprintf("commands[%d]", i), "%s command has %d points, need %d; command dropped", cmd.Op, len(cmd.Points), want)
+			continue
+		}
+		if command.Type == ArcToCommand {
+			command.Radius = cmd.Radius
+			command.StartAngle = cmd.StartAngle
+			command.Sweep = cmd.Sweep
+		}
+		path.Commands = append(path.Commands, command)
+	}
+	if len(path.Commands) == 0 {
+		return nil, check.reject("commands", "no drawable commands")
+	}
+	path.IsFilled = checkFillColor(record.IsFilled, false, record.FillColor, check)
+	if record.FillColor != nil {
+		path.FillColor = record.FillColor.Color()
+	}
+	path.Raster = rasterFrom(record.Raster)
+	return path, nil
+}
+
+
+type groupCodec struct{}
+
+
+func (groupCodec) Encode(shape Shape) (interface{}, error) {
+	g := shape.(*Group)
+	children, err := EncodeShapes(g.Children)
+	if err != nil {
+		return nil, err
+	}
+	return GroupRecord{Type: "group", Children: children}, nil
+}
+
+
+func (groupCodec) Decode(data []byte, check *ShapeCheck) (Shape, error) {
+	var record GroupRecord
+	if _, err := decodeRecord(data, &record, check, "children"); err != nil {
+		return nil, err
+	}
+	children := make([]Shape, 0, len(record.Children))
+	for i, childData := range record.Children {
+		child, childCheck := RepairShape(childData)
+		check.nest(fmt.Sprintf("children[%d]", i), childCheck)
+		if child != nil {
+			children = append(children, child)
+		}
+	}
+	if len(children) == 0 {
+		return nil, check.reject("children", "group has no valid children")
+	}
+	return NewGroup(children), nil
+}
+
+
+func init() {
+	RegisterShapeCodec("circle", &Circle{}, circleCodec{})
+	RegisterShapeCodec("line", &Line{}, lineCodec{})
+	RegisterShapeCodec("polygon", &Polygon{}, polygonCodec{})
+	RegisterShapeCodec("rectangle", &Rectangle{}, rectangleCodec{})
+	RegisterShapeCodec("pill", &Pill{}, pillCodec{})
+	RegisterShapeCodec("path", &Path{}, pathCodec{})
+	RegisterShapeCodec("group", &Group{}, groupCodec{})
+}
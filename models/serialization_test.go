@@ -0,0 +1,250 @@
+package models
+
+import (
+	"image/color"
+	"paint-drawer-pro/algorithms"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+
+var (
+	testRed   = color.RGBA{255, 0, 0, 255}
+	testBlue  = color.RGBA{0, 0, 255, 128}
+	testGreen = color.RGBA{0, 200, 0, 255}
+)
+
+
+func testFillImage() [][]color.Color {
+	return [][]color.Color{
+		{testRed, testBlue, testGreen},
+		{testGreen, testRed, color.RGBA{0, 0, 0, 0}},
+	}
+}
+
+
+func roundTripShapes() map[string]Shape {
+	line := NewLine(Point{X: 1.5, Y: 2}, Point{X: 40, Y: -3.25}, testRed, 4, "brush")
+	line.Raster = RasterAlgorithms{Line: algorithms.LineBresenham}
+
+	circle := NewCircle(Point{X: 50, Y: 60}, 12.5, testBlue)
+
+	pill := NewPill(Point{X: 10, Y: 10}, 6, testGreen)
+	pill.End = Point{X: 80, Y: 30}
+	pill.Step = 3
+
+	polygon := NewPolygon([]Point{{X: 0, Y: 0}, {X: 30, Y: 0}, {X: 15, Y: 25}}, testRed, 2)
+	polygon.SetFillColor(testGreen)
+
+	imagePolygon := NewPolygon([]Point{{X: 0, Y: 0}, {X: 30, Y: 0}, {X: 15, Y: 25}}, testRed, 1)
+	imagePolygon.SetFillImage(testFillImage())
+
+	unfilledPolygon := NewPolygon([]Point{{X: 0, Y: 0}, {X: 30, Y: 0}, {X: 15, Y: 25}}, testRed, 1)
+	unfilledPolygon.SetFillColor(testBlue)
+	unfilledPolygon.DisableFill()
+
+	rectangle := NewRectangle(Point{X: 5, Y: 5}, Point{X: 45, Y: 25}, testBlue, 3)
+	rectangle.SetFillColor(testRed)
+
+	imageRectangle := NewRectangle(Point{X: 5, Y: 5}, Point{X: 45, Y: 25}, testBlue, 3)
+	imageRectangle.SetFillImage(testFillImage())
+
+	path := NewPath(testRed, 2).MoveTo(Point{X: 0, Y: 0}).LineTo(Point{X: 10, Y: 0}).
+		QuadTo(Point{X: 15, Y: 5}, Point{X: 10, Y: 10}).
+		CubicTo(Point{X: 5, Y: 15}, Point{X: 0, Y: 15}, Point{X: -5, Y: 10}).
+		ArcTo(Point{X: 0, Y: 5}, 5, 0, 1.5).Close()
+	path.SetFillColor(testGreen)
+
+	group := NewGroup([]Shape{line.Clone(), circle.Clone(), NewGroup([]Shape{polygon.Clone(), imageRectangle.Clone()})})
+
+	return map[string]Shape{
+		"line":                 line,
+		"circle":               circle,
+		"pill":                 pill,
+		"polygon":              polygon,
+		"polygon image fill":   imagePolygon,
+		"polygon fill off":     unfilledPolygon,
+		"rectangle":            rectangle,
+		"rectangle image fill": imageRectangle,
+		"path":                 path,
+		"group":                group,
+	}
+}
+
+
+func TestShapeCodecsRoundTrip(t *testing.T) {
+	for name, shape := range roundTripShapes() {
+		t.Run(name, func(t *testing.T) {
+			encoded, err := EncodeShape(shape)
+			if err != nil {
+				t.Fatalf("EncodeShape: %v", err)
+			}
+			decoded, err := DecodeShape(encoded)
+			if err != nil {
+				t.Fatalf("DecodeShape: %v", err)
+			}
+			if !reflect.DeepEqual(decoded, shape.Clone()) {
+				t.Fatalf("decoded shape differs in %s\n got: %#v\nwant: %#v", differingField(decoded, shape), decoded, shape)
+			}
+			if err := CheckRoundTrip(shape); err != nil {
+				t.Fatalf("CheckRoundTrip: %v", err)
+			}
+		})
+	}
+}
+
+
+func TestEveryCodecHasARoundTripCase(t *testing.T) {
+	covered := map[string]bool{}
+	for _, shape := range roundTripShapes() {
+		name, ok := ShapeTypeName(shape)
+		if !ok {
+			t.Fatalf("no codec for %T", shape)
+		}
+		covered[name] = true
+	}
+	for _, name := range ShapeTypes() {
+		if !covered[name] {
+			t.Errorf("codec %q has no round-trip case", name)
+		}
+	}
+}
+
+
+func TestCheckRoundTripReportsLostFields(t *testing.T) {
+	tests := []struct {
+		name  string
+		shape Shape
+		field string
+	}{
+		{
+			name:  "unknown pen type",
+			shape: NewLine(Point{}, Point{X: 10, Y: 10}, testRed, 2, "marker"),
+			field: "penType",
+		},
+		{
+			name: "ragged fill image",
+			shape: &Polygon{
+				Vertices:  []Point{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 5, Y: 5}},
+				Color:     testRed,
+				Thickness: 1,
+				IsFilled:  true,
+				UseImage:  true,
+				FillImage: [][]color.Color{{testRed, testBlue}, {testRed}},
+			},
+			field: "fill image row 1",
+		},
+		{
+			name: "non-RGBA fill color",
+			shape: &Rectangle{
+				TopLeft:     Point{X: 0, Y: 0},
+				BottomRight: Point{X: 10, Y: 10},
+				Color:       testRed,
+				Thickness:   1,
+				IsFilled:    true,
+				FillColor:   color.NRGBA{255, 0, 0, 128},
+			},
+			field: "FillColor",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckRoundTrip(tt.shape)
+			if err == nil {
+				t.Fatalf("CheckRoundTrip accepted a shape that does not survive")
+			}
+			if !strings.Contains(err.Error(), tt.field) {
+				t.Fatalf("error %q does not mention %s", err, tt.field)
+			}
+		})
+	}
+}
+
+
+func TestMarshalDocumentRefusesLossyShapes(t *testing.T) {
+	state := &DrawingState{Layers: []*Layer{NewLayer("Layer 1")}}
+	state.Shapes = []Shape{NewLine(Point{}, Point{X: 10, Y: 10}, testRed, 2, "marker")}
+	state.Layers[0].Count = 1
+
+	if _, err := MarshalDocument(state); err == nil || !strings.Contains(err.Error(), "layer 1 shape 1") {
+		t.Fatalf("MarshalDocument error = %v, want a layer 1 shape 1 error", err)
+	}
+}
+
+
+func TestFillImageDecodeRepairs(t *testing.T) {
+	tests := []struct {
+		name   string
+		record string
+	}{
+		{
+			name:   "missing image",
+			record: `{"type":"polygon","vertices":[{"X":0,"Y":0},{"X":10,"Y":0},{"X":5,"Y":5}],"color":{"R":0,"G":0,"B":0,"A":255},"thickness":1,"isFilled":true,"useImage":true,"fillColor":{"R":1,"G":2,"B":3,"A":255}}`,
+		},
+		{
+			name:   "short pixels",
+			record: `{"type":"rectangle","topLeft":{"X":0,"Y":0},"bottomRight":{"X":10,"Y":10},"color":{"R":0,"G":0,"B":0,"A":255},"thickness":1,"isFilled":true,"useImage":true,"fillColor":{"R":1,"G":2,"B":3,"A":255},"fillImage":{"width":2,"height":2,"pixels":"AAAA"}}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shape, check := RepairShape([]byte(tt.record))
+			if shape == nil {
+				t.Fatalf("shape was rejected: %v", check.Issues)
+			}
+			if len(check.Issues) != 1 || check.Issues[0].Field != "fillImage" || !check.Issues[0].Repaired {
+				t.Fatalf("issues = %v, want one fillImage repair", check.Issues)
+			}
+			var useImage bool
+			switch s := shape.(type) {
+			case *Polygon:
+				useImage = s.UseImage
+			case *Rectangle:
+				useImage = s.UseImage
+			}
+			if useImage {
+				t.Fatalf("repaired shape still uses the image fill")
+			}
+		})
+	}
+}
+
+
+func TestDocumentRasterRoundTrip(t *testing.T) {
+	state := &DrawingState{Layers: []*Layer{NewLayer("Layer 1")}}
+	state.Raster = RasterAlgorithms{Line: algorithms.LineDDA, Fill: algorithms.FillSeed}
+
+	data, err := MarshalDocument(state)
+	if err != nil {
+		t.Fatalf("MarshalDocument: %v", err)
+	}
+	doc, report, err := ParseDocument(data)
+	if err != nil {
+		t.Fatalf("ParseDocument: %v", err)
+	}
+	if !report.Empty() {
+		t.Fatalf("clean document reported %s", report.Summary())
+	}
+	if doc.Raster != state.Raster || doc.State().Raster != state.Raster {
+		t.Fatalf("raster = %+v, want %+v", doc.Raster, state.Raster)
+	}
+}
+
+
+func TestDocumentRasterRepairs(t *testing.T) {
+	data := `{"version":2,"raster":{"line":"Teleport","circle":"Midpoint"},"layers":[{"name":"Layer 1","shapes":[]}]}`
+	doc, report, err := ParseDocument([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseDocument: %v", err)
+	}
+	want := RasterAlgorithms{Circle: algorithms.CircleMidpoint}
+	if doc.Raster != want {
+		t.Fatalf("raster = %+v, want %+v", doc.Raster, want)
+	}
+	if len(report.Diagnostics) != 1 || report.Diagnostics[0].Field != "raster.line" || report.Diagnostics[0].Location() != "document" {
+		t.Fatalf("diagnostics = %v, want one document raster.line repair", report.Diagnostics)
+	}
+}
@@ -6,26 +6,28 @@ import (
 
 
 type Point struct {
-	X, Y int
+	X, Y float64
 }
 
 
 type Shape interface {
 	Draw(canvas [][]color.Color, antiAliasing bool)
 	Contains(p Point) bool
-	Move(deltaX, deltaY int)
+	Move(deltaX, deltaY float64)
 	GetControlPoints() []Point
 	SetColor(c color.Color)
 	GetColor() color.Color
-	Serialize() map[string]interface{}
 	Clone() Shape
+	ToPath() *Path
+	Transform(m Matrix) Shape
 }
 
 
 type Circle struct {
 	Center Point
-	Radius int
+	Radius float64
 	Color  color.Color
+	RasterChoice
 }
 
 
@@ -37,12 +39,17 @@ type Polygon struct {
 	IsFilled  bool
 	FillImage [][]color.Color
 	UseImage  bool
+	RasterChoice
 }
 
 
 type DrawingState struct {
 	Shapes         []Shape
 	SelectedShape  Shape
+	Selection      []Shape
+	Layers         []*Layer
+	ActiveLayer    int
+	SelectedVertex int
 	CurrentShape   Shape  
 	CurrentAction  string
 	AntiAliasing   bool
@@ -53,4 +60,5 @@ type DrawingState struct {
 	FillColor      color.Color
 	FillImage      [][]color.Color
 	UseImageFill   bool 
+	Raster         RasterAlgorithms
 }
\ No newline at end of file
@@ -0,0 +1,196 @@
+package models
+
+import (
+	"math"
+)
+
+
+type SnapKind int
+
+const (
+	SnapNone SnapKind = iota
+	SnapGrid
+	SnapVertex
+	SnapMidpoint
+	SnapCenter
+	SnapIntersection
+)
+
+
+type SnapSettings struct {
+	ShowGrid      bool
+	SnapToGrid    bool
+	SnapToObjects bool
+	GridSize      float64
+	Tolerance     float64
+	AngleStep     float64
+}
+
+
+type SnapResult struct {
+	Point   Point
+	Kind    SnapKind
+	Snapped bool
+}
+
+
+var snapPriority = map[SnapKind]int{
+	SnapVertex:       0,
+	SnapIntersection: 1,
+	SnapMidpoint:     2,
+	SnapCenter:       3,
+}
+
+
+func DefaultSnapSettings() SnapSettings {
+	return SnapSettings{
+		GridSize:  20,
+		Tolerance: 8,
+		AngleStep: 15,
+	}
+}
+
+
+func Snap(p Point, shapes []Shape, exclude []Shape, settings SnapSettings) SnapResult {
+	result := SnapResult{Point: p}
+
+	if settings.SnapToObjects {
+		best := settings.Tolerance
+		consider := func(candidate Point, kind SnapKind) {
+			d := math.Hypot(candidate.X-p.X, candidate.Y-p.Y)
+			if d > settings.Tolerance {
+				return
+			}
+			if result.Snapped {
+				rank, bestRank := snapPriority[kind], snapPriority[result.Kind]
+				if rank > bestRank || (rank == bestRank && d >= best) {
+					return
+				}
+			}
+			best = d
+			result = SnapResult{Point: candidate, Kind: kind, Snapped: true}
+		}
+
+		var nearby [][2]Point
+		for _, shape := range shapes {
+			if IndexOfShape(exclude, shape) >= 0 {
+				continue
+			}
+			vertices, segments := snapFeatures(shape)
+			for _, v := range vertices {
+				consider(v, SnapVertex)
+			}
+			for _, seg := range segments {
+				consider(Point{X: (seg[0].X + seg[1].X) / 2, Y: (seg[0].Y + seg[1].Y) / 2}, SnapMidpoint)
+				if distanceToSegment(p, seg[0], seg[1]) <= settings.Tolerance {
+					nearby = append(nearby, seg)
+				}
+			}
+			consider(ShapeCenter(shape), SnapCenter)
+		}
+
+		for i := 0; i < len(nearby); i++ {
+			for j := i + 1; j < len(nearby); j++ {
+				if x, ok := segmentIntersection(nearby[i][0], nearby[i][1], nearby[j][0], nearby[j][1]); ok {
+					consider(x, SnapIntersection)
+				}
+			}
+		}
+
+		if result.Snapped {
+			return result
+		}
+	}
+
+	if settings.SnapToGrid && settings.GridSize > 0 {
+		return SnapResult{Point: SnapToGrid(p, settings.GridSize), Kind: SnapGrid, Snapped: true}
+	}
+	return result
+}
+
+
+func SnapToGrid(p Point, size float64) Point {
+	return Point{
+		X: math.Round(p.X/size) * size,
+		Y: math.Round(p.Y/size) * size,
+	}
+}
+
+
+func ConstrainAngle(origin, p Point, stepDegrees float64) Point {
+	dx, dy := p.X-origin.X, p.Y-origin.Y
+	length := math.Hypot(dx, dy)
+	if length == 0 || stepDegrees <= 0 {
+		return p
+	}
+
+	step := stepDegrees * math.Pi / 180
+	angle := math.Round(math.Atan2(dy, dx)/step) * step
+	return Point{X: origin.X + length*math.Cos(angle), Y: origin.Y + length*math.Sin(angle)}
+}
+
+
+func ConstrainSquare(origin, p Point) Point {
+	dx, dy := p.X-origin.X, p.Y-origin.Y
+	side := math.Max(math.Abs(dx), math.Abs(dy))
+	return Point{X: origin.X + math.Copysign(side, dx), Y: origin.Y + math.Copysign(side, dy)}
+}
+
+
+func snapFeatures(shape Shape) ([]Point, [][2]Point) {
+	if group, ok := shape.(*Group); ok {
+		var vertices []Point
+		var segments [][2]Point
+		for _, child := range group.Children {
+			v, s := snapFeatures(child)
+			vertices = append(vertices, v...)
+			segments = append(segments, s...)
+		}
+		return vertices, segments
+	}
+
+	var vertices []Point
+	var segments [][2]Point
+	var current, start Point
+	for _, cmd := range shape.ToPath().Commands {
+		switch cmd.Type {
+		case MoveToCommand:
+			current, start = cmd.Points[0], cmd.Points[0]
+			vertices = append(vertices, current)
+		case LineToCommand:
+			segments = append(segments, [2]Point{current, cmd.Points[0]})
+			current = cmd.Points[0]
+			vertices = append(vertices, current)
+		case QuadToCommand, CubicToCommand:
+			current = cmd.Points[len(cmd.Points)-1]
+			vertices = append(vertices, current)
+		case ArcToCommand:
+			end := cmd.StartAngle + cmd.Sweep
+			current = Point{
+				X: cmd.Points[0].X + cmd.Radius*math.Cos(end),
+				Y: cmd.Points[0].Y + cmd.Radius*math.Sin(end),
+			}
+		case CloseCommand:
+			if current != start {
+				segments = append(segments, [2]Point{current, start})
+			}
+			current = start
+		}
+	}
+	return vertices, segments
+}
+
+
+func segmentIntersection(a1, a2, b1, b2 Point) (Point, bool) {
+	d := (a2.X-a1.X)*(b2.Y-b1.Y) - (a2.Y-a1.Y)*(b2.X-b1.X)
+	if math.Abs(d) < 1e-9 {
+		return Point{}, false
+	}
+
+	t := ((b1.X-a1.X)*(b2.Y-b1.Y) - (b1.Y-a1.Y)*(b2.X-b1.X)) / d
+	u := ((b1.X-a1.X)*(a2.Y-a1.Y) - (b1.Y-a1.Y)*(a2.X-a1.X)) / d
+	if t < 0 || t > 1 || u < 0 || u > 1 {
+		return Point{}, false
+	}
+	return Point{X: a1.X + t*(a2.X-a1.X), Y: a1.Y + t*(a2.Y-a1.Y)}, true
+}
@@ -0,0 +1,203 @@
+package models
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"testing"
+)
+
+
+func snapLine(x0, y0, x1, y1 float64) *Line {
+	return NewLine(Point{X: x0, Y: y0}, Point{X: x1, Y: y1}, color.RGBA{0, 0, 0, 255}, 1, "regular")
+}
+
+
+func closePoint(a, b Point) bool {
+	return math.Abs(a.X-b.X) < 1e-9 && math.Abs(a.Y-b.Y) < 1e-9
+}
+
+
+func TestSnapPriority(t *testing.T) {
+	long := snapLine(0, 0, 100, 0)
+	short := snapLine(0, 0, 8, 0)
+	cross := []Shape{snapLine(0, 0, 100, 100), snapLine(0, 100, 100, 0)}
+	settings := SnapSettings{SnapToGrid: true, SnapToObjects: true, GridSize: 20, Tolerance: 8}
+
+	tests := []struct {
+		name     string
+		p        Point
+		shapes   []Shape
+		exclude  []Shape
+		settings func(s *SnapSettings)
+		want     SnapResult
+	}{
+		{
+			name:   "vertex",
+			p:      Point{X: 2, Y: 1},
+			shapes: []Shape{long},
+			want:   SnapResult{Point: Point{X: 0, Y: 0}, Kind: SnapVertex, Snapped: true},
+		},
+		{
+			name:   "midpoint",
+			p:      Point{X: 53, Y: 2},
+			shapes: []Shape{long},
+			want:   SnapResult{Point: Point{X: 50, Y: 0}, Kind: SnapMidpoint, Snapped: true},
+		},
+		{
+			name:   "vertex over a nearer midpoint",
+			p:      Point{X: 4, Y: 1},
+			shapes: []Shape{short},
+			want:   SnapResult{Point: Point{X: 0, Y: 0}, Kind: SnapVertex, Snapped: true},
+		},
+		{
+			name:   "intersection over midpoint and center",
+			p:      Point{X: 52, Y: 51},
+			shapes: cross,
+			want:   SnapResult{Point: Point{X: 50, Y: 50}, Kind: SnapIntersection, Snapped: true},
+		},
+		{
+			name:   "midpoint over grid",
+			p:      Point{X: 47, Y: 3},
+			shapes: []Shape{long},
+			want:   SnapResult{Point: Point{X: 50, Y: 0}, Kind: SnapMidpoint, Snapped: true},
+		},
+		{
+			name:   "grid when no object is in tolerance",
+			p:      Point{X: 33, Y: 29},
+			shapes: []Shape{long},
+			want:   SnapResult{Point: Point{X: 40, Y: 20}, Kind: SnapGrid, Snapped: true},
+		},
+		{
+			name:   "at the tolerance edge",
+			p:      Point{X: 0, Y: 8},
+			shapes: []Shape{long},
+			want:   SnapResult{Point: Point{X: 0, Y: 0}, Kind: SnapVertex, Snapped: true},
+		},
+		{
+			name:    "excluded shapes are ignored",
+			p:       Point{X: 2, Y: 1},
+			shapes:  []Shape{long},
+			exclude: []Shape{long},
+			want:    SnapResult{Point: Point{X: 0, Y: 0}, Kind: SnapGrid, Snapped: true},
+		},
+		{
+			name:     "object snapping off",
+			p:        Point{X: 53, Y: 2},
+			shapes:   []Shape{long},
+			settings: func(s *SnapSettings) { s.SnapToObjects = false },
+			want:     SnapResult{Point: Point{X: 60, Y: 0}, Kind: SnapGrid, Snapped: true},
+		},
+		{
+			name:     "nothing to snap to",
+			p:        Point{X: 33, Y: 29},
+			shapes:   []Shape{long},
+			settings: func(s *SnapSettings) { s.SnapToGrid = false },
+			want:     SnapResult{Point: Point{X: 33, Y: 29}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := settings
+			if tt.settings != nil {
+				tt.settings(&s)
+			}
+			got := Snap(tt.p, tt.shapes, tt.exclude, s)
+			if got.Kind != tt.want.Kind || got.Snapped != tt.want.Snapped || !closePoint(got.Point, tt.want.Point) {
+				t.Fatalf("Snap(%v) = %+v, want %+v", tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+
+func TestSegmentIntersection(t *testing.T) {
+	tests := []struct {
+		name           string
+		a1, a2, b1, b2 Point
+		want           Point
+		ok             bool
+	}{
+		{name: "crossing", a1: Point{0, 0}, a2: Point{10, 10}, b1: Point{0, 10}, b2: Point{10, 0}, want: Point{5, 5}, ok: true},
+		{name: "touching at an endpoint", a1: Point{0, 0}, a2: Point{10, 0}, b1: Point{10, 0}, b2: Point{10, 10}, want: Point{10, 0}, ok: true},
+		{name: "T junction", a1: Point{0, 0}, a2: Point{10, 0}, b1: Point{5, 0}, b2: Point{5, 10}, want: Point{5, 0}, ok: true},
+		{name: "lines cross beyond the segments", a1: Point{0, 0}, a2: Point{4, 4}, b1: Point{0, 10}, b2: Point{4, 6}},
+		{name: "parallel", a1: Point{0, 0}, a2: Point{10, 0}, b1: Point{0, 5}, b2: Point{10, 5}},
+		{name: "parallel diagonal", a1: Point{0, 0}, a2: Point{10, 10}, b1: Point{1, 0}, b2: Point{11, 10}},
+		{name: "collinear overlapping", a1: Point{0, 0}, a2: Point{10, 0}, b1: Point{5, 0}, b2: Point{15, 0}},
+		{name: "collinear disjoint", a1: Point{0, 0}, a2: Point{10, 0}, b1: Point{20, 0}, b2: Point{30, 0}},
+		{name: "degenerate segment", a1: Point{5, 5}, a2: Point{5, 5}, b1: Point{0, 5}, b2: Point{10, 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := segmentIntersection(tt.a1, tt.a2, tt.b1, tt.b2)
+			if ok != tt.ok || (ok && !closePoint(got, tt.want)) {
+				t.Fatalf("segmentIntersection = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+
+func TestConstrainAngleOctantBoundaries(t *testing.T) {
+	origin := Point{X: 100, Y: 100}
+	const length = 50
+	at := func(degrees float64) Point {
+		rad := degrees * math.Pi / 180
+		return Point{X: origin.X + length*math.Cos(rad), Y: origin.Y + length*math.Sin(rad)}
+	}
+
+	for octant := 0; octant < 8; octant++ {
+		boundary := 22.5 + 45*float64(octant)
+		tests := []struct {
+			name  string
+			angle float64
+			want  float64
+		}{
+			{name: "below", angle: boundary - 0.5, want: boundary - 22.5},
+			{name: "above", angle: boundary + 0.5, want: boundary + 22.5},
+			{name: "on the axis", angle: boundary - 22.5, want: boundary - 22.5},
+		}
+		for _, tt := range tests {
+			t.Run(fmt.Sprintf("%.1f° %s", boundary, tt.name), func(t *testing.T) {
+				got := ConstrainAngle(origin, at(tt.angle), 45)
+				want := at(tt.want)
+				if math.Abs(got.X-want.X) > 1e-6 || math.Abs(got.Y-want.Y) > 1e-6 {
+					t.Fatalf("ConstrainAngle at %.1f° = %v, want %v (%.1f°)", tt.angle, got, want, tt.want)
+				}
+			})
+		}
+	}
+
+	if got := ConstrainAngle(origin, origin, 45); got != origin {
+		t.Fatalf("ConstrainAngle of a zero-length drag = %v, want the origin", got)
+	}
+}
+
+
+func TestConstrainSquare(t *testing.T) {
+	origin := Point{X: 10, Y: 10}
+	tests := []struct {
+		name string
+		p    Point
+		want Point
+	}{
+		{name: "wider than tall", p: Point{X: 30, Y: 15}, want: Point{X: 30, Y: 30}},
+		{name: "taller than wide", p: Point{X: 14, Y: 20}, want: Point{X: 20, Y: 20}},
+		{name: "up and left", p: Point{X: 4, Y: 2}, want: Point{X: 2, Y: 2}},
+		{name: "down and left", p: Point{X: 0, Y: 13}, want: Point{X: 0, Y: 20}},
+		{name: "up and right", p: Point{X: 13, Y: 0}, want: Point{X: 20, Y: 0}},
+		{name: "already square", p: Point{X: 15, Y: 5}, want: Point{X: 15, Y: 5}},
+		{name: "no drag", p: origin, want: origin},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ConstrainSquare(origin, tt.p); got != tt.want {
+				t.Fatalf("ConstrainSquare(%v) = %v, want %v", tt.p, got, tt.want)
+			}
+		})
+	}
+}
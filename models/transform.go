@@ -0,0 +1,139 @@
+package models
+
+import (
+	"math"
+)
+
+
+type Matrix struct {
+	A, B, C, D, E, F float64
+}
+
+
+func IdentityMatrix() Matrix {
+	return Matrix{A: 1, D: 1}
+}
+
+
+func TranslateMatrix(tx, ty float64) Matrix {
+	return Matrix{A: 1, D: 1, E: tx, F: ty}
+}
+
+
+func RotateMatrix(angle float64) Matrix {
+	cos, sin := math.Cos(angle), math.Sin(angle)
+	return Matrix{A: cos, B: sin, C: -sin, D: cos}
+}
+
+
+func ScaleMatrix(sx, sy float64) Matrix {
+	return Matrix{A: sx, D: sy}
+}
+
+
+func SkewMatrix(angleX, angleY float64) Matrix {
+	return Matrix{A: 1, B: math.Tan(angleY), C: math.Tan(angleX), D: 1}
+}
+
+
+func (m Matrix) Multiply(other Matrix) Matrix {
+	return Matrix{
+		A: m.A*other.A + m.C*other.B,
+		B: m.B*other.A + m.D*other.B,
+		C: m.A*other.C + m.C*other.D,
+		D: m.B*other.C + m.D*other.D,
+		E: m.A*other.E + m.C*other.F + m.E,
+		F: m.B*other.E + m.D*other.F + m.F,
+	}
+}
+
+
+func (m Matrix) About(center Point) Matrix {
+	return TranslateMatrix(center.X, center.Y).Multiply(m).Multiply(TranslateMatrix(-center.X, -center.Y))
+}
+
+
+func (m Matrix) Apply(p Point) Point {
+	return Point{
+		X: m.A*p.X + m.C*p.Y + m.E,
+		Y: m.B*p.X + m.D*p.Y + m.F,
+	}
+}
+
+
+func (m Matrix) Determinant() float64 {
+	return m.A*m.D - m.B*m.C
+}
+
+
+func (m Matrix) Inverse() (Matrix, bool) {
+	det := m.Determinant()
+	if math.Abs(det) < 1e-12 {
+		return Matrix{}, false
+	}
+	return Matrix{
+		A: m.D / det,
+		B: -m.B / det,
+		C: -m.C / det,
+		D: m.A / det,
+		E: (m.C*m.F - m.D*m.E) / det,
+		F: (m.B*m.E - m.A*m.F) / det,
+	}, true
+}
+
+
+func (m Matrix) ScaleFactor() float64 {
+	return math.Sqrt(math.Abs(m.Determinant()))
+}
+
+
+func (m Matrix) IsSimilarity() bool {
+	const eps = 1e-9
+	rotation := math.Abs(m.A-m.D) < eps && math.Abs(m.B+m.C) < eps
+	reflection := math.Abs(m.A+m.D) < eps && math.Abs(m.B-m.C) < eps
+	return rotation || reflection
+}
+
+
+func (m Matrix) IsAxisAligned() bool {
+	const eps = 1e-9
+	return (math.Abs(m.B) < eps && math.Abs(m.C) < eps) ||
+		(math.Abs(m.A) < eps && math.Abs(m.D) < eps)
+}
+
+
+func ShapeBounds(s Shape) (Point, Point) {
+	var points []Point
+	for _, sp := range s.ToPath().Subpaths() {
+		points = append(points, sp.Points...)
+	}
+	if len(points) == 0 {
+		points = s.GetControlPoints()
+	}
+	if len(points) == 0 {
+		return Point{}, Point{}
+	}
+
+	minP, maxP := points[0], points[0]
+	for _, p := range points[1:] {
+		if p.X < minP.X {
+			minP.X = p.X
+		}
+		if p.Y < minP.Y {
+			minP.Y = p.Y
+		}
+		if p.X > maxP.X {
+			maxP.X = p.X
+		}
+		if p.Y > maxP.Y {
+			maxP.Y = p.Y
+		}
+	}
+	return minP, maxP
+}
+
+
+func ShapeCenter(s Shape) Point {
+	minP, maxP := ShapeBounds(s)
+	return Point{X: (minP.X + maxP.X) / 2, Y: (minP.Y + maxP.Y) / 2}
+}
@@ -0,0 +1,195 @@
+package models
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"testing"
+)
+
+
+func closeMatrix(a, b Matrix) bool {
+	const eps = 1e-9
+	return math.Abs(a.A-b.A) < eps && math.Abs(a.B-b.B) < eps && math.Abs(a.C-b.C) < eps &&
+		math.Abs(a.D-b.D) < eps && math.Abs(a.E-b.E) < eps && math.Abs(a.F-b.F) < eps
+}
+
+
+var testMatrices = []struct {
+	name string
+	m    Matrix
+}{
+	{name: "translate", m: TranslateMatrix(12, -7)},
+	{name: "rotate", m: RotateMatrix(math.Pi / 5)},
+	{name: "uniform scale", m: ScaleMatrix(2.5, 2.5)},
+	{name: "scale", m: ScaleMatrix(2, 0.5)},
+	{name: "reflect", m: ScaleMatrix(-1, 1)},
+	{name: "skew", m: SkewMatrix(0.4, -0.2)},
+	{name: "scale and skew about a point", m: ScaleMatrix(1.5, 3).Multiply(SkewMatrix(0.3, 0.1)).About(Point{X: 40, Y: 25})},
+}
+
+
+func TestMatrixIdentity(t *testing.T) {
+	identity := IdentityMatrix()
+	p := Point{X: 3.5, Y: -8}
+	if got := identity.Apply(p); got != p {
+		t.Fatalf("identity moved %v to %v", p, got)
+	}
+	for _, tt := range testMatrices {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.Multiply(identity); !closeMatrix(got, tt.m) {
+				t.Fatalf("m * I = %+v, want %+v", got, tt.m)
+			}
+			if got := identity.Multiply(tt.m); !closeMatrix(got, tt.m) {
+				t.Fatalf("I * m = %+v, want %+v", got, tt.m)
+			}
+		})
+	}
+	if !identity.IsSimilarity() || !identity.IsAxisAligned() || identity.ScaleFactor() != 1 {
+		t.Fatalf("identity is not reported as an unscaled, axis-aligned similarity")
+	}
+}
+
+
+func TestMatrixInverse(t *testing.T) {
+	for _, tt := range testMatrices {
+		t.Run(tt.name, func(t *testing.T) {
+			inverse, ok := tt.m.Inverse()
+			if !ok {
+				t.Fatalf("Inverse reported %+v as singular", tt.m)
+			}
+			if got := tt.m.Multiply(inverse); !closeMatrix(got, IdentityMatrix()) {
+				t.Fatalf("m * m⁻¹ = %+v, want the identity", got)
+			}
+			if got := inverse.Multiply(tt.m); !closeMatrix(got, IdentityMatrix()) {
+				t.Fatalf("m⁻¹ * m = %+v, want the identity", got)
+			}
+			p := Point{X: 17, Y: -4}
+			if got := inverse.Apply(tt.m.Apply(p)); math.Hypot(got.X-p.X, got.Y-p.Y) > 1e-9 {
+				t.Fatalf("round trip moved %v to %v", p, got)
+			}
+		})
+	}
+
+	for _, m := range []Matrix{ScaleMatrix(0, 1), {A: 1, B: 2, C: 2, D: 4}, {}} {
+		if _, ok := m.Inverse(); ok {
+			t.Fatalf("Inverse of singular %+v succeeded", m)
+		}
+	}
+}
+
+
+func TestRotateAboutPoint(t *testing.T) {
+	center := Point{X: 10, Y: 10}
+	quarter := RotateMatrix(math.Pi / 2).About(center)
+
+	tests := []struct {
+		p, want Point
+	}{
+		{p: center, want: center},
+		{p: Point{X: 20, Y: 10}, want: Point{X: 10, Y: 20}},
+		{p: Point{X: 10, Y: 20}, want: Point{X: 0, Y: 10}},
+		{p: Point{X: 13, Y: 14}, want: Point{X: 6, Y: 13}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.p), func(t *testing.T) {
+			if got := quarter.Apply(tt.p); math.Hypot(got.X-tt.want.X, got.Y-tt.want.Y) > 1e-9 {
+				t.Fatalf("quarter turn about %v moved %v to %v, want %v", center, tt.p, got, tt.want)
+			}
+		})
+	}
+
+	full := quarter.Multiply(quarter).Multiply(quarter).Multiply(quarter)
+	if !closeMatrix(full, IdentityMatrix()) {
+		t.Fatalf("four quarter turns = %+v, want the identity", full)
+	}
+}
+
+
+func expandedPath(shape Shape) *Path {
+	path := shape.ToPath()
+	path.expandArcs()
+	return path
+}
+
+
+func expectSamePath(t *testing.T, got, want *Path) {
+	t.Helper()
+	if len(got.Commands) != len(want.Commands) {
+		t.Fatalf("%d path commands, want %d", len(got.Commands), len(want.Commands))
+	}
+	for i, w := range want.Commands {
+		g := got.Commands[i]
+		if g.Type != w.Type || len(g.Points) != len(w.Points) {
+			t.Fatalf("command %d is %v with %d points, want %v with %d", i, g.Type, len(g.Points), w.Type, len(w.Points))
+		}
+		for j := range w.Points {
+			if math.Hypot(g.Points[j].X-w.Points[j].X, g.Points[j].Y-w.Points[j].Y) > 1e-6 {
+				t.Fatalf("command %d point %d = %v, want %v", i, j, g.Points[j], w.Points[j])
+			}
+		}
+	}
+}
+
+
+func TestTransformRoundTrip(t *testing.T) {
+	black := color.RGBA{0, 0, 0, 255}
+	pill := NewPill(Point{X: 10, Y: 10}, 6, black)
+	pill.End = Point{X: 40, Y: 22}
+	shapes := []struct {
+		name  string
+		shape func() Shape
+	}{
+		{name: "line", shape: func() Shape { return NewLine(Point{X: 1, Y: 2}, Point{X: 30, Y: 45}, black, 1, "regular") }},
+		{name: "circle", shape: func() Shape { return NewCircle(Point{X: 25, Y: 30}, 12, black) }},
+		{name: "polygon", shape: func() Shape {
+			return NewPolygon([]Point{{X: 0, Y: 0}, {X: 20, Y: 5}, {X: 15, Y: 25}, {X: 8, Y: 12}}, black, 1)
+		}},
+		{name: "pill", shape: func() Shape { return pill.Clone() }},
+		{name: "group", shape: func() Shape {
+			return NewGroup([]Shape{
+				NewLine(Point{X: 5, Y: 5}, Point{X: 15, Y: 9}, black, 1, "regular"),
+				NewCircle(Point{X: 50, Y: 50}, 8, black),
+				NewGroup([]Shape{pill.Clone()}),
+			})
+		}},
+	}
+
+	for _, s := range shapes {
+		for _, tt := range testMatrices {
+			t.Run(s.name+"/"+tt.name, func(t *testing.T) {
+				inverse, _ := tt.m.Inverse()
+				original := s.shape()
+				got := s.shape().Transform(tt.m).Transform(inverse)
+				expectSamePath(t, expandedPath(got), expandedPath(original))
+
+				if tt.m.IsSimilarity() {
+					if fmt.Sprintf("%T", got) != fmt.Sprintf("%T", original) {
+						t.Fatalf("a similarity turned %T into %T", original, got)
+					}
+				}
+			})
+		}
+	}
+}
+
+
+func TestTransformNonSimilarityConvertsCurves(t *testing.T) {
+	black := color.RGBA{0, 0, 0, 255}
+	m := ScaleMatrix(2, 1)
+
+	circle := NewCircle(Point{X: 0, Y: 0}, 10, black).Transform(m)
+	path, ok := circle.(*Path)
+	if !ok {
+		t.Fatalf("non-uniform scale of a circle gave %T, want *Path", circle)
+	}
+	lo, hi := ShapeBounds(path)
+	if math.Abs(lo.X+20) > 1e-6 || math.Abs(hi.X-20) > 1e-6 || math.Abs(lo.Y+10) > 1e-6 || math.Abs(hi.Y-10) > 1e-6 {
+		t.Fatalf("scaled circle bounds = %v..%v, want (-20,-10)..(20,10)", lo, hi)
+	}
+
+	scaled := NewCircle(Point{X: 5, Y: 5}, 10, black).Transform(RotateMatrix(1).Multiply(ScaleMatrix(3, 3)))
+	if c, ok := scaled.(*Circle); !ok || math.Abs(c.Radius-30) > 1e-9 {
+		t.Fatalf("uniform scale of a circle gave %#v, want a circle of radius 30", scaled)
+	}
+}
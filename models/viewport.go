@@ -0,0 +1,107 @@
+package models
+
+import (
+	"math"
+)
+
+
+const (
+	MinZoom = 0.1
+	MaxZoom = 32
+)
+
+
+type Viewport struct {
+	Zoom float64
+	PanX float64
+	PanY float64
+}
+
+
+func NewViewport() Viewport {
+	return Viewport{Zoom: 1}
+}
+
+
+func (v Viewport) IsIdentity() bool {
+	return v.Zoom == 1 && v.PanX == 0 && v.PanY == 0
+}
+
+
+func (v Viewport) Matrix() Matrix {
+	return Matrix{A: v.Zoom, D: v.Zoom, E: v.PanX, F: v.PanY}
+}
+
+
+type Stroked interface {
+	GetThickness() int
+	SetThickness(thickness int)
+}
+
+
+func (v Viewport) ViewShape(shape Shape) Shape {
+	if v.IsIdentity() {
+		return shape
+	}
+	view := shape.Clone().Transform(v.Matrix())
+	scaleStroke(view, v.Zoom)
+	return view
+}
+
+
+func scaleStroke(shape Shape, zoom float64) {
+	if group, ok := shape.(*Group); ok {
+		for _, child := range group.Children {
+			scaleStroke(child, zoom)
+		}
+		return
+	}
+	if stroked, ok := shape.(Stroked); ok {
+		stroked.SetThickness(int(math.Max(1, math.Round(float64(stroked.GetThickness())*zoom))))
+	}
+}
+
+
+func (v Viewport) ToScreen(p Point) Point {
+	return Point{X: p.X*v.Zoom + v.PanX, Y: p.Y*v.Zoom + v.PanY}
+}
+
+
+func (v Viewport) ToDocument(p Point) Point {
+	return Point{X: (p.X - v.PanX) / v.Zoom, Y: (p.Y - v.PanY) / v.Zoom}
+}
+
+
+func (v *Viewport) Pan(dx, dy float64) {
+	v.PanX += dx
+	v.PanY += dy
+}
+
+
+func (v *Viewport) ZoomAt(screen Point, zoom float64) {
+	zoom = math.Max(MinZoom, math.Min(MaxZoom, zoom))
+	doc := v.ToDocument(screen)
+	v.Zoom = zoom
+	v.PanX = screen.X - doc.X*zoom
+	v.PanY = screen.Y - doc.Y*zoom
+}
+
+
+func (v *Viewport) Fit(minP, maxP Point, width, height, margin float64) {
+	w := maxP.X - minP.X
+	h := maxP.Y - minP.Y
+	availW := width - 2*margin
+	availH := height - 2*margin
+	if w <= 0 || h <= 0 || availW <= 0 || availH <= 0 {
+		v.Zoom = 1
+	} else {
+		v.Zoom = math.Max(MinZoom, math.Min(MaxZoom, math.Min(availW/w, availH/h)))
+	}
+	v.PanX = width/2 - (minP.X+maxP.X)/2*v.Zoom
+	v.PanY = height/2 - (minP.Y+maxP.Y)/2*v.Zoom
+}
+
+
+func (v Viewport) VisibleRect(width, height float64) (Point, Point) {
+	return v.ToDocument(Point{}), v.ToDocument(Point{X: width - 1, Y: height - 1})
+}
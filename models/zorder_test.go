@@ -0,0 +1,161 @@
+package models
+
+import (
+	"strings"
+	"testing"
+)
+
+
+func shapeOrder(state *DrawingState) string {
+	keys := make([]string, len(state.Shapes))
+	for i, shape := range state.Shapes {
+		keys[i] = shapeKey(shape)
+	}
+	return strings.Join(keys, " ")
+}
+
+
+func TestZOrder(t *testing.T) {
+	names := []string{"A", "B", "C", "D"}
+	initial := map[string]string{"A": "0 10", "B": "20 [50 60]", "C": "", "D": "30 40"}
+
+	tests := []struct {
+		name     string
+		selected []string
+		op       ZOrderOp
+		contents map[string]string
+	}{
+		{
+			name:     "bring forward",
+			selected: []string{"0"},
+			op:       BringForward,
+			contents: map[string]string{"A": "10 0", "B": "20 [50 60]", "C": "", "D": "30 40"},
+		},
+		{
+			name:     "send backward",
+			selected: []string{"[50 60]"},
+			op:       SendBackward,
+			contents: map[string]string{"A": "0 10", "B": "[50 60] 20", "C": "", "D": "30 40"},
+		},
+		{
+			name:     "bring to front",
+			selected: []string{"30"},
+			op:       BringToFront,
+			contents: map[string]string{"A": "0 10", "B": "20 [50 60]", "C": "", "D": "40 30"},
+		},
+		{
+			name:     "send to back",
+			selected: []string{"10"},
+			op:       SendToBack,
+			contents: map[string]string{"A": "10 0", "B": "20 [50 60]", "C": "", "D": "30 40"},
+		},
+		{
+			name:     "bring forward stops at the top of the layer",
+			selected: []string{"10"},
+			op:       BringForward,
+			contents: initial,
+		},
+		{
+			name:     "send backward stops at the bottom of the layer",
+			selected: []string{"20"},
+			op:       SendBackward,
+			contents: initial,
+		},
+		{
+			name:     "bring forward across a layer boundary",
+			selected: []string{"10", "20"},
+			op:       BringForward,
+			contents: map[string]string{"A": "0 10", "B": "[50 60] 20", "C": "", "D": "30 40"},
+		},
+		{
+			name:     "send backward past an empty layer",
+			selected: []string{"10", "30"},
+			op:       SendBackward,
+			contents: map[string]string{"A": "10 0", "B": "20 [50 60]", "C": "", "D": "30 40"},
+		},
+		{
+			name:     "bring to front in every layer",
+			selected: []string{"0", "20", "30"},
+			op:       BringToFront,
+			contents: map[string]string{"A": "10 0", "B": "[50 60] 20", "C": "", "D": "40 30"},
+		},
+		{
+			name:     "send to back in every layer",
+			selected: []string{"10", "[50 60]", "40"},
+			op:       SendToBack,
+			contents: map[string]string{"A": "10 0", "B": "[50 60] 20", "C": "", "D": "40 30"},
+		},
+		{
+			name:     "a whole layer selected stays put",
+			selected: []string{"30", "40"},
+			op:       BringForward,
+			contents: initial,
+		},
+		{
+			name:     "a block keeps its own order",
+			selected: []string{"0", "10"},
+			op:       SendToBack,
+			contents: initial,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := layeredState()
+			before := shapeOrder(state)
+			var selected []Shape
+			for _, key := range tt.selected {
+				selected = append(selected, shapeAt(state, key))
+			}
+
+			order := state.ZOrder(selected, tt.op)
+			cmd := &ReorderShapesCommand{Order: order, Label: tt.op.String()}
+			cmd.Do(state)
+			expectLayers(t, state, names, tt.contents)
+			if moved := !IsIdentityOrder(order); moved != (shapeOrder(state) != before) {
+				t.Fatalf("IsIdentityOrder = %v, but the order went from %s to %s", !moved, before, shapeOrder(state))
+			}
+
+			cmd.Undo(state)
+			if got := shapeOrder(state); got != before {
+				t.Fatalf("undo left order %s, want %s", got, before)
+			}
+			expectLayers(t, state, names, initial)
+		})
+	}
+}
+
+
+func TestZOrderWithoutLayers(t *testing.T) {
+	state := &DrawingState{Shapes: []Shape{historyLine(0), historyLine(10), historyLine(20), historyLine(30)}}
+
+	tests := []struct {
+		name     string
+		selected []int
+		op       ZOrderOp
+		want     string
+	}{
+		{name: "bring forward", selected: []int{0, 2}, op: BringForward, want: "10 0 30 20"},
+		{name: "send backward", selected: []int{1, 3}, op: SendBackward, want: "10 0 30 20"},
+		{name: "bring to front", selected: []int{0, 1}, op: BringToFront, want: "20 30 0 10"},
+		{name: "send to back", selected: []int{3}, op: SendToBack, want: "30 0 10 20"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var selected []Shape
+			for _, i := range tt.selected {
+				selected = append(selected, state.Shapes[i])
+			}
+			cmd := &ReorderShapesCommand{Order: state.ZOrder(selected, tt.op)}
+			cmd.Do(state)
+			if got := shapeOrder(state); got != tt.want {
+				t.Fatalf("order = %s, want %s", got, tt.want)
+			}
+			cmd.Undo(state)
+			if got := shapeOrder(state); got != "0 10 20 30" {
+				t.Fatalf("undo left order %s", got)
+			}
+		})
+	}
+}
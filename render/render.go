@@ -0,0 +1,216 @@
+package render
+
+import (
+	"flag"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"math"
+	"os"
+	"paint-drawer-pro/algorithms"
+	"paint-drawer-pro/models"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/image/bmp"
+)
+
+
+type Options struct {
+	Width        int
+	Height       int
+	Scale        float64
+	Fit          bool
+	Margin       float64
+	AntiAliasing bool
+	Background   color.Color
+	Raster       models.RasterAlgorithms
+}
+
+
+func Run(args []string, out, errOut io.Writer) error {
+	fs := flag.NewFlagSet("render", flag.ContinueOnError)
+	output := fs.String("o", "", "output image path (.png, .jpg, .jpeg or .bmp)")
+	format := fs.String("format", "", "output format: png, jpeg or bmp (default: from the output extension)")
+	width := fs.Int("width", 0, "image width in pixels (default: fit the drawing)")
+	height := fs.Int("height", 0, "image height in pixels (default: fit the drawing)")
+	scale := fs.Float64("scale", 1, "zoom factor applied to document coordinates")
+	fit := fs.Bool("fit", false, "scale the drawing to fit -width x -height")
+	margin := fs.Float64("margin", 10, "padding around the drawing when fitting")
+	antiAliasing := fs.Bool("aa", true, "render with anti-aliasing")
+	background := fs.String("background", "white", "background: white, transparent or #rrggbb")
+	quality := fs.Int("quality", 90, "JPEG quality (1-100)")
+	lineAlgorithm := fs.String("line", "", "line algorithm name (default: per-shape, then the drawing's, then built-in)")
+	circleAlgorithm := fs.String("circle", "", "circle algorithm name (default: per-shape, then the drawing's, then built-in)")
+	fillAlgorithm := fs.String("fill", "", "fill algorithm name (default: per-shape, then the drawing's, then built-in)")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: paint-drawer-pro render [flags] -o output.png drawing.json")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 || *output == "" {
+		fs.Usage()
+		return fmt.Errorf("render needs one drawing file and an -o output path")
+	}
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*output)), ".")
+	}
+	encode, err := encoder(*format, *quality)
+	if err != nil {
+		return err
+	}
+
+	bg, err := parseBackground(*background)
+	if err != nil {
+		return err
+	}
+	if *scale <= 0 {
+		return fmt.Errorf("-scale must be positive")
+	}
+	if *fit && (*width <= 0 || *height <= 0) {
+		return fmt.Errorf("-fit needs -width and -height")
+	}
+
+	raster := models.RasterAlgorithms{Line: *lineAlgorithm, Circle: *circleAlgorithm, Fill: *fillAlgorithm}
+	if err := checkAlgorithms(raster); err != nil {
+		return err
+	}
+
+	doc, report, err := models.LoadDocument(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	for _, d := range report.Diagnostics {
+		fmt.Fprintf(errOut, "warning: %s: %s\n", fs.Arg(0), d)
+	}
+
+	img, err := Render(doc, Options{
+		Width:        *width,
+		Height:       *height,
+		Scale:        *scale,
+		Fit:          *fit,
+		Margin:       *margin,
+		AntiAliasing: *antiAliasing,
+		Background:   bg,
+		Raster:       raster,
+	})
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("error creating output: %v", err)
+	}
+	if err := encode(file, img); err != nil {
+		file.Close()
+		return fmt.Errorf("error encoding %s: %v", *format, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error writing output: %v", err)
+	}
+
+	fmt.Fprintf(out, "Rendered %s (%dx%d) to %s\n", fs.Arg(0), img.Bounds().Dx(), img.Bounds().Dy(), *output)
+	return nil
+}
+
+
+func Render(doc *models.Document, opts Options) (*image.RGBA, error) {
+	state := doc.State()
+	state.Raster = opts.Raster.Resolve(state.Raster)
+	view := models.Viewport{Zoom: opts.Scale}
+	width, height := opts.Width, opts.Height
+
+	if opts.Fit {
+		minP, maxP, ok := contentBounds(state)
+		if ok {
+			view.Fit(minP, maxP, float64(width), float64(height), opts.Margin)
+		}
+	} else if width <= 0 || height <= 0 {
+		minP, maxP, ok := contentBounds(state)
+		if !ok {
+			return nil, fmt.Errorf("drawing is empty; pass -width and -height to render a blank image")
+		}
+		view.PanX = opts.Margin - minP.X*opts.Scale
+		view.PanY = opts.Margin - minP.Y*opts.Scale
+		if width <= 0 {
+			width = int(math.Ceil((maxP.X-minP.X)*opts.Scale+2*opts.Margin)) + 1
+		}
+		if height <= 0 {
+			height = int(math.Ceil((maxP.Y-minP.Y)*opts.Scale+2*opts.Margin)) + 1
+		}
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	if opts.Background != nil {
+		bg := color.RGBAModel.Convert(opts.Background).(color.RGBA)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				img.SetRGBA(x, y, bg)
+			}
+		}
+	}
+
+	models.RenderLayers(img, state, view, opts.AntiAliasing)
+	return img, nil
+}
+
+
+func contentBounds(state *models.DrawingState) (models.Point, models.Point, bool) {
+	shapes := state.VisibleShapes()
+	return (&models.DrawingState{Shapes: shapes, Selection: shapes}).SelectionBounds()
+}
+
+
+func encoder(format string, quality int) (func(io.Writer, image.Image) error, error) {
+	switch format {
+	case "png":
+		return png.Encode, nil
+	case "jpg", "jpeg":
+		if quality < 1 || quality > 100 {
+			return nil, fmt.Errorf("-quality must be between 1 and 100")
+		}
+		return func(w io.Writer, img image.Image) error {
+			return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+		}, nil
+	case "bmp":
+		return bmp.Encode, nil
+	}
+	return nil, fmt.Errorf("unsupported output format %q (use png, jpeg or bmp)", format)
+}
+
+
+func parseBackground(value string) (color.Color, error) {
+	switch strings.ToLower(value) {
+	case "white":
+		return color.White, nil
+	case "transparent", "none":
+		return nil, nil
+	}
+
+	var r, g, b uint8
+	if _, err := fmt.Sscanf(value, "#%02x%02x%02x", &r, &g, &b); err != nil || len(value) != 7 {
+		return nil, fmt.Errorf("invalid background %q", value)
+	}
+	return color.RGBA{r, g, b, 255}, nil
+}
+
+
+func checkAlgorithms(raster models.RasterAlgorithms) error {
+	if _, ok := algorithms.LookupLineAlgorithm(raster.Line); raster.Line != "" && !ok {
+		return fmt.Errorf("unknown line algorithm %q (available: %s)", raster.Line, strings.Join(algorithms.LineAlgorithmNames(), ", "))
+	}
+	if _, ok := algorithms.LookupCircleAlgorithm(raster.Circle); raster.Circle != "" && !ok {
+		return fmt.Errorf("unknown circle algorithm %q (available: %s)", raster.Circle, strings.Join(algorithms.CircleAlgorithmNames(), ", "))
+	}
+	if _, ok := algorithms.LookupFillAlgorithm(raster.Fill); raster.Fill != "" && !ok {
+		return fmt.Errorf("unknown fill algorithm %q (available: %s)", raster.Fill, strings.Join(algorithms.FillAlgorithmNames(), ", "))
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package render
+
+import (
+	"bytes"
+	"image/color"
+	"os"
+	"paint-drawer-pro/models"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+
+func TestRunWritesWarningsToErrOut(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "drawing.json")
+	output := filepath.Join(dir, "drawing.png")
+	document := `{"version": 2, "layers": [{"name": "Layer 1", "visible": true, "opacity": 1, "blendMode": "normal", "shapes": [
+		{"type": "circle", "center": {"X": 20, "Y": 20}, "radius": 10, "color": {"R": 0, "G": 0, "B": 0, "A": 255}},
+		null
+	]}]}`
+	if err := os.WriteFile(input, []byte(document), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out, errOut bytes.Buffer
+	if err := Run([]string{"-o", output, input}, &out, &errOut); err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+
+	if strings.Contains(out.String(), "warning") {
+		t.Fatalf("warnings were written to out: %q", out.String())
+	}
+	if !strings.Contains(errOut.String(), "not a JSON object") {
+		t.Fatalf("errOut = %q, want the skipped null shape", errOut.String())
+	}
+	if !strings.Contains(out.String(), "Rendered") {
+		t.Fatalf("out = %q, want the render summary", out.String())
+	}
+}
+
+
+var (
+	testWhite = color.RGBA{255, 255, 255, 255}
+	testRed   = color.RGBA{255, 0, 0, 255}
+	testBlue  = color.RGBA{0, 0, 255, 255}
+)
+
+
+func filledRectangle(topLeft, bottomRight models.Point, c color.Color) *models.Rectangle {
+	r := models.NewRectangle(topLeft, bottomRight, c, 1)
+	r.IsFilled = true
+	r.FillColor = c
+	return r
+}
+
+
+func testDocument(t *testing.T) *models.Document {
+	t.Helper()
+	visible := models.NewLayer("Visible")
+	hidden := models.NewLayer("Hidden")
+	hidden.Visible = false
+	visible.Count, hidden.Count = 1, 1
+	state := &models.DrawingState{
+		Shapes: []models.Shape{
+			filledRectangle(models.Point{X: 10, Y: 20}, models.Point{X: 50, Y: 40}, testRed),
+			filledRectangle(models.Point{X: 100, Y: 100}, models.Point{X: 120, Y: 120}, testBlue),
+		},
+		Layers:         []*models.Layer{visible, hidden},
+		SelectedVertex: -1,
+	}
+
+	data, err := models.MarshalDocument(state)
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc, _, err := models.ParseDocument(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return doc
+}
+
+
+func TestRender(t *testing.T) {
+	type pixel struct {
+		x, y int
+		c    color.RGBA
+	}
+
+	tests := []struct {
+		name          string
+		opts          Options
+		width, height int
+		pixels        []pixel
+	}{
+		{
+			name:   "sized to the visible content",
+			opts:   Options{Scale: 1, Margin: 10, Background: testWhite},
+			width:  61,
+			height: 41,
+			pixels: []pixel{{30, 20, testRed}, {12, 12, testRed}, {48, 28, testRed}, {2, 2, testWhite}, {58, 38, testWhite}},
+		},
+		{
+			name:   "scaled",
+			opts:   Options{Scale: 2, Margin: 10, Background: testWhite},
+			width:  101,
+			height: 61,
+			pixels: []pixel{{50, 30, testRed}, {14, 14, testRed}, {86, 46, testRed}, {5, 5, testWhite}, {95, 55, testWhite}},
+		},
+		{
+			name:   "fit to the requested size",
+			opts:   Options{Width: 200, Height: 100, Scale: 1, Fit: true, Margin: 10, Background: testWhite},
+			width:  200,
+			height: 100,
+			pixels: []pixel{{100, 50, testRed}, {25, 15, testRed}, {175, 85, testRed}, {15, 50, testWhite}, {100, 5, testWhite}, {190, 95, testWhite}},
+		},
+		{
+			name:   "fixed size without fitting",
+			opts:   Options{Width: 60, Height: 50, Scale: 1, Margin: 10, Background: testWhite},
+			width:  60,
+			height: 50,
+			pixels: []pixel{{30, 30, testRed}, {5, 5, testWhite}, {55, 45, testWhite}},
+		},
+		{
+			name:   "transparent background",
+			opts:   Options{Scale: 1, Margin: 10},
+			width:  61,
+			height: 41,
+			pixels: []pixel{{30, 20, testRed}, {2, 2, color.RGBA{}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := Render(testDocument(t), tt.opts)
+			if err != nil {
+				t.Fatalf("Render: %v", err)
+			}
+			if got := img.Bounds().Size(); got.X != tt.width || got.Y != tt.height {
+				t.Fatalf("image is %dx%d, want %dx%d", got.X, got.Y, tt.width, tt.height)
+			}
+			for _, p := range tt.pixels {
+				if got := img.RGBAAt(p.x, p.y); got != p.c {
+					t.Fatalf("pixel (%d, %d) = %v, want %v", p.x, p.y, got, p.c)
+				}
+			}
+			for y := 0; y < tt.height; y++ {
+				for x := 0; x < tt.width; x++ {
+					if img.RGBAAt(x, y) == testBlue {
+						t.Fatalf("pixel (%d, %d) is from the hidden layer", x, y)
+					}
+				}
+			}
+		})
+	}
+}
+
+
+func TestRenderEmptyDrawing(t *testing.T) {
+	doc, _, err := models.ParseDocument([]byte(`{"version": 2, "layers": [{"name": "Layer 1", "visible": true, "opacity": 1, "blendMode": "normal", "shapes": []}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Render(doc, Options{Scale: 1, Margin: 10}); err == nil {
+		t.Fatalf("Render of an empty drawing without a size succeeded")
+	}
+
+	img, err := Render(doc, Options{Width: 8, Height: 4, Scale: 1, Background: testWhite})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if got := img.Bounds().Size(); got.X != 8 || got.Y != 4 {
+		t.Fatalf("image is %dx%d, want 8x4", got.X, got.Y)
+	}
+	if got := img.RGBAAt(3, 2); got != testWhite {
+		t.Fatalf("blank image pixel = %v, want the background", got)
+	}
+}
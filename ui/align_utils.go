@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"paint-drawer-pro/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+
+var alignReferenceNames = []string{"Selection", "Key Shape", "Canvas"}
+
+
+func (ui *MainUI) alignSelection(edge models.AlignEdge) {
+	selected := ui.State.SelectedShapes()
+	if len(selected) == 0 || (len(selected) < 2 && ui.AlignReference != models.AlignToCanvas) {
+		ui.StatusLabel.SetText("Select at least two shapes, or align to the canvas.")
+		return
+	}
+
+	var refMin, refMax models.Point
+	switch ui.AlignReference {
+	case models.AlignToCanvas:
+		refMin, refMax = ui.Viewport.VisibleRect(ui.canvasSize())
+	case models.AlignToKeyShape:
+		refMin, refMax = models.ShapeBounds(ui.State.SelectedShape)
+	default:
+		refMin, refMax, _ = ui.State.SelectionBounds()
+	}
+
+	ui.offsetShapes(selected, models.AlignOffsets(selected, edge, refMin, refMax), edge.String())
+}
+
+
+func (ui *MainUI) distributeSelection(axis models.DistributeAxis) {
+	selected := ui.State.SelectedShapes()
+	if len(selected) < 3 {
+		ui.StatusLabel.SetText("Select at least three shapes to distribute.")
+		return
+	}
+
+	ui.offsetShapes(selected, models.DistributeOffsets(selected, axis), axis.String())
+}
+
+
+func (ui *MainUI) offsetShapes(shapes []models.Shape, offsets []models.Point, label string) {
+	ui.Editor.EditShapes(shapes, label, func() {
+		for i, shape := range shapes {
+			if offsets[i].X != 0 || offsets[i].Y != 0 {
+				shape.Move(offsets[i].X, offsets[i].Y)
+			}
+		}
+	})
+	ui.StatusLabel.SetText(label)
+	ui.Canvas.Refresh()
+}
+
+
+func (ui *MainUI) buildAlignControls() *fyne.Container {
+	referenceSelect := widget.NewSelect(alignReferenceNames, func(selected string) {
+		for i, name := range alignReferenceNames {
+			if name == selected {
+				ui.AlignReference = models.AlignReference(i)
+			}
+		}
+	})
+	referenceSelect.SetSelected(alignReferenceNames[ui.AlignReference])
+
+	alignBtn := func(label string, edge models.AlignEdge) *widget.Button {
+		return widget.NewButton(label, func() {
+			ui.alignSelection(edge)
+		})
+	}
+	distributeBtn := func(label string, axis models.DistributeAxis) *widget.Button {
+		return widget.NewButton(label, func() {
+			ui.distributeSelection(axis)
+		})
+	}
+
+	return container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel("Align to:"), nil, referenceSelect),
+		container.NewGridWithColumns(3,
+			alignBtn("Left", models.AlignLeft),
+			alignBtn("Center", models.AlignCenter),
+			alignBtn("Right", models.AlignRight),
+			alignBtn("Top", models.AlignTop),
+			alignBtn("Middle", models.AlignMiddle),
+			alignBtn("Bottom", models.AlignBottom),
+		),
+		container.NewGridWithColumns(2,
+			distributeBtn("Distribute H", models.DistributeHorizontal),
+			distributeBtn("Distribute V", models.DistributeVertical),
+		),
+	)
+}
@@ -0,0 +1,204 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+	"paint-drawer-pro/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"golang.design/x/clipboard"
+)
+
+
+const (
+	clipboardFormat  = "paint-drawer-pro/shapes"
+	pasteOffset      = 10
+	pngExportPadding = 4
+)
+
+
+type clipboardContent struct {
+	Format  string            `json:"format"`
+	Version int               `json:"version"`
+	Shapes  []json.RawMessage `json:"shapes"`
+}
+
+
+func (ui *MainUI) clipboard() fyne.Clipboard {
+	if app := fyne.CurrentApp(); app != nil {
+		return app.Clipboard()
+	}
+	return nil
+}
+
+
+func (ui *MainUI) copySelection() bool {
+	selected := ui.State.SelectionInStackOrder()
+	if len(selected) == 0 {
+		ui.StatusLabel.SetText("Nothing selected to copy.")
+		return false
+	}
+	clipboard := ui.clipboard()
+	if clipboard == nil {
+		return false
+	}
+
+	shapesData, err := models.EncodeShapes(selected)
+	if err == nil {
+		var data []byte
+		data, err = json.Marshal(clipboardContent{Format: clipboardFormat, Version: models.DocumentVersion, Shapes: shapesData})
+		if err == nil {
+			clipboard.SetContent(string(data))
+		}
+	}
+	if err != nil {
+		ui.StatusLabel.SetText(fmt.Sprintf("Copy failed: %v", err))
+		return false
+	}
+
+	ui.pasteCount = 0
+	ui.StatusLabel.SetText(fmt.Sprintf("%d shapes copied", len(selected)))
+	return true
+}
+
+
+func (ui *MainUI) cutSelection() {
+	if ui.copySelection() {
+		count := len(ui.State.SelectedShapes())
+		ui.Editor.RemoveShapes(ui.State.SelectedShapes())
+		ui.Editor.ClearSelection()
+		ui.StatusLabel.SetText(fmt.Sprintf("%d shapes cut", count))
+	}
+}
+
+
+func (ui *MainUI) paste(at *models.Point) {
+	clipboard := ui.clipboard()
+	if clipboard == nil {
+		return
+	}
+
+	var data clipboardContent
+	if err := json.Unmarshal([]byte(clipboard.Content()), &data); err != nil || data.Format != clipboardFormat {
+		ui.StatusLabel.SetText("Clipboard does not contain shapes.")
+		return
+	}
+	if data.Version > models.DocumentVersion {
+		ui.StatusLabel.SetText(fmt.Sprintf("Clipboard shapes are version %d, newer than this version of the app supports (%d).", data.Version, models.DocumentVersion))
+		return
+	}
+	report := &models.LoadReport{}
+	shapes := models.RepairShapes(data.Shapes, 0, report)
+	if len(shapes) == 0 {
+		if report.Empty() {
+			ui.StatusLabel.SetText("Clipboard does not contain shapes.")
+		} else {
+			ui.StatusLabel.SetText(fmt.Sprintf("Nothing pasted: %s", report.Summary()))
+		}
+		return
+	}
+
+	var dx, dy float64
+	if at != nil {
+		selection := models.DrawingState{Shapes: shapes, Selection: shapes}
+		minP, maxP, _ := selection.SelectionBounds()
+		dx = at.X - (minP.X+maxP.X)/2
+		dy = at.Y - (minP.Y+maxP.Y)/2
+	} else {
+		ui.pasteCount++
+		dx = float64(pasteOffset * ui.pasteCount)
+		dy = dx
+	}
+	for _, shape := range shapes {
+		shape.Move(dx, dy)
+	}
+
+	first := ui.State.ActiveInsertIndex()
+	ui.Editor.AddShapes(shapes, fmt.Sprintf("Paste %d shapes", len(shapes)))
+	ui.Editor.Select(ui.State.Shapes[first : first+len(shapes)]...)
+	if report.Empty() {
+		ui.StatusLabel.SetText(fmt.Sprintf("%d shapes pasted", len(shapes)))
+	} else {
+		ui.StatusLabel.SetText(fmt.Sprintf("%d shapes pasted; %s", len(shapes), report.Summary()))
+	}
+}
+
+
+func (ui *MainUI) pasteAtCursor() {
+	if ui.CursorInCanvas {
+		cursor := ui.CursorPoint
+		ui.paste(&cursor)
+		return
+	}
+	ui.paste(nil)
+}
+
+
+func (ui *MainUI) copySelectionAsPNG() {
+	selected := ui.State.SelectionInStackOrder()
+	if len(selected) == 0 {
+		ui.StatusLabel.SetText("Nothing selected to copy.")
+		return
+	}
+	if err := clipboard.Init(); err != nil {
+		ui.StatusLabel.SetText(fmt.Sprintf("Image clipboard unavailable: %v", err))
+		return
+	}
+
+	img := renderShapesImage(selected, ui.State.AntiAliasing, ui.State.Raster)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		ui.StatusLabel.SetText(fmt.Sprintf("PNG encoding failed: %v", err))
+		return
+	}
+	if clipboard.Write(clipboard.FmtImage, buf.Bytes()) == nil {
+		ui.StatusLabel.SetText("Copy as PNG failed: the clipboard rejected the image.")
+		return
+	}
+
+	ui.StatusLabel.SetText(fmt.Sprintf("Selection copied as %dx%d PNG", img.Bounds().Dx(), img.Bounds().Dy()))
+}
+
+
+func renderShapesImage(shapes []models.Shape, antiAliasing bool, defaults models.RasterAlgorithms) *image.RGBA {
+	selection := models.DrawingState{Shapes: shapes, Selection: shapes}
+	minP, maxP, _ := selection.SelectionBounds()
+
+	w := int(math.Ceil(maxP.X-minP.X)) + 2*pngExportPadding + 1
+	h := int(math.Ceil(maxP.Y-minP.Y)) + 2*pngExportPadding + 1
+	canvas := models.NewLayerCanvas(w, h)
+	for _, shape := range shapes {
+		clone := shape.Clone()
+		clone.Move(pngExportPadding-math.Floor(minP.X), pngExportPadding-math.Floor(minP.Y))
+		models.DrawShape(clone, canvas, antiAliasing, defaults)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range canvas {
+		for x, c := range canvas[y] {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+
+func (ui *MainUI) registerClipboardShortcuts() {
+	shortcuts := map[*desktop.CustomShortcut]func(){
+		{KeyName: fyne.KeyC, Modifier: fyne.KeyModifierShortcutDefault}:                         func() { ui.copySelection() },
+		{KeyName: fyne.KeyX, Modifier: fyne.KeyModifierShortcutDefault}:                         ui.cutSelection,
+		{KeyName: fyne.KeyV, Modifier: fyne.KeyModifierShortcutDefault}:                         ui.pasteAtCursor,
+		{KeyName: fyne.KeyV, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}: func() { ui.paste(nil) },
+		{KeyName: fyne.KeyD, Modifier: fyne.KeyModifierShortcutDefault}:                         ui.cloneSelection,
+		{KeyName: fyne.KeyC, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}: ui.copySelectionAsPNG,
+	}
+	for shortcut, action := range shortcuts {
+		action := action
+		ui.Window.Canvas().AddShortcut(shortcut, func(fyne.Shortcut) { action() })
+	}
+}
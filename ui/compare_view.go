@@ -0,0 +1,186 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"paint-drawer-pro/algorithms"
+	"paint-drawer-pro/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+
+const (
+	comparePadding = 4
+	compareMaxSize = 256
+	comparePreview = 320
+)
+
+
+var compareCategories = []string{"Line", "Circle", "Fill"}
+
+
+func compareAlgorithmNames(category string) []string {
+	switch category {
+	case "Circle":
+		return algorithms.CircleAlgorithmNames()
+	case "Fill":
+		return algorithms.FillAlgorithmNames()
+	}
+	return algorithms.LineAlgorithmNames()
+}
+
+
+func defaultCompareCategory(shape models.Shape) string {
+	switch shape.(type) {
+	case *models.Circle, *models.Pill:
+		return "Circle"
+	}
+	if isFilledShape(shape) {
+		return "Fill"
+	}
+	return "Line"
+}
+
+
+func withAlgorithm(raster models.RasterAlgorithms, category, name string) models.RasterAlgorithms {
+	switch category {
+	case "Circle":
+		raster.Circle = name
+	case "Fill":
+		raster.Fill = name
+	default:
+		raster.Line = name
+	}
+	return raster
+}
+
+
+func (ui *MainUI) showCompareDialog() {
+	shape := ui.State.SelectedShape
+	if shape == nil {
+		dialog.ShowInformation("Compare Algorithms", "Please select a shape to compare first.", ui.Window)
+		return
+	}
+
+	category := defaultCompareCategory(shape)
+	names := compareAlgorithmNames(category)
+	nameA, nameB := names[0], names[0]
+	if len(names) > 1 {
+		nameB = names[1]
+	}
+
+	imageA := canvas.NewImageFromImage(image.NewRGBA(image.Rect(0, 0, 1, 1)))
+	imageB := canvas.NewImageFromImage(image.NewRGBA(image.Rect(0, 0, 1, 1)))
+	for _, img := range []*canvas.Image{imageA, imageB} {
+		img.FillMode = canvas.ImageFillContain
+		img.ScaleMode = canvas.ImageScalePixels
+		img.SetMinSize(fyne.NewSize(comparePreview, comparePreview))
+	}
+	diffLabel := widget.NewLabel("")
+
+	update := func() {
+		a, b := renderComparison(shape, category, nameA, nameB, ui.State.AntiAliasing, ui.State.Raster)
+		imageA.Image = a
+		imageB.Image = b
+		imageA.Refresh()
+		imageB.Refresh()
+
+		differing := 0
+		for i := 0; i < len(a.Pix); i += 4 {
+			if a.Pix[i] != b.Pix[i] || a.Pix[i+1] != b.Pix[i+1] || a.Pix[i+2] != b.Pix[i+2] || a.Pix[i+3] != b.Pix[i+3] {
+				differing++
+			}
+		}
+		diffLabel.SetText(fmt.Sprintf("%d of %d pixels differ", differing, len(a.Pix)/4))
+	}
+
+	selectA := widget.NewSelect(names, func(selected string) {
+		nameA = selected
+		update()
+	})
+	selectB := widget.NewSelect(names, func(selected string) {
+		nameB = selected
+		update()
+	})
+	categorySelect := widget.NewSelect(compareCategories, func(selected string) {
+		category = selected
+		names = compareAlgorithmNames(category)
+		selectA.Options = names
+		selectB.Options = names
+		nameA, nameB = names[0], names[0]
+		if len(names) > 1 {
+			nameB = names[1]
+		}
+		selectA.SetSelected(nameA)
+		selectB.SetSelected(nameB)
+		update()
+	})
+
+	categorySelect.SetSelected(category)
+	selectA.SetSelected(nameA)
+	selectB.SetSelected(nameB)
+
+	content := container.NewBorder(
+		container.NewBorder(nil, nil, widget.NewLabel("Compare:"), nil, categorySelect),
+		diffLabel, nil, nil,
+		container.NewGridWithColumns(2,
+			container.NewBorder(selectA, nil, nil, nil, imageA),
+			container.NewBorder(selectB, nil, nil, nil, imageB),
+		),
+	)
+	dialog.ShowCustom("Compare Algorithms", "Close", content, ui.Window)
+}
+
+
+func renderComparison(shape models.Shape, category, nameA, nameB string, antiAliasing bool, defaults models.RasterAlgorithms) (*image.RGBA, *image.RGBA) {
+	minP, maxP := models.ShapeBounds(shape)
+	scale := 1.0
+	if extent := math.Max(maxP.X-minP.X, maxP.Y-minP.Y); extent > compareMaxSize {
+		scale = compareMaxSize / extent
+	}
+	w := int(math.Ceil((maxP.X-minP.X)*scale)) + 2*comparePadding + 1
+	h := int(math.Ceil((maxP.Y-minP.Y)*scale)) + 2*comparePadding + 1
+
+	render := func(name string) *image.RGBA {
+		clone := shape.Clone().Transform(models.TranslateMatrix(-minP.X, -minP.Y))
+		if scale != 1 {
+			clone = clone.Transform(models.ScaleMatrix(scale, scale))
+		}
+		clone.Move(comparePadding, comparePadding)
+		if fillable, ok := clone.(interface{ SetFillColor(color.Color) }); ok && category == "Fill" && !isFilledShape(clone) {
+			fillable.SetFillColor(color.RGBA{160, 200, 255, 255})
+		}
+		models.SetShapeRaster(clone, withAlgorithm(defaults, category, name))
+
+		img := image.NewRGBA(image.Rect(0, 0, w, h))
+		for i := range img.Pix {
+			img.Pix[i] = 0xff
+		}
+		layer := models.NewLayerCanvas(w, h)
+		models.DrawShape(clone, layer, antiAliasing, defaults)
+		models.CompositeLayer(img, layer, models.NewLayer(""))
+		return img
+	}
+
+	return render(nameA), render(nameB)
+}
+
+
+func isFilledShape(shape models.Shape) bool {
+	switch s := shape.(type) {
+	case *models.Polygon:
+		return s.IsFilled
+	case *models.Rectangle:
+		return s.IsFilled
+	case *models.Path:
+		return s.IsFilled
+	}
+	return false
+}
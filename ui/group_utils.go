@@ -0,0 +1,69 @@
+package ui
+
+import (
+	"fmt"
+	"paint-drawer-pro/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+
+func (ui *MainUI) groupSelection() {
+	selected := ui.State.SelectionInStackOrder()
+	if len(selected) < 2 {
+		dialog.ShowInformation("Group", "Please select at least two shapes to group.", ui.Window)
+		return
+	}
+
+	if _, ok := ui.State.SharedLayer(selected); !ok {
+		ui.StatusLabel.SetText("Cannot group shapes from different layers.")
+		return
+	}
+
+	batch, index := models.NewGroupCommand(ui.State, selected)
+	ui.Editor.Execute(batch)
+	ui.Editor.Select(ui.State.Shapes[index])
+	ui.StatusLabel.SetText(fmt.Sprintf("Grouped %d shapes", len(selected)))
+}
+
+
+func (ui *MainUI) ungroupSelection() {
+	var groups []*models.Group
+	for _, shape := range ui.State.SelectionInStackOrder() {
+		if group, ok := shape.(*models.Group); ok {
+			groups = append(groups, group)
+		}
+	}
+	if len(groups) == 0 {
+		dialog.ShowInformation("Ungroup", "Please select a group to ungroup.", ui.Window)
+		return
+	}
+
+	indices := make([]int, len(groups))
+	for i, group := range groups {
+		indices[i] = models.IndexOfShape(ui.State.Shapes, group)
+	}
+
+	ui.Editor.Execute(models.NewUngroupCommand(ui.State, groups))
+
+	var children []models.Shape
+	offset := 0
+	for i, group := range groups {
+		start := indices[i] + offset
+		children = append(children, ui.State.Shapes[start:start+len(group.Children)]...)
+		offset += len(group.Children) - 1
+	}
+	ui.Editor.Select(children...)
+	ui.StatusLabel.SetText(fmt.Sprintf("Ungrouped into %d shapes", len(children)))
+}
+
+
+func (ui *MainUI) registerGroupShortcuts() {
+	group := &desktop.CustomShortcut{KeyName: fyne.KeyG, Modifier: fyne.KeyModifierShortcutDefault}
+	ungroup := &desktop.CustomShortcut{KeyName: fyne.KeyG, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}
+
+	ui.Window.Canvas().AddShortcut(group, func(fyne.Shortcut) { ui.groupSelection() })
+	ui.Window.Canvas().AddShortcut(ungroup, func(fyne.Shortcut) { ui.ungroupSelection() })
+}
@@ -0,0 +1,67 @@
+package ui
+
+import (
+	"paint-drawer-pro/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+
+func (ui *MainUI) Undo() {
+	cmd, ok := ui.Editor.Undo()
+	if !ok {
+		ui.StatusLabel.SetText("Nothing to undo")
+		return
+	}
+	ui.afterHistoryStep()
+	ui.StatusLabel.SetText("Undo: " + cmd.Name())
+}
+
+
+func (ui *MainUI) Redo() {
+	cmd, ok := ui.Editor.Redo()
+	if !ok {
+		ui.StatusLabel.SetText("Nothing to redo")
+		return
+	}
+	ui.afterHistoryStep()
+	ui.StatusLabel.SetText("Redo: " + cmd.Name())
+}
+
+
+func (ui *MainUI) afterHistoryStep() {
+	_, selecting := ui.Tool().(*selectTool)
+	if _, isPill := ui.State.SelectedShape.(*models.Pill); !isPill && selecting {
+		ui.PillLengthContainer.Hide()
+	}
+}
+
+
+func (ui *MainUI) registerHistoryShortcuts() {
+	undo := &desktop.CustomShortcut{KeyName: fyne.KeyZ, Modifier: fyne.KeyModifierShortcutDefault}
+	redo := &desktop.CustomShortcut{KeyName: fyne.KeyZ, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}
+	redoAlt := &desktop.CustomShortcut{KeyName: fyne.KeyY, Modifier: fyne.KeyModifierShortcutDefault}
+
+	ui.Window.Canvas().AddShortcut(undo, func(fyne.Shortcut) { ui.Undo() })
+	ui.Window.Canvas().AddShortcut(redo, func(fyne.Shortcut) { ui.Redo() })
+	ui.Window.Canvas().AddShortcut(redoAlt, func(fyne.Shortcut) { ui.Redo() })
+}
+
+
+type editSession struct {
+	indices   []int
+	snapshots []models.Shape
+}
+
+
+func (s *editSession) begin(ui *MainUI, shapes ...models.Shape) {
+	s.indices, s.snapshots = ui.Editor.SnapshotShapes(shapes)
+}
+
+
+func (s *editSession) end(ui *MainUI, label string) {
+	ui.Editor.RecordEdits(s.indices, s.snapshots, label)
+	s.indices = nil
+	s.snapshots = nil
+}
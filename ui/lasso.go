@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"paint-drawer-pro/models"
+)
+
+
+const lassoMinSpacing = 3.0
+
+
+func (t *lassoTool) beginLasso(h *MouseHandler, p models.Point, additive bool) {
+	t.active = true
+	t.additive = additive
+	h.UI.LassoPoints = []models.Point{p}
+}
+
+
+func (t *lassoTool) extendLasso(h *MouseHandler, p models.Point) {
+	last := h.UI.LassoPoints[len(h.UI.LassoPoints)-1]
+	if math.Hypot(p.X-last.X, p.Y-last.Y) < lassoMinSpacing {
+		return
+	}
+	h.UI.LassoPoints = append(h.UI.LassoPoints, p)
+	h.UI.Canvas.Refresh()
+}
+
+
+func (t *lassoTool) endLasso(h *MouseHandler) {
+	t.active = false
+	loop := h.UI.LassoPoints
+	h.UI.LassoPoints = nil
+
+	hits := models.ShapesInLasso(h.UI.State.SelectableShapes(), loop, h.UI.LassoMode)
+	if t.additive {
+		selection := h.UI.State.SelectedShapes()
+		for _, shape := range hits {
+			if models.IndexOfShape(selection, shape) < 0 {
+				selection = append(selection, shape)
+			}
+		}
+		hits = selection
+	}
+	h.UI.Editor.Select(hits...)
+
+	if len(hits) == 0 {
+		h.UI.StatusLabel.SetText("No shape inside the lasso.")
+	} else {
+		h.UI.StatusLabel.SetText(fmt.Sprintf("%d shapes selected. Press Delete to remove, or switch to Select to move them.", len(hits)))
+	}
+}
+
+
+func drawLasso(canvas [][]color.Color, points []models.Point, c color.Color) {
+	if len(points) < 2 {
+		return
+	}
+	for i := 1; i < len(points); i++ {
+		drawDashedLine(canvas, points[i-1], points[i], c)
+	}
+	drawDashedLine(canvas, points[len(points)-1], points[0], c)
+}
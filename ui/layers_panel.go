@@ -0,0 +1,196 @@
+package ui
+
+import (
+	"fmt"
+	"paint-drawer-pro/algorithms"
+	"paint-drawer-pro/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+
+func (ui *MainUI) buildLayersPanel() *fyne.Container {
+	ui.LayersList = container.NewVBox()
+
+	ui.LayerOpacitySlider = widget.NewSlider(0, 100)
+	ui.LayerOpacitySlider.Step = 1
+	ui.LayerOpacitySlider.OnChanged = func(value float64) {
+		if ui.syncingLayerControls {
+			return
+		}
+		ui.setLayerProperty(ui.State.ActiveLayer, "Change layer opacity", "layer-opacity", func(layer *models.Layer) {
+			layer.Opacity = value / 100
+		})
+	}
+
+	blendNames := make([]string, len(algorithms.BlendModes))
+	for i, mode := range algorithms.BlendModes {
+		blendNames[i] = string(mode)
+	}
+	ui.LayerBlendSelect = widget.NewSelect(blendNames, func(selected string) {
+		if ui.syncingLayerControls {
+			return
+		}
+		ui.setLayerProperty(ui.State.ActiveLayer, "Change blend mode", "", func(layer *models.Layer) {
+			layer.BlendMode = algorithms.BlendMode(selected)
+		})
+	})
+
+	addBtn := widget.NewButton("Add", func() {
+		ui.addLayer()
+	})
+	deleteBtn := widget.NewButton("Delete", func() {
+		ui.deleteLayer()
+	})
+	upBtn := widget.NewButton("Up", func() {
+		ui.moveLayer(1)
+	})
+	downBtn := widget.NewButton("Down", func() {
+		ui.moveLayer(-1)
+	})
+	renameBtn := widget.NewButton("Rename", func() {
+		ui.showRenameLayerDialog()
+	})
+
+	controls := container.NewVBox(
+		widget.NewSeparator(),
+		container.NewGridWithColumns(2, addBtn, deleteBtn, upBtn, downBtn),
+		renameBtn,
+		widget.NewLabel("Opacity:"),
+		ui.LayerOpacitySlider,
+		widget.NewLabel("Blend Mode:"),
+		ui.LayerBlendSelect,
+	)
+
+	ui.refreshLayersPanel()
+
+	return container.NewBorder(
+		widget.NewLabel("Layers:"), controls, nil, nil,
+		container.NewVScroll(ui.LayersList),
+	)
+}
+
+
+func (ui *MainUI) refreshLayersPanel() {
+	if ui.LayersList == nil {
+		return
+	}
+
+	ui.LayersList.Objects = nil
+	for i := len(ui.State.Layers) - 1; i >= 0; i-- {
+		index := i
+		layer := ui.State.Layers[i]
+
+		nameBtn := widget.NewButton(layer.Name, func() {
+			ui.Editor.SetActiveLayer(index)
+			ui.StatusLabel.SetText(fmt.Sprintf("Active layer: %s", ui.State.Layers[index].Name))
+			ui.refreshLayersPanel()
+		})
+		if index == ui.State.ActiveLayer {
+			nameBtn.Importance = widget.HighImportance
+		}
+
+		visibleCheck := widget.NewCheck("Show", nil)
+		visibleCheck.SetChecked(layer.Visible)
+		visibleCheck.OnChanged = func(checked bool) {
+			ui.setLayerProperty(index, "Toggle layer visibility", "", func(layer *models.Layer) {
+				layer.Visible = checked
+			})
+		}
+
+		lockCheck := widget.NewCheck("Lock", nil)
+		lockCheck.SetChecked(layer.Locked)
+		lockCheck.OnChanged = func(checked bool) {
+			ui.setLayerProperty(index, "Toggle layer lock", "", func(layer *models.Layer) {
+				layer.Locked = checked
+			})
+		}
+
+		ui.LayersList.Add(container.NewBorder(nil, nil, nil, container.NewHBox(visibleCheck, lockCheck), nameBtn))
+	}
+	ui.LayersList.Refresh()
+
+	if ui.State.ActiveLayer >= 0 && ui.State.ActiveLayer < len(ui.State.Layers) {
+		active := ui.State.Layers[ui.State.ActiveLayer]
+		ui.syncingLayerControls = true
+		ui.LayerOpacitySlider.SetValue(active.Opacity * 100)
+		ui.LayerBlendSelect.SetSelected(string(active.BlendMode))
+		ui.syncingLayerControls = false
+	}
+}
+
+
+func (ui *MainUI) setLayerProperty(index int, label, key string, edit func(layer *models.Layer)) {
+	if index < 0 || index >= len(ui.State.Layers) {
+		return
+	}
+
+	before := *ui.State.Layers[index]
+	after := before
+	edit(&after)
+	if after == before {
+		return
+	}
+
+	cmd := models.NewLayerPropertiesCommand(index, before, after, label)
+	cmd.Key = key
+	if !after.Visible || after.Locked {
+		for _, shape := range ui.State.LayerShapes(index) {
+			ui.Editor.Deselect(shape)
+		}
+	}
+	ui.Editor.Execute(cmd)
+}
+
+
+func (ui *MainUI) addLayer() {
+	layer := models.NewLayer(fmt.Sprintf("Layer %d", len(ui.State.Layers)+1))
+	ui.Editor.Execute(models.NewAddLayerCommand(ui.State, layer, ui.State.ActiveLayer+1))
+	ui.StatusLabel.SetText(fmt.Sprintf("%s added", layer.Name))
+}
+
+
+func (ui *MainUI) deleteLayer() {
+	if len(ui.State.Layers) <= 1 {
+		dialog.ShowInformation("Layers", "A drawing needs at least one layer.", ui.Window)
+		return
+	}
+
+	name := ui.State.Layers[ui.State.ActiveLayer].Name
+	ui.Editor.Execute(models.NewDeleteLayerCommand(ui.State, ui.State.ActiveLayer))
+	ui.StatusLabel.SetText(fmt.Sprintf("%s deleted", name))
+}
+
+
+func (ui *MainUI) moveLayer(delta int) {
+	index := ui.State.ActiveLayer
+	target := index + delta
+	if target < 0 || target >= len(ui.State.Layers) {
+		return
+	}
+	ui.Editor.Execute(models.NewMoveLayerCommand(ui.State, index, target))
+}
+
+
+func (ui *MainUI) showRenameLayerDialog() {
+	index := ui.State.ActiveLayer
+	if index < 0 || index >= len(ui.State.Layers) {
+		return
+	}
+
+	entry := widget.NewEntry()
+	entry.SetText(ui.State.Layers[index].Name)
+	items := []*widget.FormItem{widget.NewFormItem("Name", entry)}
+
+	dialog.ShowForm("Rename Layer", "Rename", "Cancel", items, func(confirmed bool) {
+		if !confirmed || entry.Text == "" {
+			return
+		}
+		ui.setLayerProperty(index, "Rename layer", "", func(layer *models.Layer) {
+			layer.Name = entry.Text
+		})
+	}, ui.Window)
+}
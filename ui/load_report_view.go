@@ -0,0 +1,78 @@
+package ui
+
+import (
+	"fmt"
+	"paint-drawer-pro/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+
+var loadReportColumns = []string{"Layer", "Shape", "Type", "Field", "Problem", "Action"}
+
+
+var loadReportWidths = []float32{50, 50, 80, 120, 320, 100}
+
+
+func loadReportCell(d models.Diagnostic, column int) string {
+	switch column {
+	case 0:
+		if d.Layer < 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%d", d.Layer+1)
+	case 1:
+		if d.Shape < 0 {
+			return "-"
+		}
+		return fmt.Sprintf("%d", d.Shape+1)
+	case 2:
+		return d.Type
+	case 3:
+		return d.Field
+	case 4:
+		return d.Problem
+	default:
+		return d.Action()
+	}
+}
+
+
+func (ui *MainUI) showLoadReport(report *models.LoadReport) {
+	table := widget.NewTable(
+		func() (int, int) {
+			return len(report.Diagnostics), len(loadReportColumns)
+		},
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.TableCellID, cell fyne.CanvasObject) {
+			cell.(*widget.Label).SetText(loadReportCell(report.Diagnostics[id.Row], id.Col))
+		},
+	)
+	table.ShowHeaderRow = true
+	table.CreateHeader = func() fyne.CanvasObject {
+		return widget.NewLabel("")
+	}
+	table.UpdateHeader = func(id widget.TableCellID, cell fyne.CanvasObject) {
+		if id.Col >= 0 {
+			cell.(*widget.Label).SetText(loadReportColumns[id.Col])
+		}
+	}
+	for i, width := range loadReportWidths {
+		table.SetColumnWidth(i, width)
+	}
+
+	summary := widget.NewLabel(fmt.Sprintf("The drawing was loaded with %s. Saving it will write the repaired shapes.", report.Summary()))
+	summary.Wrapping = fyne.TextWrapWord
+	content := container.NewBorder(summary, nil, nil, nil, table)
+
+	d := dialog.NewCustom("Load Problems", "Close", content, ui.Window)
+	d.Resize(fyne.NewSize(760, 420))
+	d.Show()
+}
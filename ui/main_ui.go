@@ -6,6 +6,7 @@ import (
 	"image/color"
 	"math"
 	"paint-drawer-pro/algorithms"
+	"paint-drawer-pro/editor"
 	"paint-drawer-pro/models"
 
 	"fyne.io/fyne/v2"
@@ -26,24 +27,59 @@ type MainUI struct {
 	PillLengthSlider *widget.Slider
 	PillLengthLabel  *widget.Label
 	PillLengthContainer *fyne.Container
-	State           models.DrawingState
+	Editor          *editor.Editor
+	Tools           map[string]Tool
+	State           *models.DrawingState
+	MarqueeActive   bool
+	MarqueeStart    models.Point
+	MarqueeEnd      models.Point
+	MarqueeMode     models.MarqueeMode
+	LassoPoints     []models.Point
+	LassoMode       models.LassoMode
+	LayersPanel     *fyne.Container
+	SidePanel       *container.AppTabs
+	Visualizer      *AlgorithmVisualizer
+	LayersList      *fyne.Container
+	LayerOpacitySlider *widget.Slider
+	LayerBlendSelect   *widget.Select
+	CursorInCanvas  bool
+	CursorPoint     models.Point
+	Snap            models.SnapSettings
+	SnapIndicator   models.SnapResult
+	ShiftHeld       bool
+	SpaceHeld       bool
+	Viewport        models.Viewport
+	ZoomLabel       *widget.Label
+	PixelInspect    bool
+	PixelGrid       bool
+	AlignReference  models.AlignReference
+	syncingLayerControls bool
+	pasteCount      int
+	inspectImage    *image.RGBA
+	inspectSources  [][]models.PixelSource
+	inspectOrigin   image.Point
+	inspectedPixel  image.Point
+	activeTool      Tool
 }
 
 func NewMainUI(window fyne.Window) *MainUI {
+	core := editor.New()
 	ui := &MainUI{
-		Window: window,
-		State: models.DrawingState{
-			Shapes:         []models.Shape{},
-			CurrentAction:  "line",
-			AntiAliasing:   true,
-			PenType:        "brush",
-			BrushThickness: 3,
-			CurrentColor:   color.RGBA{0, 0, 0, 255}, 
-			FillEnabled:    false,
-			FillColor:      color.RGBA{255, 255, 255, 255},
-			UseImageFill:   false,
-		},
+		Window:   window,
+		Editor:   core,
+		Tools:    newTools(),
+		State:    core.State,
+		Snap:     models.DefaultSnapSettings(),
+		Viewport: models.NewViewport(),
 	}
+	core.Subscribe(ui.onEditorChange)
+	ui.registerHistoryShortcuts()
+	ui.registerSelectionShortcuts()
+	ui.registerGroupShortcuts()
+	ui.registerZOrderShortcuts()
+	ui.registerClipboardShortcuts()
+	ui.registerKeyStateTracking()
+	ui.registerViewportShortcuts()
 
 	
 	ui.PillLengthLabel = widget.NewLabel("Pill Length:")
@@ -56,7 +92,11 @@ func NewMainUI(window fyne.Window) *MainUI {
 		pillLengthValue.SetText(fmt.Sprintf("%d", length))
 		ui.StatusLabel.SetText(fmt.Sprintf("Pill length set to %d", length))
 		
-		ui.updatePillLength(length)
+		if sizer, ok := ui.Tool().(PillLengthTool); ok {
+			if pill := sizer.PillTarget(ui); pill != nil {
+				ui.Editor.SetPillLength(pill, length)
+			}
+		}
 	}
 	ui.PillLengthContainer = container.NewBorder(
 		nil, nil, ui.PillLengthLabel, pillLengthValue, ui.PillLengthSlider,
@@ -74,59 +114,80 @@ func NewMainUI(window fyne.Window) *MainUI {
 
 	
 	ui.StatusLabel = widget.NewLabel("Ready")
-	ui.CurrentToolText = widget.NewLabel("Current tool: Line")
+	ui.CurrentToolText = widget.NewLabel("Current tool: " + ui.Tool().Label())
+	ui.activeTool = ui.Tool()
 
 	
-	lineBtn := widget.NewButton("Line", func() {
-		ui.State.CurrentAction = "line"
-		ui.CurrentToolText.SetText("Current tool: Line")
-		ui.StatusLabel.SetText("Line tool selected")
-		ui.PillLengthContainer.Hide() 
+	lineBtn := ui.toolButton(ToolLine)
+	circleBtn := ui.toolButton(ToolCircle)
+	polygonBtn := ui.toolButton(ToolPolygon)
+	rectangleBtn := ui.toolButton(ToolRectangle)
+	pillBtn := ui.toolButton(ToolPill)
+	selectBtn := ui.toolButton(ToolSelect)
+	vertexEditBtn := ui.toolButton(ToolVertexEdit)
+
+	selectAllBtn := widget.NewButton("Select All", func() {
+		ui.selectAll()
 	})
 
-	circleBtn := widget.NewButton("Circle", func() {
-		ui.State.CurrentAction = "circle"
-		ui.CurrentToolText.SetText("Current tool: Circle")
-		ui.StatusLabel.SetText("Circle tool selected")
-		ui.PillLengthContainer.Hide() 
+	cloneBtn := widget.NewButton("Clone Selection", func() {
+		ui.cloneSelection()
 	})
 
-	polygonBtn := widget.NewButton("Polygon", func() {
-		ui.State.CurrentAction = "polygon"
-		ui.CurrentToolText.SetText("Current tool: Polygon")
-		ui.StatusLabel.SetText("Polygon tool selected")
-		ui.PillLengthContainer.Hide() 
+	copyBtn := widget.NewButton("Copy", func() {
+		ui.copySelection()
 	})
 
-	rectangleBtn := widget.NewButton("Rectangle", func() {
-		ui.State.CurrentAction = "rectangle"
-		ui.CurrentToolText.SetText("Current tool: Rectangle")
-		ui.StatusLabel.SetText("Rectangle tool selected")
-		ui.PillLengthContainer.Hide() 
+	cutBtn := widget.NewButton("Cut", func() {
+		ui.cutSelection()
 	})
 
-	pillBtn := widget.NewButton("Pill", func() {
-		ui.State.CurrentAction = "pill"
-		ui.CurrentToolText.SetText("Current tool: Pill")
-		ui.StatusLabel.SetText("Pill tool selected: Click to place first end, then set radius, then place second end")
-		
-		ui.PillLengthContainer.Show()
-		ui.PillLengthSlider.SetValue(100)
+	pasteBtn := widget.NewButton("Paste", func() {
+		ui.paste(nil)
+	})
+
+	copyPNGBtn := widget.NewButton("Copy as PNG", func() {
+		ui.copySelectionAsPNG()
 	})
 
-	selectBtn := widget.NewButton("Select", func() {
-		ui.State.CurrentAction = "select"
-		ui.CurrentToolText.SetText("Current tool: Select")
-		ui.StatusLabel.SetText("Select tool active")
+	marqueeCheck := widget.NewCheck("Marquee selects touching shapes", func(checked bool) {
+		if checked {
+			ui.MarqueeMode = models.MarqueeTouching
+		} else {
+			ui.MarqueeMode = models.MarqueeInside
+		}
+	})
 
-		ui.PillLengthContainer.Hide()
+	groupBtn := widget.NewButton("Group", func() {
+		ui.groupSelection()
+	})
+
+	ungroupBtn := widget.NewButton("Ungroup", func() {
+		ui.ungroupSelection()
+	})
+
+	lassoBtn := ui.toolButton(ToolLasso)
+
+	lassoCheck := widget.NewCheck("Lasso tests bounding boxes", func(checked bool) {
+		if checked {
+			ui.LassoMode = models.LassoBounds
+		} else {
+			ui.LassoMode = models.LassoGeometry
+		}
 	})
 
 	clearBtn := widget.NewButton("Clear All", func() {
-		ui.State.Shapes = []models.Shape{}
-		ui.Canvas.Refresh()
+		ui.Editor.Clear()
 		ui.StatusLabel.SetText("Canvas cleared")
 	})
+
+	undoBtn := widget.NewButton("Undo", func() {
+		ui.Undo()
+	})
+
+	redoBtn := widget.NewButton("Redo", func() {
+		ui.Redo()
+	})
 	
 	saveBtn := widget.NewButton("Save", func() {
 		fd := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
@@ -142,7 +203,7 @@ func NewMainUI(window fyne.Window) *MainUI {
 				
 			filePath := writer.URI().Path()
 				
-			err = ui.SaveShapesToFile(filePath)
+			err = ui.Editor.Save(filePath)
 			if err != nil {
 				dialog.ShowError(err, ui.Window)
 				return
@@ -168,10 +229,15 @@ func NewMainUI(window fyne.Window) *MainUI {
 				
 			filePath := reader.URI().Path()
 				
-			err = ui.LoadShapesFromFile(filePath)
+			report, err := ui.Editor.Load(filePath)
 			if err != nil {
 				dialog.ShowError(err, ui.Window)
 				return
+			}
+			if !report.Empty() {
+				ui.StatusLabel.SetText(fmt.Sprintf("Drawing loaded with %s", report.Summary()))
+				ui.showLoadReport(report)
+				return
 			}
 				ui.StatusLabel.SetText("Drawing loaded from file")
 		}, ui.Window)
@@ -181,43 +247,37 @@ func NewMainUI(window fyne.Window) *MainUI {
 	
 	
 	clipBtn := widget.NewButton("Clip Polygon", func() {
-		if ui.State.SelectedShape == nil {
-			dialog.ShowInformation("Clipping", "Please select a polygon to clip first.", ui.Window)
-			return
-		}
-		selectedPoly, isPolygon := ui.State.SelectedShape.(*models.Polygon)
-		if !isPolygon {
-			dialog.ShowInformation("Clipping", "Only polygons can be clipped. Please select a polygon.", ui.Window)
+		if err := editor.CheckClipper(ui.State.SelectedShape); err != nil {
+			dialog.ShowInformation("Clipping", err.Error(), ui.Window)
 			return
 		}
-		
-		vertices := selectedPoly.GetVertices()
-		algVertices := make([]algorithms.Point, len(vertices))
-		for i, v := range vertices {
-			algVertices[i] = algorithms.Point{X: v.X, Y: v.Y}
-		}
-		
-		simplified := algorithms.SimplifyPolygon(algVertices, 2.0)
-		
-		originalCount := len(vertices)
-		simplifiedCount := len(simplified)
-		
-		if !algorithms.IsPolygonConvex(simplified) {
-			message := fmt.Sprintf("Only convex polygons can be used for clipping. Selected polygon has %d vertices (simplified from %d).", 
-				simplifiedCount, originalCount)
-			dialog.ShowInformation("Clipping", message, ui.Window)
+		ui.Editor.SetTool(ToolClipping)
+	})
+
+	offsetBtn := widget.NewButton("Offset Polygon", func() {
+		ui.showOffsetDialog()
+	})
+
+	outlineBtn := widget.NewButton("Stroke to Outline", func() {
+		ui.convertSelectedToOutline()
+	})
+
+	transformBtn := widget.NewButton("Transform...", func() {
+		ui.showTransformDialog()
+	})
+
+	toPathBtn := widget.NewButton("Convert to Path", func() {
+		if ui.State.SelectedShape == nil {
+			dialog.ShowInformation("Path", "Please select a shape to convert first.", ui.Window)
 			return
 		}
-		ui.State.CurrentAction = "clipping"
-		ui.CurrentToolText.SetText("Current tool: Clipping")
-		ui.StatusLabel.SetText("Clipping mode active. Select a polygon to clip against " + 
-			"the current selection.")
+		ui.Editor.ReplaceShape(ui.State.SelectedShape, ui.State.SelectedShape.ToPath(), "Convert to path")
+		ui.StatusLabel.SetText("Shape converted to path")
 	})
 
 
 	aaCheck := widget.NewCheck("Anti-aliasing", func(checked bool) {
-		ui.State.AntiAliasing = checked
-		ui.Canvas.Refresh()
+		ui.Editor.SetAntiAliasing(checked)
 		if checked {
 			ui.StatusLabel.SetText("Anti-aliasing enabled")
 		} else {
@@ -229,13 +289,12 @@ func NewMainUI(window fyne.Window) *MainUI {
 	penTypeLabel := widget.NewLabel("Pen Type:")
 	regularPenRadio := widget.NewRadioGroup([]string{"Regular Pen", "Brush"}, func(selected string) {
 		if selected == "Regular Pen" {
-			ui.State.PenType = "regular"
+			ui.Editor.SetPenType("regular")
 			ui.StatusLabel.SetText("Regular Pen selected")
 		} else {
-			ui.State.PenType = "brush"
+			ui.Editor.SetPenType("brush")
 			ui.StatusLabel.SetText("Brush selected")
 		}
-		ui.Canvas.Refresh()
 	})
 	regularPenRadio.SetSelected("Brush")
 
@@ -246,7 +305,7 @@ func NewMainUI(window fyne.Window) *MainUI {
 	thicknessSlider.Step = 1
 	thicknessSlider.OnChanged = func(value float64) {
 		thickness := int(value)
-		ui.State.BrushThickness = thickness
+		ui.Editor.SetBrushThickness(thickness)
 		thicknessValue.SetText(fmt.Sprintf("%d", thickness))
 		ui.StatusLabel.SetText(fmt.Sprintf("Brush thickness set to %d", thickness))
 	}
@@ -256,88 +315,24 @@ func NewMainUI(window fyne.Window) *MainUI {
 
 	
 	fillCheck := widget.NewCheck("Fill Shapes", func(checked bool) {
-		ui.State.FillEnabled = checked
+		ui.Editor.SetFillEnabled(checked)
 		ui.StatusLabel.SetText(fmt.Sprintf("Fill %s", map[bool]string{true: "enabled", false: "disabled"}[checked]))
 	})
 	
 	fillColorBtn := widget.NewButton("Fill Color", func() {
-		rSlider := widget.NewSlider(0, 255)
-		gSlider := widget.NewSlider(0, 255)
-		bSlider := widget.NewSlider(0, 255)
-
-		fillColor := ui.State.FillColor
-		if fillColor == nil {
-			fillColor = color.RGBA{255, 255, 255, 255}
-		}
-		r, g, b, _ := fillColor.RGBA()
-		rSlider.Value = float64(uint8(r))
-		gSlider.Value = float64(uint8(g))
-		bSlider.Value = float64(uint8(b))
-
-		preview := canvas.NewRectangle(fillColor)
-		preview.SetMinSize(fyne.NewSize(100, 60))
-
-		rLabel := widget.NewLabel(fmt.Sprintf("R: %d", uint8(r)))
-		gLabel := widget.NewLabel(fmt.Sprintf("G: %d", uint8(g)))
-		bLabel := widget.NewLabel(fmt.Sprintf("B: %d", uint8(b)))
-
-		updateFillColor := func() {
-			r := uint8(rSlider.Value)
-			g := uint8(gSlider.Value)
-			b := uint8(bSlider.Value)
-			newColor := color.RGBA{r, g, b, 255}
-
-			preview.FillColor = newColor
-			preview.Refresh()
-
-			rLabel.SetText(fmt.Sprintf("R: %d", r))
-			gLabel.SetText(fmt.Sprintf("G: %d", g))
-			bLabel.SetText(fmt.Sprintf("B: %d", b))
-			}
-
-		rSlider.OnChanged = func(value float64) {
-			updateFillColor()
-		}
-		gSlider.OnChanged = func(value float64) {
-			updateFillColor()
-		}
-		bSlider.OnChanged = func(value float64) {
-			updateFillColor()
-		}
-
-		content := container.NewVBox(
-			preview,
-			widget.NewSeparator(),
-			container.NewHBox(widget.NewLabel("Red:"), rLabel),
-			rSlider,
-			container.NewHBox(widget.NewLabel("Green:"), gLabel),
-			gSlider,
-			container.NewHBox(widget.NewLabel("Blue:"), bLabel),
-			bSlider,
-			)
-
-		customDialog := dialog.NewCustom("Choose Fill Color", "Apply", content, ui.Window)
-		customDialog.SetOnClosed(func() {
-			newColor := color.RGBA{
-				R: uint8(rSlider.Value),
-				G: uint8(gSlider.Value),
-				B: uint8(bSlider.Value),
-				A: 255,
-			}
-			ui.State.FillColor = newColor
+		ui.showColorDialog("Choose Fill Color", ui.State.FillColor, func(newColor color.RGBA) {
+			ui.Editor.SetFillColor(newColor)
 			ui.StatusLabel.SetText("Fill color updated")
-				
-			if ui.State.SelectedShape != nil {
-				switch s := ui.State.SelectedShape.(type) {
-				case *models.Polygon:
-					s.SetFillColor(newColor)
-				case *models.Rectangle:
-					s.SetFillColor(newColor)
-				}
-				ui.Canvas.Refresh()
-			}
+			ui.applyFillColor(newColor)
+		})
+	})
+
+	strokeColorBtn := widget.NewButton("Stroke Color", func() {
+		ui.showColorDialog("Choose Stroke Color", ui.State.CurrentColor, func(newColor color.RGBA) {
+			ui.Editor.SetColor(newColor)
+			ui.StatusLabel.SetText("Stroke color updated")
+			ui.applyStrokeColor(newColor)
 		})
-		customDialog.Show()
 	})
 	
 	loadImageBtn := widget.NewButton("Load Fill Image", func() {
@@ -361,22 +356,13 @@ func NewMainUI(window fyne.Window) *MainUI {
 			for y := 0; y < height; y++ {
 				fillImage[y] = make([]color.Color, width)
 				for x := 0; x < width; x++ {
-					fillImage[y][x] = imgData.At(x, y)
+					fillImage[y][x] = color.RGBAModel.Convert(imgData.At(x, y))
 				}
 			}
-				ui.State.FillImage = fillImage
-			ui.State.UseImageFill = true
+				ui.Editor.SetFillImage(fillImage)
 			ui.StatusLabel.SetText("Fill image loaded")
 				
-			if ui.State.SelectedShape != nil {
-				switch s := ui.State.SelectedShape.(type) {
-				case *models.Polygon:
-					s.SetFillImage(fillImage)
-				case *models.Rectangle:
-					s.SetFillImage(fillImage)
-				}
-				ui.Canvas.Refresh()
-			}
+			ui.applyFillImage(fillImage)
 		}, ui.Window)
 		fd.SetFilter(storage.NewExtensionFileFilter([]string{".png", ".jpg", ".jpeg"}))
 		fd.Show()
@@ -385,6 +371,7 @@ func NewMainUI(window fyne.Window) *MainUI {
 	fillContainer := container.NewVBox(
 		fillCheck,
 		container.NewHBox(fillColorBtn, loadImageBtn),
+		strokeColorBtn,
 	)
 	
 	ui.ToolsContainer = container.NewVBox(
@@ -395,13 +382,31 @@ func NewMainUI(window fyne.Window) *MainUI {
 		polygonBtn,
 		rectangleBtn,
 		selectBtn,
+		selectAllBtn,
+		cloneBtn,
+		container.NewGridWithColumns(2, copyBtn, cutBtn, pasteBtn, copyPNGBtn),
+		container.NewGridWithColumns(2, groupBtn, ungroupBtn),
+		ui.buildAlignControls(),
+		marqueeCheck,
+		lassoBtn,
+		lassoCheck,
+		vertexEditBtn,
 		widget.NewSeparator(),
+		undoBtn,
+		redoBtn,
 		clearBtn,
 		saveBtn, 
 		loadBtn, 
 		clipBtn,
+		offsetBtn,
+		outlineBtn,
+		toPathBtn,
+		transformBtn,
 		widget.NewSeparator(),
 		aaCheck,
+		ui.buildZoomControls(),
+		ui.buildInspectorControls(),
+		ui.buildSnapControls(),
 		widget.NewSeparator(),
 		penTypeLabel,
 		regularPenRadio,
@@ -421,8 +426,15 @@ func NewMainUI(window fyne.Window) *MainUI {
 		ui.StatusLabel,
 	)
 
+	ui.LayersPanel = ui.buildLayersPanel()
+	ui.SidePanel = container.NewAppTabs(
+		container.NewTabItem("Layers", ui.LayersPanel),
+		container.NewTabItem("Visualizer", ui.buildVisualizerPanel()),
+		container.NewTabItem("Algorithms", ui.buildAlgorithmPanel()),
+	)
+
 	ui.Container = container.NewBorder(
-		nil, statusBar, ui.ToolsContainer, nil,
+		nil, statusBar, ui.ToolsContainer, ui.SidePanel,
 		ui.Canvas,
 	)
 
@@ -466,18 +478,48 @@ func (ui *MainUI) renderCanvas(w, h int) image.Image {
 		}
 	}
 
+	if ui.PixelInspect {
+		ui.renderFatPixels(img)
+	} else {
+		if ui.Snap.ShowGrid {
+			drawGrid(img, ui.Snap.GridSize, ui.Viewport, color.RGBA{225, 225, 225, 255})
+		}
+
 	
-	for _, shape := range ui.State.Shapes {
-		canvas := make([][]color.Color, h)
-		for j := range canvas {
-			canvas[j] = make([]color.Color, w)
-			for i := 0; i < w; i++ {
-				canvas[j][i] = img.At(i, j)
+		models.RenderLayers(img, ui.State, ui.Viewport, ui.State.AntiAliasing)
+
+	
+		if ui.State.CurrentShape != nil {
+			canvas := make([][]color.Color, h)
+			for j := range canvas {
+				canvas[j] = make([]color.Color, w)
+				for i := 0; i < w; i++ {
+					canvas[j][i] = img.At(i, j)
+				}
+			}
+
+			models.DrawShape(ui.viewShape(ui.State.CurrentShape), canvas, ui.State.AntiAliasing, ui.State.Raster)
+
+			for x := 0; x < w; x++ {
+				for y := 0; y < h; y++ {
+					if canvas[y][x] != nil {
+						img.Set(x, y, canvas[y][x])
+					}
+				}
 			}
 		}
 
-		shape.Draw(canvas, ui.State.AntiAliasing)
+	
+	}
 
+	if ui.activeTool != nil {
+		canvas := make([][]color.Color, h)
+		for j := range canvas {
+			canvas[j] = make([]color.Color, w)
+		}
+		
+		ui.activeTool.DrawOverlay(ui, canvas)
+		
 		for x := 0; x < w; x++ {
 			for y := 0; y < h; y++ {
 				if canvas[y][x] != nil {
@@ -487,18 +529,14 @@ func (ui *MainUI) renderCanvas(w, h int) image.Image {
 		}
 	}
 
-	
-	if ui.State.CurrentShape != nil {
+	if ui.MarqueeActive {
 		canvas := make([][]color.Color, h)
 		for j := range canvas {
 			canvas[j] = make([]color.Color, w)
-			for i := 0; i < w; i++ {
-				canvas[j][i] = img.At(i, j)
-			}
 		}
-
-		ui.State.CurrentShape.Draw(canvas, ui.State.AntiAliasing)
-
+		
+		drawDashedRect(canvas, ui.Viewport.ToScreen(ui.MarqueeStart), ui.Viewport.ToScreen(ui.MarqueeEnd), color.RGBA{80, 80, 80, 255})
+		
 		for x := 0; x < w; x++ {
 			for y := 0; y < h; y++ {
 				if canvas[y][x] != nil {
@@ -508,24 +546,49 @@ func (ui *MainUI) renderCanvas(w, h int) image.Image {
 		}
 	}
 
-	
-	if ui.State.CurrentAction == "select" && ui.State.SelectedShape != nil {
-		controlPoints := ui.State.SelectedShape.GetControlPoints()
+	if len(ui.LassoPoints) > 1 {
+		canvas := make([][]color.Color, h)
+		for j := range canvas {
+			canvas[j] = make([]color.Color, w)
+		}
 		
-		indicatorColor := color.RGBA{0, 119, 255, 255} 
+		drawLasso(canvas, ui.viewPoints(ui.LassoPoints), color.RGBA{80, 80, 80, 255})
 		
+		for x := 0; x < w; x++ {
+			for y := 0; y < h; y++ {
+				if canvas[y][x] != nil {
+					img.Set(x, y, canvas[y][x])
+				}
+			}
+		}
+	}
+
+	if ui.Visualizer != nil && ui.Visualizer.Trace != nil {
 		canvas := make([][]color.Color, h)
 		for j := range canvas {
 			canvas[j] = make([]color.Color, w)
 		}
 		
-		if rect, isRect := ui.State.SelectedShape.(*models.Rectangle); isRect {
-			drawRectangleSelectionHandles(canvas, rect, indicatorColor)
-		} else {
-				for _, point := range controlPoints {
-				drawSelectionIndicator(canvas, point.X, point.Y, 5, indicatorColor)
+		drawTraceOverlay(canvas, ui.Visualizer.Trace, ui.Visualizer.Step, ui.Viewport)
+		
+		for x := 0; x < w; x++ {
+			for y := 0; y < h; y++ {
+				if canvas[y][x] != nil {
+					img.Set(x, y, canvas[y][x])
+				}
 			}
 		}
+	}
+
+	if ui.SnapIndicator.Snapped {
+		canvas := make([][]color.Color, h)
+		for j := range canvas {
+			canvas[j] = make([]color.Color, w)
+		}
+		
+		indicator := ui.SnapIndicator
+		indicator.Point = ui.Viewport.ToScreen(indicator.Point)
+		drawSnapIndicator(canvas, indicator, color.RGBA{255, 0, 170, 255})
 		
 		for x := 0; x < w; x++ {
 			for y := 0; y < h; y++ {
@@ -540,8 +603,10 @@ func (ui *MainUI) renderCanvas(w, h int) image.Image {
 }
 
 
-func drawSelectionIndicator(canvas [][]color.Color, x, y, size int, c color.Color) {
+func drawSelectionIndicator(canvas [][]color.Color, centerX, centerY float64, size int, c color.Color) {
 	halfSize := size / 2
+	x := int(math.Round(centerX))
+	y := int(math.Round(centerY))
 	
 	
 	if len(canvas) == 0 || len(canvas[0]) == 0 {
@@ -566,50 +631,27 @@ func drawSelectionIndicator(canvas [][]color.Color, x, y, size int, c color.Colo
 }
 
 
-func drawRectangleSelectionHandles(canvas [][]color.Color, rect *models.Rectangle, c color.Color) {
-	
-	points := rect.GetControlPoints()
-	for _, point := range points {
-		drawSelectionIndicator(canvas, point.X, point.Y, 8, c)
+func drawEditHandle(canvas [][]color.Color, handle models.Handle, c color.Color) {
+	switch handle.Kind {
+	case models.CornerHandle:
+		drawSelectionIndicator(canvas, handle.Position.X, handle.Position.Y, 8, c)
+	case models.RadiusHandle:
+		algorithms.MidpointCircle(canvas, int(math.Round(handle.Position.X)), int(math.Round(handle.Position.Y)), 4, c)
+		drawSelectionIndicator(canvas, handle.Position.X, handle.Position.Y, 1, c)
+	case models.ControlHandle:
+		drawSelectionIndicator(canvas, handle.Position.X, handle.Position.Y, 4, c)
+	default:
+		drawSelectionIndicator(canvas, handle.Position.X, handle.Position.Y, 6, c)
 	}
 }
 
 
-func (ui *MainUI) updatePillLength(length int) {
-	
-	if ui.State.CurrentAction == "select" && ui.State.SelectedShape != nil {
-		if pill, ok := ui.State.SelectedShape.(*models.Pill); ok {
-				dx := pill.End.X - pill.Start.X
-			dy := pill.End.Y - pill.Start.Y
-			currentLength := math.Sqrt(float64(dx*dx + dy*dy))
-				
-			if currentLength <= 0 {
-				return
-			}
-				
-			dirX := float64(dx) / currentLength
-			dirY := float64(dy) / currentLength
-				
-			pill.End.X = pill.Start.X + int(dirX * float64(length))
-			pill.End.Y = pill.Start.Y + int(dirY * float64(length))
-				ui.Canvas.Refresh()
-		}
-	} else if ui.State.CurrentShape != nil {
-		if pill, ok := ui.State.CurrentShape.(*models.Pill); ok && pill.Step >= 2 {
-				dx := pill.End.X - pill.Start.X
-			dy := pill.End.Y - pill.Start.Y
-			currentLength := math.Sqrt(float64(dx*dx + dy*dy))
-				
-			if currentLength <= 0 {
-				return
-			}
-				
-			dirX := float64(dx) / currentLength
-			dirY := float64(dy) / currentLength
-				
-			pill.End.X = pill.Start.X + int(dirX * float64(length))
-			pill.End.Y = pill.Start.Y + int(dirY * float64(length))
-				ui.Canvas.Refresh()
-		}
+func (ui *MainUI) onEditorChange(change editor.Change) {
+	if change.Has(editor.DocumentChanged) {
+		ui.refreshLayersPanel()
 	}
-}
\ No newline at end of file
+	if change.Has(editor.ToolChanged) {
+		ui.switchTool()
+	}
+	ui.Canvas.Refresh()
+}
@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"fmt"
+	"paint-drawer-pro/algorithms"
+	"paint-drawer-pro/models"
+	"strconv"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+
+var joinTypeNames = map[string]algorithms.JoinType{
+	"Miter": algorithms.MiterJoin,
+	"Round": algorithms.RoundJoin,
+	"Bevel": algorithms.BevelJoin,
+}
+
+
+func (ui *MainUI) showOffsetDialog() {
+	var source *models.Polygon
+	switch s := ui.State.SelectedShape.(type) {
+	case *models.Polygon:
+		source = s
+	case *models.Rectangle:
+		source = models.NewPolygon(s.GetVertices(), s.Color, s.Thickness)
+		source.FillColor = s.FillColor
+		source.IsFilled = s.IsFilled
+		source.FillImage = s.FillImage
+		source.UseImage = s.UseImage
+	default:
+		dialog.ShowInformation("Offset", "Please select a polygon or rectangle to offset.", ui.Window)
+		return
+	}
+
+	distanceEntry := widget.NewEntry()
+	distanceEntry.SetText("10")
+	joinSelect := widget.NewSelect([]string{"Miter", "Round", "Bevel"}, nil)
+	joinSelect.SetSelected("Miter")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Distance (negative shrinks)", distanceEntry),
+		widget.NewFormItem("Join", joinSelect),
+	}
+
+	original := ui.State.SelectedShape
+	dialog.ShowForm("Offset Polygon", "Apply", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		distance, err := strconv.ParseFloat(distanceEntry.Text, 64)
+		if err != nil {
+			dialog.ShowError(fmt.Errorf("invalid distance: %v", err), ui.Window)
+			return
+		}
+
+		result := source.Offset(distance, joinTypeNames[joinSelect.Selected])
+		if result == nil {
+			ui.StatusLabel.SetText("Offset collapsed the polygon; nothing changed.")
+			return
+		}
+
+		ui.Editor.ReplaceShape(original, result, "Offset polygon")
+		ui.StatusLabel.SetText(fmt.Sprintf("Polygon offset by %.1f", distance))
+	}, ui.Window)
+}
+
+
+func (ui *MainUI) convertSelectedToOutline() {
+	if ui.State.SelectedShape == nil {
+		dialog.ShowInformation("Outline", "Please select a shape to convert first.", ui.Window)
+		return
+	}
+
+	outliner, ok := ui.State.SelectedShape.(models.Outliner)
+	if !ok {
+		dialog.ShowInformation("Outline", "The selected shape cannot be converted to an outline.", ui.Window)
+		return
+	}
+
+	outline := outliner.Outline(algorithms.MiterJoin)
+	if outline == nil {
+		ui.StatusLabel.SetText("Shape is too small to convert to an outline.")
+		return
+	}
+
+	ui.Editor.ReplaceShape(ui.State.SelectedShape, outline, "Stroke to outline")
+	ui.StatusLabel.SetText("Stroke converted to filled outline polygon")
+}
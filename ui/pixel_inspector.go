@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"paint-drawer-pro/models"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+
+const (
+	inspectZoom      = 8
+	pixelGridMinZoom = 6
+)
+
+
+func (ui *MainUI) buildInspectorControls() *fyne.Container {
+	gridCheck := widget.NewCheck("Pixel Grid", func(checked bool) {
+		ui.PixelGrid = checked
+		ui.Canvas.Refresh()
+	})
+	gridCheck.SetChecked(true)
+
+	inspectCheck := widget.NewCheck("Pixel Inspector", func(checked bool) {
+		ui.PixelInspect = checked
+		ui.inspectedPixel = image.Point{X: -1, Y: -1}
+		if checked {
+			if ui.Viewport.Zoom < pixelGridMinZoom {
+				ui.zoomBy(inspectZoom / ui.Viewport.Zoom)
+			}
+			ui.StatusLabel.SetText("Pixel inspector: hover a pixel to see its color and source")
+		} else {
+			ui.inspectImage = nil
+			ui.inspectSources = nil
+			ui.StatusLabel.SetText("Pixel inspector disabled")
+		}
+		ui.Canvas.Refresh()
+	})
+
+	return container.NewGridWithColumns(2, inspectCheck, gridCheck)
+}
+
+
+func (ui *MainUI) renderFatPixels(img *image.RGBA) {
+	bounds := img.Bounds()
+	docMin, docMax := ui.Viewport.VisibleRect(float64(bounds.Dx()), float64(bounds.Dy()))
+	x0, y0 := int(math.Floor(docMin.X)), int(math.Floor(docMin.Y))
+	rw := int(math.Floor(docMax.X)) - x0 + 1
+	rh := int(math.Floor(docMax.Y)) - y0 + 1
+
+	region := image.NewRGBA(image.Rect(0, 0, rw, rh))
+	for i := range region.Pix {
+		region.Pix[i] = 0xff
+	}
+	sources := make([][]models.PixelSource, rh)
+	for y := range sources {
+		sources[y] = make([]models.PixelSource, rw)
+	}
+
+	trace := func(shapes []models.Shape, layer *models.Layer) {
+		originals := make(map[models.Shape]models.Shape, len(shapes))
+		clones := make([]models.Shape, len(shapes))
+		for i, shape := range shapes {
+			clones[i] = shape.Clone()
+			clones[i].Move(float64(-x0), float64(-y0))
+			originals[clones[i]] = shape
+		}
+
+		canvas := models.NewLayerCanvas(rw, rh)
+		layerSources := models.TraceProvenance(canvas, clones, ui.State.AntiAliasing, ui.State.Raster)
+		models.CompositeLayer(region, canvas, layer)
+
+		for y := range layerSources {
+			for x, src := range layerSources[y] {
+				if src.Shape != nil {
+					sources[y][x] = models.PixelSource{Shape: originals[src.Shape], Algorithm: src.Algorithm}
+				}
+			}
+		}
+	}
+
+	for i, layer := range ui.State.Layers {
+		if shapes := ui.State.LayerShapes(i); layer.Visible && len(shapes) > 0 {
+			trace(shapes, layer)
+		}
+	}
+	if ui.State.CurrentShape != nil {
+		trace([]models.Shape{ui.State.CurrentShape}, models.NewLayer(""))
+	}
+
+	zoom := ui.Viewport.Zoom
+	showGrid := ui.PixelGrid && zoom >= pixelGridMinZoom
+	gridColor := color.RGBA{200, 200, 200, 255}
+	for sy := 0; sy < bounds.Dy(); sy++ {
+		docY := (float64(sy) - ui.Viewport.PanY) / zoom
+		py := int(math.Floor(docY)) - y0
+		for sx := 0; sx < bounds.Dx(); sx++ {
+			docX := (float64(sx) - ui.Viewport.PanX) / zoom
+			px := int(math.Floor(docX)) - x0
+			if showGrid && (docX-math.Floor(docX) < 1/zoom || docY-math.Floor(docY) < 1/zoom) {
+				img.SetRGBA(sx, sy, gridColor)
+				continue
+			}
+			if px >= 0 && py >= 0 && px < rw && py < rh {
+				img.SetRGBA(sx, sy, region.RGBAAt(px, py))
+			}
+		}
+	}
+
+	ui.inspectImage = region
+	ui.inspectSources = sources
+	ui.inspectOrigin = image.Point{X: x0, Y: y0}
+}
+
+
+func (ui *MainUI) describePixel(p models.Point) {
+	if ui.inspectImage == nil {
+		return
+	}
+
+	pixel := image.Point{X: int(math.Floor(p.X)), Y: int(math.Floor(p.Y))}
+	if pixel == ui.inspectedPixel {
+		return
+	}
+	ui.inspectedPixel = pixel
+
+	local := pixel.Sub(ui.inspectOrigin)
+	if !local.In(ui.inspectImage.Bounds()) {
+		return
+	}
+
+	c := ui.inspectImage.RGBAAt(local.X, local.Y)
+	text := fmt.Sprintf("Pixel (%d, %d)  RGBA(%d, %d, %d, %d)", pixel.X, pixel.Y, c.R, c.G, c.B, c.A)
+
+	if src := ui.inspectSources[local.Y][local.X]; src.Shape != nil {
+		name := strings.TrimPrefix(fmt.Sprintf("%T", src.Shape), "*models.")
+		if layer := ui.State.LayerOfShape(src.Shape); layer >= 0 {
+			name = fmt.Sprintf("%s on %s", name, ui.State.Layers[layer].Name)
+		}
+		text += fmt.Sprintf("  written by %s via %s", name, src.Algorithm)
+	} else {
+		text += "  background"
+	}
+	ui.StatusLabel.SetText(text)
+}
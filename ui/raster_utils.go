@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"fmt"
+	"paint-drawer-pro/algorithms"
+	"paint-drawer-pro/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+
+const defaultAlgorithmOption = "Default"
+
+
+func algorithmOptions(names []string) []string {
+	return append([]string{defaultAlgorithmOption}, names...)
+}
+
+
+func algorithmFromOption(option string) string {
+	if option == defaultAlgorithmOption {
+		return ""
+	}
+	return option
+}
+
+
+func optionFromAlgorithm(name string) string {
+	if name == "" {
+		return defaultAlgorithmOption
+	}
+	return name
+}
+
+
+func newAlgorithmSelect(names []string, current string, changed func(name string)) *widget.Select {
+	sel := widget.NewSelect(algorithmOptions(names), func(option string) {
+		changed(algorithmFromOption(option))
+	})
+	sel.SetSelected(optionFromAlgorithm(current))
+	return sel
+}
+
+
+func (ui *MainUI) buildAlgorithmPanel() fyne.CanvasObject {
+	global := ui.State.Raster
+	setGlobal := func(update func(r *models.RasterAlgorithms)) {
+		r := ui.State.Raster
+		update(&r)
+		ui.Editor.SetRaster(r)
+	}
+
+	globalForm := widget.NewForm(
+		widget.NewFormItem("Line", newAlgorithmSelect(algorithms.LineAlgorithmNames(), global.Line, func(name string) {
+			setGlobal(func(r *models.RasterAlgorithms) { r.Line = name })
+		})),
+		widget.NewFormItem("Circle", newAlgorithmSelect(algorithms.CircleAlgorithmNames(), global.Circle, func(name string) {
+			setGlobal(func(r *models.RasterAlgorithms) { r.Circle = name })
+		})),
+		widget.NewFormItem("Fill", newAlgorithmSelect(algorithms.FillAlgorithmNames(), global.Fill, func(name string) {
+			setGlobal(func(r *models.RasterAlgorithms) { r.Fill = name })
+		})),
+	)
+
+	var shapeRaster models.RasterAlgorithms
+	shapeForm := widget.NewForm(
+		widget.NewFormItem("Line", newAlgorithmSelect(algorithms.LineAlgorithmNames(), "", func(name string) {
+			shapeRaster.Line = name
+		})),
+		widget.NewFormItem("Circle", newAlgorithmSelect(algorithms.CircleAlgorithmNames(), "", func(name string) {
+			shapeRaster.Circle = name
+		})),
+		widget.NewFormItem("Fill", newAlgorithmSelect(algorithms.FillAlgorithmNames(), "", func(name string) {
+			shapeRaster.Fill = name
+		})),
+	)
+
+	applyBtn := widget.NewButton("Apply to Selection", func() {
+		ui.setSelectionRaster(shapeRaster, "Set rasterization algorithms")
+	})
+	resetBtn := widget.NewButton("Reset Selection", func() {
+		ui.setSelectionRaster(models.RasterAlgorithms{}, "Reset rasterization algorithms")
+	})
+	compareBtn := widget.NewButton("Compare...", func() {
+		ui.showCompareDialog()
+	})
+
+	return container.NewVScroll(container.NewVBox(
+		widget.NewLabel("Drawing algorithms:"),
+		globalForm,
+		widget.NewSeparator(),
+		widget.NewLabel("Selected shapes:"),
+		shapeForm,
+		container.NewGridWithColumns(2, applyBtn, resetBtn),
+		widget.NewSeparator(),
+		compareBtn,
+	))
+}
+
+
+func (ui *MainUI) setSelectionRaster(raster models.RasterAlgorithms, label string) {
+	shapes := ui.State.SelectedShapes()
+	if len(shapes) == 0 {
+		dialog.ShowInformation("Algorithms", "Please select one or more shapes first.", ui.Window)
+		return
+	}
+
+	ui.Editor.EditShapes(shapes, label, func() {
+		for _, shape := range shapes {
+			models.SetShapeRaster(shape, raster)
+		}
+	})
+	ui.StatusLabel.SetText(fmt.Sprintf("%s on %d shape(s)", label, len(shapes)))
+	ui.Canvas.Refresh()
+}
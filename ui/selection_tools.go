@@ -0,0 +1,315 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"paint-drawer-pro/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+
+var selectionColor = color.RGBA{0, 119, 255, 255}
+
+
+type selectionTool struct {
+	baseTool
+}
+
+
+func (selectionTool) KeyDown(h *MouseHandler, ev *fyne.KeyEvent) bool {
+	if (ev.Name == fyne.KeyDelete || ev.Name == fyne.KeyBackspace) && h.UI.State.SelectedShape != nil {
+		h.UI.deleteSelection()
+		return true
+	}
+	return false
+}
+
+
+func (selectionTool) TappedSecondary(h *MouseHandler, ev *fyne.PointEvent) {
+	h.IsDrawing = false
+	point := h.adjustMousePosition(*ev)
+	if shape := h.shapeAt(point); shape != nil && !h.UI.State.IsSelected(shape) {
+		h.UI.Editor.Select(shape)
+	}
+	h.UI.showShapeContextMenu(ev.AbsolutePosition, point)
+}
+
+
+type selectGesture int
+
+const (
+	selectIdle selectGesture = iota
+	selectMoving
+	selectResizing
+	selectTransforming
+	selectMarquee
+)
+
+
+type selectTool struct {
+	selectionTool
+	gesture   selectGesture
+	handle    models.Handle
+	edit      editSession
+	move      snappedMove
+	transform transformGesture
+	additive  bool
+}
+
+
+func (t *selectTool) Label() string {
+	return "Select"
+}
+
+
+func (t *selectTool) Status() string {
+	return "Select tool active"
+}
+
+
+func (t *selectTool) Constrain(origin, p models.Point) models.Point {
+	return models.ConstrainAngle(origin, p, 90)
+}
+
+
+func (t *selectTool) Deactivate(ui *MainUI) {
+	t.gesture = selectIdle
+	ui.MarqueeActive = false
+	ui.PillLengthContainer.Hide()
+}
+
+
+func (t *selectTool) PillTarget(ui *MainUI) *models.Pill {
+	pill, _ := ui.State.SelectedShape.(*models.Pill)
+	return pill
+}
+
+
+func (t *selectTool) PointerDown(h *MouseHandler, ev *desktop.MouseEvent, p models.Point) {
+	t.gesture = selectIdle
+	if ev.Button != desktop.MouseButtonPrimary {
+		return
+	}
+	shift := ev.Modifier&fyne.KeyModifierShift != 0
+
+	if h.UI.State.SelectedShape != nil && !shift && len(h.UI.State.SelectedShapes()) == 1 {
+		if handle, found := h.UI.handleAt(h.UI.State.SelectedShape, h.ScreenPoint); found {
+			t.gesture = selectResizing
+			t.handle = handle
+			t.edit.begin(h.UI, h.UI.State.SelectedShape)
+			h.UI.StatusLabel.SetText("Editing shape...")
+			return
+		}
+
+		if handle := hitTransformHandle(h.UI.viewShape(h.UI.State.SelectedShape), h.ScreenPoint); handle != NoTransformHandle {
+			if t.transform.begin(h, handle) {
+				t.gesture = selectTransforming
+			}
+			return
+		}
+	}
+
+	shape := h.shapeAt(p)
+
+	if shift {
+		if shape != nil {
+			h.UI.Editor.ToggleSelection(shape)
+			h.SuppressTap = true
+			h.UI.PillLengthContainer.Hide()
+			h.UI.StatusLabel.SetText(fmt.Sprintf("%d shapes selected", len(h.UI.State.Selection)))
+		} else {
+			t.beginMarquee(h, p, true)
+			h.SuppressTap = true
+		}
+		return
+	}
+
+	if shape == nil {
+		h.UI.Editor.ClearSelection()
+		h.UI.PillLengthContainer.Hide()
+		t.beginMarquee(h, p, false)
+		h.UI.StatusLabel.SetText("No shape selected.")
+		return
+	}
+
+	h.UI.Editor.Focus(shape)
+	t.gesture = selectMoving
+	t.move.begin(h, p)
+	t.edit.begin(h.UI, h.UI.State.SelectedShapes()...)
+
+	if count := len(h.UI.State.SelectedShapes()); count > 1 {
+		h.UI.PillLengthContainer.Hide()
+		h.UI.StatusLabel.SetText(fmt.Sprintf("%d shapes selected. Drag to move them together. Press Delete to remove.", count))
+	} else if pill, isPill := shape.(*models.Pill); isPill {
+		h.UI.PillLengthSlider.SetValue(math.Hypot(pill.End.X-pill.Start.X, pill.End.Y-pill.Start.Y))
+		h.UI.PillLengthContainer.Show()
+		h.UI.StatusLabel.SetText("Pill selected. Use slider to adjust length or drag to move.")
+	} else if _, isRect := shape.(*models.Rectangle); isRect {
+		h.UI.PillLengthContainer.Hide()
+		h.UI.StatusLabel.SetText("Rectangle selected. Drag corners to resize or drag center to move. Press Delete to remove.")
+	} else {
+		h.UI.PillLengthContainer.Hide()
+		h.UI.StatusLabel.SetText("Shape selected. Drag handles to edit or drag the shape to move. Press Delete to remove.")
+	}
+	h.UI.Canvas.Refresh()
+}
+
+
+func (t *selectTool) PointerMove(h *MouseHandler, p models.Point) {
+	switch t.gesture {
+	case selectMoving:
+		t.move.update(h, p)
+	case selectResizing:
+		if editable, ok := h.UI.State.SelectedShape.(models.Editable); ok {
+			editable.MoveHandle(t.handle.ID, h.snapPoint(p, h.UI.State.SelectedShape))
+			h.UI.Canvas.Refresh()
+		}
+	case selectTransforming:
+		t.transform.update(h, p)
+	case selectMarquee:
+		h.UI.MarqueeEnd = p
+		h.UI.Canvas.Refresh()
+	default:
+		t.selectionTool.PointerMove(h, p)
+	}
+}
+
+
+func (t *selectTool) PointerUp(h *MouseHandler, p models.Point) {
+	gesture := t.gesture
+	t.gesture = selectIdle
+
+	switch gesture {
+	case selectMoving:
+		t.edit.end(h.UI, "Move shape")
+		h.UI.StatusLabel.SetText("Shape moved.")
+		h.UI.Canvas.Refresh()
+	case selectResizing:
+		t.handle = models.Handle{}
+		t.edit.end(h.UI, "Edit shape")
+		h.UI.StatusLabel.SetText("Shape edited.")
+		h.UI.Canvas.Refresh()
+	case selectTransforming:
+		t.transform.end(h)
+	case selectMarquee:
+		t.endMarquee(h)
+	}
+}
+
+
+func (t *selectTool) DrawOverlay(ui *MainUI, canvas [][]color.Color) {
+	if len(ui.State.SelectedShapes()) > 1 {
+		views := ui.viewShapes(ui.State.SelectedShapes())
+		drawMultiSelection(canvas, &models.DrawingState{Shapes: views, Selection: views}, selectionColor)
+		return
+	}
+	if ui.State.SelectedShape == nil {
+		return
+	}
+
+	selected := ui.viewShape(ui.State.SelectedShape)
+	if editable, ok := selected.(models.Editable); ok {
+		for _, handle := range editable.GetHandles() {
+			drawEditHandle(canvas, handle, selectionColor)
+		}
+	} else {
+		for _, point := range selected.GetControlPoints() {
+			drawSelectionIndicator(canvas, point.X, point.Y, 5, selectionColor)
+		}
+	}
+	drawTransformBox(canvas, selected, selectionColor)
+}
+
+
+type lassoTool struct {
+	selectionTool
+	active   bool
+	additive bool
+}
+
+
+func (t *lassoTool) Label() string {
+	return "Lasso"
+}
+
+
+func (t *lassoTool) Status() string {
+	return "Lasso tool: draw a loop around shapes to select them (Shift adds to the selection)"
+}
+
+
+func (t *lassoTool) Deactivate(ui *MainUI) {
+	t.active = false
+	ui.LassoPoints = nil
+}
+
+
+func (t *lassoTool) PointerDown(h *MouseHandler, ev *desktop.MouseEvent, p models.Point) {
+	if ev.Button != desktop.MouseButtonPrimary {
+		return
+	}
+	shift := ev.Modifier&fyne.KeyModifierShift != 0
+	t.beginLasso(h, p, shift)
+	h.SuppressTap = shift
+	h.UI.Canvas.Refresh()
+}
+
+
+func (t *lassoTool) PointerMove(h *MouseHandler, p models.Point) {
+	if !t.active {
+		t.selectionTool.PointerMove(h, p)
+		return
+	}
+	t.extendLasso(h, p)
+}
+
+
+func (t *lassoTool) PointerUp(h *MouseHandler, p models.Point) {
+	if t.active {
+		t.endLasso(h)
+	}
+}
+
+
+func (t *lassoTool) DrawOverlay(ui *MainUI, canvas [][]color.Color) {
+	if len(ui.State.SelectedShapes()) == 0 {
+		return
+	}
+	views := ui.viewShapes(ui.State.SelectedShapes())
+	drawMultiSelection(canvas, &models.DrawingState{Shapes: views, Selection: views}, selectionColor)
+}
+
+
+type clippingTool struct {
+	baseTool
+}
+
+
+func (t *clippingTool) Label() string {
+	return "Clipping"
+}
+
+
+func (t *clippingTool) Status() string {
+	return "Clipping mode active. Select a polygon to clip against the current selection."
+}
+
+
+func (t *clippingTool) PointerUp(h *MouseHandler, p models.Point) {
+	shapes := h.UI.State.Shapes
+	for i := len(shapes) - 1; i >= 0; i-- {
+		if !shapes[i].Contains(p) {
+			continue
+		}
+		clipper, _ := h.UI.State.SelectedShape.(*models.Polygon)
+		if err := h.UI.Editor.ClipPolygon(shapes[i], clipper); err != nil {
+			h.UI.StatusLabel.SetText(err.Error())
+			return
+		}
+		h.UI.StatusLabel.SetText("Polygon clipped successfully.")
+		return
+	}
+}
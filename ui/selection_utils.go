@@ -0,0 +1,241 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"paint-drawer-pro/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+
+const cloneOffset = 10
+
+
+func (ui *MainUI) selectAll() {
+	ui.Editor.SetTool(ToolSelect)
+	ui.Editor.SelectAll()
+	ui.StatusLabel.SetText(fmt.Sprintf("%d shapes selected", len(ui.State.Selection)))
+}
+
+
+func (ui *MainUI) cloneSelection() {
+	selected := ui.State.SelectedShapes()
+	if len(selected) == 0 {
+		dialog.ShowInformation("Clone", "Please select one or more shapes to clone first.", ui.Window)
+		return
+	}
+
+	clones := make([]models.Shape, len(selected))
+	for i, shape := range selected {
+		clones[i] = shape.Clone()
+		clones[i].Move(cloneOffset, cloneOffset)
+	}
+
+	first := ui.State.ActiveInsertIndex()
+	ui.Editor.AddShapes(clones, fmt.Sprintf("Clone %d shapes", len(clones)))
+	ui.Editor.Select(ui.State.Shapes[first : first+len(clones)]...)
+	ui.StatusLabel.SetText(fmt.Sprintf("%d shapes cloned", len(clones)))
+}
+
+
+func (ui *MainUI) deleteSelection() {
+	selected := ui.State.SelectedShapes()
+	if len(selected) == 0 {
+		return
+	}
+	count := len(selected)
+	ui.Editor.RemoveShapes(selected)
+	ui.Editor.ClearSelection()
+	ui.PillLengthContainer.Hide()
+	ui.StatusLabel.SetText(fmt.Sprintf("%d shapes deleted", count))
+}
+
+
+func (ui *MainUI) applyFillColor(c color.Color) {
+	selected := ui.State.SelectedShapes()
+	if len(selected) == 0 {
+		return
+	}
+	ui.Editor.EditShapes(selected, "Change fill color", func() {
+		for _, shape := range models.FlattenGroups(selected) {
+			switch s := shape.(type) {
+			case *models.Polygon:
+				s.SetFillColor(c)
+			case *models.Rectangle:
+				s.SetFillColor(c)
+			case *models.Path:
+				s.SetFillColor(c)
+			}
+		}
+	})
+	ui.Canvas.Refresh()
+}
+
+
+func (ui *MainUI) applyFillImage(img [][]color.Color) {
+	selected := ui.State.SelectedShapes()
+	if len(selected) == 0 {
+		return
+	}
+	ui.Editor.EditShapes(selected, "Change fill image", func() {
+		for _, shape := range models.FlattenGroups(selected) {
+			switch s := shape.(type) {
+			case *models.Polygon:
+				s.SetFillImage(img)
+			case *models.Rectangle:
+				s.SetFillImage(img)
+			}
+		}
+	})
+	ui.Canvas.Refresh()
+}
+
+
+func (ui *MainUI) applyStrokeColor(c color.Color) {
+	selected := ui.State.SelectedShapes()
+	if len(selected) == 0 {
+		return
+	}
+	ui.Editor.EditShapes(selected, "Change color", func() {
+		for _, shape := range selected {
+			shape.SetColor(c)
+		}
+	})
+	ui.Canvas.Refresh()
+}
+
+
+func (ui *MainUI) showColorDialog(title string, initial color.Color, apply func(color.RGBA)) {
+	rSlider := widget.NewSlider(0, 255)
+	gSlider := widget.NewSlider(0, 255)
+	bSlider := widget.NewSlider(0, 255)
+
+	if initial == nil {
+		initial = color.RGBA{255, 255, 255, 255}
+	}
+	r, g, b, _ := initial.RGBA()
+	rSlider.Value = float64(uint8(r >> 8))
+	gSlider.Value = float64(uint8(g >> 8))
+	bSlider.Value = float64(uint8(b >> 8))
+
+	preview := canvas.NewRectangle(initial)
+	preview.SetMinSize(fyne.NewSize(100, 60))
+
+	rLabel := widget.NewLabel(fmt.Sprintf("R: %d", uint8(rSlider.Value)))
+	gLabel := widget.NewLabel(fmt.Sprintf("G: %d", uint8(gSlider.Value)))
+	bLabel := widget.NewLabel(fmt.Sprintf("B: %d", uint8(bSlider.Value)))
+
+	current := func() color.RGBA {
+		return color.RGBA{uint8(rSlider.Value), uint8(gSlider.Value), uint8(bSlider.Value), 255}
+	}
+	update := func(float64) {
+		c := current()
+		preview.FillColor = c
+		preview.Refresh()
+		rLabel.SetText(fmt.Sprintf("R: %d", c.R))
+		gLabel.SetText(fmt.Sprintf("G: %d", c.G))
+		bLabel.SetText(fmt.Sprintf("B: %d", c.B))
+	}
+	rSlider.OnChanged = update
+	gSlider.OnChanged = update
+	bSlider.OnChanged = update
+
+	content := container.NewVBox(
+		preview,
+		widget.NewSeparator(),
+		container.NewHBox(widget.NewLabel("Red:"), rLabel),
+		rSlider,
+		container.NewHBox(widget.NewLabel("Green:"), gLabel),
+		gSlider,
+		container.NewHBox(widget.NewLabel("Blue:"), bLabel),
+		bSlider,
+	)
+
+	dialog.ShowCustomConfirm(title, "Apply", "Cancel", content, func(confirmed bool) {
+		if confirmed {
+			apply(current())
+		}
+	}, ui.Window)
+}
+
+
+func (ui *MainUI) registerSelectionShortcuts() {
+	selectAll := &desktop.CustomShortcut{KeyName: fyne.KeyA, Modifier: fyne.KeyModifierShortcutDefault}
+	ui.Window.Canvas().AddShortcut(selectAll, func(fyne.Shortcut) { ui.selectAll() })
+}
+
+
+func (t *selectTool) beginMarquee(h *MouseHandler, p models.Point, additive bool) {
+	t.gesture = selectMarquee
+	t.additive = additive
+	h.UI.MarqueeActive = true
+	h.UI.MarqueeStart = p
+	h.UI.MarqueeEnd = p
+}
+
+
+func (t *selectTool) endMarquee(h *MouseHandler) {
+	h.UI.MarqueeActive = false
+
+	hits := models.ShapesInRect(h.UI.State.SelectableShapes(), h.UI.MarqueeStart, h.UI.MarqueeEnd, h.UI.MarqueeMode)
+	if t.additive {
+		selection := h.UI.State.SelectedShapes()
+		for _, shape := range hits {
+			if models.IndexOfShape(selection, shape) < 0 {
+				selection = append(selection, shape)
+			}
+		}
+		hits = selection
+	}
+	h.UI.Editor.Select(hits...)
+
+	if len(hits) == 0 {
+		h.UI.StatusLabel.SetText("No shape selected.")
+	} else {
+		h.UI.StatusLabel.SetText(fmt.Sprintf("%d shapes selected", len(hits)))
+	}
+}
+
+
+func (h *MouseHandler) shapeAt(p models.Point) models.Shape {
+	shapes := h.UI.State.SelectableShapes()
+	for i := len(shapes) - 1; i >= 0; i-- {
+		if shapes[i].Contains(p) {
+			return shapes[i]
+		}
+	}
+	return nil
+}
+
+
+func drawMultiSelection(canvas [][]color.Color, state *models.DrawingState, c color.Color) {
+	for _, shape := range state.SelectedShapes() {
+		for _, point := range shape.GetControlPoints() {
+			drawSelectionIndicator(canvas, point.X, point.Y, 5, c)
+		}
+	}
+
+	minP, maxP, ok := state.SelectionBounds()
+	if !ok {
+		return
+	}
+	minP = models.Point{X: minP.X - transformBoxPadding, Y: minP.Y - transformBoxPadding}
+	maxP = models.Point{X: maxP.X + transformBoxPadding, Y: maxP.Y + transformBoxPadding}
+	drawDashedRect(canvas, minP, maxP, c)
+}
+
+
+func drawDashedRect(canvas [][]color.Color, a, b models.Point, c color.Color) {
+	topRight := models.Point{X: b.X, Y: a.Y}
+	bottomLeft := models.Point{X: a.X, Y: b.Y}
+	drawDashedLine(canvas, a, topRight, c)
+	drawDashedLine(canvas, topRight, b, c)
+	drawDashedLine(canvas, b, bottomLeft, c)
+	drawDashedLine(canvas, bottomLeft, a, c)
+}
@@ -0,0 +1,346 @@
+package ui
+
+import (
+	"image/color"
+	"math"
+	"paint-drawer-pro/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+
+type shapeTool struct {
+	baseTool
+}
+
+
+func (shapeTool) Snaps() bool {
+	return true
+}
+
+
+func (h *MouseHandler) beginShapeAt(p models.Point) models.Point {
+	p = h.snapPoint(p)
+	h.StartPoint = p
+	h.CurrentPoint = p
+	return p
+}
+
+
+func (h *MouseHandler) commitShape(status string) {
+	if !h.IsDrawing || h.UI.Editor.Preview() == nil {
+		return
+	}
+
+	h.IsDrawing = false
+	h.UI.Editor.CommitPreview()
+	h.UI.StatusLabel.SetText(status)
+}
+
+
+type fillable interface {
+	SetFillColor(c color.Color)
+	SetFillImage(img [][]color.Color)
+}
+
+
+func (h *MouseHandler) applyFill(shape fillable) {
+	state := h.UI.State
+	if !state.FillEnabled {
+		return
+	}
+	if state.UseImageFill && state.FillImage != nil {
+		shape.SetFillImage(state.FillImage)
+	} else if state.FillColor != nil {
+		shape.SetFillColor(state.FillColor)
+	}
+}
+
+
+type lineTool struct {
+	shapeTool
+}
+
+
+func (t *lineTool) Label() string {
+	return "Line"
+}
+
+
+func (t *lineTool) Status() string {
+	return "Line tool selected"
+}
+
+
+func (t *lineTool) PointerDown(h *MouseHandler, ev *desktop.MouseEvent, p models.Point) {
+	p = h.beginShapeAt(p)
+
+	thickness := 1
+	if h.UI.State.PenType == "brush" {
+		thickness = h.UI.State.BrushThickness
+	}
+	h.UI.Editor.SetPreview(models.NewLine(p, p, h.UI.State.CurrentColor, thickness, h.UI.State.PenType))
+	h.IsDrawing = true
+	h.UI.StatusLabel.SetText("Drawing line... Release to complete")
+}
+
+
+func (t *lineTool) PointerMove(h *MouseHandler, p models.Point) {
+	line, ok := h.UI.Editor.Preview().(*models.Line)
+	if !h.IsDrawing || !ok {
+		h.updateSnapHover()
+		return
+	}
+	line.End = h.constrainPoint(line.Start, h.snapPoint(p))
+	h.UI.Editor.UpdatePreview()
+}
+
+
+func (t *lineTool) PointerUp(h *MouseHandler, p models.Point) {
+	h.commitShape("Line added")
+}
+
+
+type circleTool struct {
+	shapeTool
+}
+
+
+func (t *circleTool) Label() string {
+	return "Circle"
+}
+
+
+func (t *circleTool) Status() string {
+	return "Circle tool selected"
+}
+
+
+func (t *circleTool) PointerDown(h *MouseHandler, ev *desktop.MouseEvent, p models.Point) {
+	p = h.beginShapeAt(p)
+	h.UI.Editor.SetPreview(models.NewCircle(p, 1, h.UI.State.CurrentColor))
+	h.IsDrawing = true
+	h.UI.StatusLabel.SetText("Drawing circle... Release to complete")
+}
+
+
+func (t *circleTool) PointerMove(h *MouseHandler, p models.Point) {
+	circle, ok := h.UI.Editor.Preview().(*models.Circle)
+	if !h.IsDrawing || !ok {
+		h.updateSnapHover()
+		return
+	}
+	edge := h.snapPoint(p)
+	circle.Radius = math.Hypot(edge.X-circle.Center.X, edge.Y-circle.Center.Y)
+	h.UI.Editor.UpdatePreview()
+}
+
+
+func (t *circleTool) PointerUp(h *MouseHandler, p models.Point) {
+	h.commitShape("Circle added")
+}
+
+
+type rectangleTool struct {
+	shapeTool
+}
+
+
+func (t *rectangleTool) Label() string {
+	return "Rectangle"
+}
+
+
+func (t *rectangleTool) Status() string {
+	return "Rectangle tool selected"
+}
+
+
+func (t *rectangleTool) Constrain(origin, p models.Point) models.Point {
+	return models.ConstrainSquare(origin, p)
+}
+
+
+func (t *rectangleTool) PointerDown(h *MouseHandler, ev *desktop.MouseEvent, p models.Point) {
+	p = h.beginShapeAt(p)
+	rectangle := models.NewRectangle(p, p, h.UI.State.CurrentColor, h.UI.State.BrushThickness)
+	h.applyFill(rectangle)
+	h.UI.Editor.SetPreview(rectangle)
+	h.IsDrawing = true
+	h.UI.StatusLabel.SetText("Drawing rectangle... Release to complete")
+}
+
+
+func (t *rectangleTool) PointerMove(h *MouseHandler, p models.Point) {
+	rectangle, ok := h.UI.Editor.Preview().(*models.Rectangle)
+	if !h.IsDrawing || !ok {
+		h.updateSnapHover()
+		return
+	}
+	rectangle.BottomRight = h.constrainPoint(rectangle.TopLeft, h.snapPoint(p))
+	h.UI.Editor.UpdatePreview()
+}
+
+
+func (t *rectangleTool) PointerUp(h *MouseHandler, p models.Point) {
+	h.commitShape("Rectangle added")
+}
+
+
+type polygonTool struct {
+	shapeTool
+	Points []models.Point
+}
+
+
+func (t *polygonTool) Label() string {
+	return "Polygon"
+}
+
+
+func (t *polygonTool) Status() string {
+	return "Polygon tool selected"
+}
+
+
+func (t *polygonTool) PlacesByClick() bool {
+	return true
+}
+
+
+func (t *polygonTool) Deactivate(ui *MainUI) {
+	t.Points = nil
+	if _, drawing := ui.Editor.Preview().(*models.Polygon); drawing {
+		ui.Editor.SetPreview(nil)
+	}
+}
+
+
+func (t *polygonTool) PointerDown(h *MouseHandler, ev *desktop.MouseEvent, p models.Point) {
+	p = h.snapPoint(p)
+	if len(t.Points) > 0 {
+		p = h.constrainPoint(t.Points[len(t.Points)-1], p)
+	}
+	h.StartPoint = p
+	h.CurrentPoint = p
+	if ev.Button != desktop.MouseButtonPrimary {
+		return
+	}
+
+	t.Points = append(t.Points, p)
+	if len(t.Points) == 1 {
+		h.UI.StatusLabel.SetText("Creating polygon... Click to add points, press Enter to finish")
+		return
+	}
+	if len(t.Points) >= 3 {
+		h.UI.Editor.SetPreview(models.NewPolygon(t.Points, h.UI.State.CurrentColor, 1))
+	}
+	h.UI.StatusLabel.SetText("Added point to polygon. Click for more points, press Enter to finish")
+}
+
+
+func (t *polygonTool) KeyDown(h *MouseHandler, ev *fyne.KeyEvent) bool {
+	switch ev.Name {
+	case fyne.KeyReturn:
+		if len(t.Points) < 3 {
+			return false
+		}
+		poly := models.NewPolygon(t.Points, h.UI.State.CurrentColor, 1)
+		h.applyFill(poly)
+		t.Points = nil
+		h.UI.Editor.SetPreview(nil)
+		h.UI.Editor.AddShape(poly)
+		h.UI.StatusLabel.SetText("Polygon added")
+		return true
+	case fyne.KeyEscape:
+		t.Points = nil
+	}
+	return false
+}
+
+
+type pillTool struct {
+	shapeTool
+}
+
+
+func (t *pillTool) Label() string {
+	return "Pill"
+}
+
+
+func (t *pillTool) Status() string {
+	return "Pill tool selected: Click to place first end, then set radius, then place second end"
+}
+
+
+func (t *pillTool) PlacesByClick() bool {
+	return true
+}
+
+
+func (t *pillTool) Activate(ui *MainUI) {
+	ui.PillLengthContainer.Show()
+	ui.PillLengthSlider.SetValue(100)
+}
+
+
+func (t *pillTool) Deactivate(ui *MainUI) {
+	ui.PillLengthContainer.Hide()
+	if _, drawing := ui.Editor.Preview().(*models.Pill); drawing {
+		ui.Editor.SetPreview(nil)
+	}
+}
+
+
+func (t *pillTool) PillTarget(ui *MainUI) *models.Pill {
+	if pill, drawing := ui.Editor.Preview().(*models.Pill); drawing && pill.Step >= 2 {
+		return pill
+	}
+	return nil
+}
+
+
+func (t *pillTool) PointerDown(h *MouseHandler, ev *desktop.MouseEvent, p models.Point) {
+	p = h.snapPoint(p)
+	pill, isPill := h.UI.Editor.Preview().(*models.Pill)
+	if isPill && pill.Step == 1 {
+		p = h.constrainPoint(pill.Start, p)
+	}
+	h.StartPoint = p
+	h.CurrentPoint = p
+	if ev.Button != desktop.MouseButtonPrimary {
+		return
+	}
+
+	if !isPill {
+		h.UI.Editor.SetPreview(models.NewPill(p, 5, h.UI.State.CurrentColor))
+		h.UI.StatusLabel.SetText("Pill started. Click to set radius.")
+		return
+	}
+
+	switch pill.Step {
+	case 1:
+		dx := p.X - pill.Start.X
+		dy := p.Y - pill.Start.Y
+		pill.Radius = math.Sqrt(dx*dx + dy*dy)
+		pill.Step = 2
+		h.UI.PillLengthContainer.Show()
+		h.UI.PillLengthSlider.SetValue(float64(pill.Radius * 4))
+		length := h.UI.PillLengthSlider.Value
+		if dx != 0 || dy != 0 {
+			pill.End.X = pill.Start.X + dx/pill.Radius*length
+			pill.End.Y = pill.Start.Y + dy/pill.Radius*length
+		} else {
+			pill.End.X = pill.Start.X + length
+			pill.End.Y = pill.Start.Y
+		}
+		h.UI.Editor.UpdatePreview()
+		h.UI.StatusLabel.SetText("Pill radius set. Use slider to adjust length, click to finalize.")
+	case 2:
+		pill.Step = 3
+		h.UI.Editor.CommitPreview()
+		h.UI.StatusLabel.SetText("Pill added")
+	}
+}
@@ -0,0 +1,213 @@
+package ui
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"paint-drawer-pro/algorithms"
+	"paint-drawer-pro/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+
+func (ui *MainUI) buildSnapControls() *fyne.Container {
+	gridCheck := widget.NewCheck("Show Grid", func(checked bool) {
+		ui.Snap.ShowGrid = checked
+		ui.Canvas.Refresh()
+	})
+
+	snapGridCheck := widget.NewCheck("Snap to Grid", func(checked bool) {
+		ui.Snap.SnapToGrid = checked
+		ui.StatusLabel.SetText(fmt.Sprintf("Grid snapping %s", map[bool]string{true: "enabled", false: "disabled"}[checked]))
+	})
+
+	snapObjectsCheck := widget.NewCheck("Snap to Objects", func(checked bool) {
+		ui.Snap.SnapToObjects = checked
+		ui.StatusLabel.SetText(fmt.Sprintf("Object snapping %s", map[bool]string{true: "enabled", false: "disabled"}[checked]))
+	})
+
+	gridSizeLabel := widget.NewLabel("Grid Size:")
+	gridSizeValue := widget.NewLabel(fmt.Sprintf("%d", int(ui.Snap.GridSize)))
+	gridSizeSlider := widget.NewSlider(5, 100)
+	gridSizeSlider.Step = 5
+	gridSizeSlider.SetValue(ui.Snap.GridSize)
+	gridSizeSlider.OnChanged = func(value float64) {
+		ui.Snap.GridSize = value
+		gridSizeValue.SetText(fmt.Sprintf("%d", int(value)))
+		ui.Canvas.Refresh()
+	}
+
+	return container.NewVBox(
+		container.NewGridWithColumns(2, gridCheck, snapGridCheck),
+		snapObjectsCheck,
+		container.NewBorder(nil, nil, gridSizeLabel, gridSizeValue, gridSizeSlider),
+	)
+}
+
+
+func (ui *MainUI) registerKeyStateTracking() {
+	dc, ok := ui.Window.Canvas().(desktop.Canvas)
+	if !ok {
+		return
+	}
+
+	track := func(name fyne.KeyName, held bool) {
+		switch name {
+		case desktop.KeyShiftLeft, desktop.KeyShiftRight:
+			ui.ShiftHeld = held
+		case fyne.KeySpace:
+			ui.SpaceHeld = held
+		}
+	}
+	dc.SetOnKeyDown(func(ev *fyne.KeyEvent) { track(ev.Name, true) })
+	dc.SetOnKeyUp(func(ev *fyne.KeyEvent) { track(ev.Name, false) })
+
+	if app := fyne.CurrentApp(); app != nil {
+		app.Lifecycle().SetOnExitedForeground(ui.releaseHeldKeys)
+	}
+}
+
+
+func (ui *MainUI) releaseHeldKeys() {
+	ui.ShiftHeld = false
+	ui.SpaceHeld = false
+}
+
+
+func (h *MouseHandler) syncModifiers(ev *desktop.MouseEvent) {
+	h.UI.ShiftHeld = ev.Modifier&fyne.KeyModifierShift != 0
+}
+
+
+func (h *MouseHandler) snapPoint(p models.Point, exclude ...models.Shape) models.Point {
+	settings := h.UI.Snap
+	settings.Tolerance /= h.UI.Viewport.Zoom
+	result := models.Snap(p, h.UI.State.VisibleShapes(), exclude, settings)
+	h.UI.SnapIndicator = result
+	return result.Point
+}
+
+
+func (h *MouseHandler) constrainPoint(origin, p models.Point) models.Point {
+	if !h.UI.ShiftHeld {
+		return p
+	}
+
+	var constrained models.Point
+	if constrainer, ok := h.UI.Tool().(ConstrainingTool); ok {
+		constrained = constrainer.Constrain(origin, p)
+	} else {
+		constrained = models.ConstrainAngle(origin, p, h.UI.Snap.AngleStep)
+	}
+
+	if constrained != p {
+		h.UI.SnapIndicator = models.SnapResult{}
+	}
+	return constrained
+}
+
+
+func (h *MouseHandler) updateSnapHover() {
+	previous := h.UI.SnapIndicator
+	if isDrawingTool(h.UI.Tool()) {
+		h.snapPoint(h.CurrentPoint)
+	} else {
+		h.UI.SnapIndicator = models.SnapResult{}
+	}
+	if h.UI.SnapIndicator != previous {
+		h.UI.Canvas.Refresh()
+	}
+}
+
+
+type snappedMove struct {
+	anchor     models.Point
+	origin     models.Point
+	grabOffset models.Point
+}
+
+
+func (m *snappedMove) begin(h *MouseHandler, grab models.Point) {
+	minP, _, ok := h.UI.State.SelectionBounds()
+	if !ok {
+		minP = grab
+	}
+	m.anchor = minP
+	m.origin = minP
+	m.grabOffset = models.Point{X: grab.X - minP.X, Y: grab.Y - minP.Y}
+}
+
+
+func (m *snappedMove) update(h *MouseHandler, p models.Point) {
+	selected := h.UI.State.SelectedShapes()
+	target := models.Point{X: p.X - m.grabOffset.X, Y: p.Y - m.grabOffset.Y}
+	target = h.constrainPoint(m.origin, h.snapPoint(target, selected...))
+
+	deltaX := target.X - m.anchor.X
+	deltaY := target.Y - m.anchor.Y
+	if deltaX == 0 && deltaY == 0 {
+		return
+	}
+
+	for _, shape := range selected {
+		shape.Move(deltaX, deltaY)
+	}
+	m.anchor = target
+	h.UI.Canvas.Refresh()
+}
+
+
+func drawGrid(img *image.RGBA, size float64, view models.Viewport, c color.RGBA) {
+	step := size * view.Zoom
+	if step < 4 {
+		return
+	}
+
+	bounds := img.Bounds()
+	docMin, docMax := view.VisibleRect(float64(bounds.Dx()), float64(bounds.Dy()))
+	for gx := math.Ceil(docMin.X/size) * size; gx <= docMax.X; gx += size {
+		x := int(math.Round(view.ToScreen(models.Point{X: gx}).X))
+		for y := 0; y < bounds.Dy(); y++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+	for gy := math.Ceil(docMin.Y/size) * size; gy <= docMax.Y; gy += size {
+		y := int(math.Round(view.ToScreen(models.Point{Y: gy}).Y))
+		for x := 0; x < bounds.Dx(); x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+
+func drawSnapIndicator(canvas [][]color.Color, result models.SnapResult, c color.Color) {
+	if !result.Snapped {
+		return
+	}
+
+	x := int(math.Round(result.Point.X))
+	y := int(math.Round(result.Point.Y))
+	const r = 5
+
+	switch result.Kind {
+	case models.SnapVertex:
+		drawSelectionIndicator(canvas, result.Point.X, result.Point.Y, 2*r+1, c)
+	case models.SnapCenter:
+		algorithms.MidpointCircle(canvas, x, y, r, c)
+	case models.SnapMidpoint:
+		algorithms.MidpointLine(canvas, x-r, y+r, x, y-r, c)
+		algorithms.MidpointLine(canvas, x, y-r, x+r, y+r, c)
+		algorithms.MidpointLine(canvas, x+r, y+r, x-r, y+r, c)
+	case models.SnapIntersection:
+		algorithms.MidpointLine(canvas, x-r, y-r, x+r, y+r, c)
+		algorithms.MidpointLine(canvas, x-r, y+r, x+r, y-r, c)
+	default:
+		algorithms.MidpointLine(canvas, x-r, y, x+r, y, c)
+		algorithms.MidpointLine(canvas, x, y-r, x, y+r, c)
+	}
+}
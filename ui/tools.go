@@ -0,0 +1,190 @@
+package ui
+
+import (
+	"image/color"
+	"paint-drawer-pro/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+
+type Tool interface {
+	Label() string
+	Status() string
+	Activate(ui *MainUI)
+	Deactivate(ui *MainUI)
+	PointerDown(h *MouseHandler, ev *desktop.MouseEvent, p models.Point)
+	PointerMove(h *MouseHandler, p models.Point)
+	PointerUp(h *MouseHandler, p models.Point)
+	KeyDown(h *MouseHandler, ev *fyne.KeyEvent) bool
+	DrawOverlay(ui *MainUI, canvas [][]color.Color)
+}
+
+
+type SecondaryTapTool interface {
+	TappedSecondary(h *MouseHandler, ev *fyne.PointEvent)
+}
+
+
+type DoubleTapTool interface {
+	DoubleTapped(h *MouseHandler, p models.Point)
+}
+
+
+type ClickTool interface {
+	PlacesByClick() bool
+}
+
+
+type ConstrainingTool interface {
+	Constrain(origin, p models.Point) models.Point
+}
+
+
+type SnappingTool interface {
+	Snaps() bool
+}
+
+
+type PillLengthTool interface {
+	PillTarget(ui *MainUI) *models.Pill
+}
+
+
+type baseTool struct{}
+
+
+func (baseTool) Activate(ui *MainUI) {}
+
+
+func (baseTool) Deactivate(ui *MainUI) {}
+
+
+func (baseTool) PointerDown(h *MouseHandler, ev *desktop.MouseEvent, p models.Point) {}
+
+
+func (baseTool) PointerMove(h *MouseHandler, p models.Point) {
+	h.updateSnapHover()
+}
+
+
+func (baseTool) PointerUp(h *MouseHandler, p models.Point) {}
+
+
+func (baseTool) KeyDown(h *MouseHandler, ev *fyne.KeyEvent) bool {
+	return false
+}
+
+
+func (baseTool) DrawOverlay(ui *MainUI, canvas [][]color.Color) {}
+
+
+const (
+	ToolLine       = "line"
+	ToolCircle     = "circle"
+	ToolPolygon    = "polygon"
+	ToolRectangle  = "rectangle"
+	ToolPill       = "pill"
+	ToolSelect     = "select"
+	ToolLasso      = "lasso"
+	ToolVertexEdit = "vertexedit"
+	ToolClipping   = "clipping"
+)
+
+
+var (
+	toolNames     []string
+	toolFactories = map[string]func() Tool{}
+)
+
+
+func RegisterTool(name string, factory func() Tool) {
+	if _, exists := toolFactories[name]; !exists {
+		toolNames = append(toolNames, name)
+	}
+	toolFactories[name] = factory
+}
+
+
+func ToolNames() []string {
+	return append([]string(nil), toolNames...)
+}
+
+
+func newTools() map[string]Tool {
+	tools := make(map[string]Tool, len(toolFactories))
+	for name, factory := range toolFactories {
+		tools[name] = factory()
+	}
+	return tools
+}
+
+
+func (ui *MainUI) Tool() Tool {
+	if tool, ok := ui.Tools[ui.State.CurrentAction]; ok {
+		return tool
+	}
+	return idleTool{}
+}
+
+
+func (ui *MainUI) toolButton(name string) *widget.Button {
+	label := name
+	if tool, ok := ui.Tools[name]; ok {
+		label = tool.Label()
+	}
+	return widget.NewButton(label, func() {
+		ui.Editor.SetTool(name)
+	})
+}
+
+
+func (ui *MainUI) switchTool() {
+	if ui.activeTool != nil {
+		ui.activeTool.Deactivate(ui)
+	}
+	tool := ui.Tool()
+	ui.activeTool = tool
+	tool.Activate(ui)
+
+	ui.CurrentToolText.SetText("Current tool: " + tool.Label())
+	if status := tool.Status(); status != "" {
+		ui.StatusLabel.SetText(status)
+	}
+}
+
+
+func isDrawingTool(tool Tool) bool {
+	snapping, ok := tool.(SnappingTool)
+	return ok && snapping.Snaps()
+}
+
+
+type idleTool struct {
+	baseTool
+}
+
+
+func (idleTool) Label() string {
+	return "None"
+}
+
+
+func (idleTool) Status() string {
+	return ""
+}
+
+
+func init() {
+	RegisterTool(ToolLine, func() Tool { return &lineTool{} })
+	RegisterTool(ToolCircle, func() Tool { return &circleTool{} })
+	RegisterTool(ToolPolygon, func() Tool { return &polygonTool{} })
+	RegisterTool(ToolRectangle, func() Tool { return &rectangleTool{} })
+	RegisterTool(ToolPill, func() Tool { return &pillTool{} })
+	RegisterTool(ToolSelect, func() Tool { return &selectTool{} })
+	RegisterTool(ToolLasso, func() Tool { return &lassoTool{} })
+	RegisterTool(ToolVertexEdit, func() Tool { return &vertexEditTool{} })
+	RegisterTool(ToolClipping, func() Tool { return &clippingTool{} })
+}
@@ -0,0 +1,265 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"paint-drawer-pro/algorithms"
+	"paint-drawer-pro/models"
+	"strconv"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+
+type TransformHandle int
+
+
+const (
+	NoTransformHandle TransformHandle = iota
+	ScaleTopLeftHandle
+	ScaleTopRightHandle
+	ScaleBottomRightHandle
+	ScaleBottomLeftHandle
+	RotateHandle
+)
+
+
+const (
+	transformBoxPadding   = 12
+	rotateHandleDistance  = 25
+	transformHandleRadius = 7
+)
+
+
+func transformBox(shape models.Shape) (models.Point, models.Point) {
+	minP, maxP := models.ShapeBounds(shape)
+	return models.Point{X: minP.X - transformBoxPadding, Y: minP.Y - transformBoxPadding},
+		models.Point{X: maxP.X + transformBoxPadding, Y: maxP.Y + transformBoxPadding}
+}
+
+
+func transformHandlePositions(minP, maxP models.Point) map[TransformHandle]models.Point {
+	return map[TransformHandle]models.Point{
+		ScaleTopLeftHandle:     minP,
+		ScaleTopRightHandle:    {X: maxP.X, Y: minP.Y},
+		ScaleBottomRightHandle: maxP,
+		ScaleBottomLeftHandle:  {X: minP.X, Y: maxP.Y},
+		RotateHandle:           {X: (minP.X + maxP.X) / 2, Y: minP.Y - rotateHandleDistance},
+	}
+}
+
+
+func hitTransformHandle(shape models.Shape, p models.Point) TransformHandle {
+	minP, maxP := transformBox(shape)
+	for handle, pos := range transformHandlePositions(minP, maxP) {
+		dx := pos.X - p.X
+		dy := pos.Y - p.Y
+		if dx*dx+dy*dy <= transformHandleRadius*transformHandleRadius {
+			return handle
+		}
+	}
+	return NoTransformHandle
+}
+
+
+func transformForHandle(handle TransformHandle, minP, maxP, start, current models.Point) models.Matrix {
+	if handle == RotateHandle {
+		center := models.Point{X: (minP.X + maxP.X) / 2, Y: (minP.Y + maxP.Y) / 2}
+		startAngle := math.Atan2(start.Y-center.Y, start.X-center.X)
+		currentAngle := math.Atan2(current.Y-center.Y, current.X-center.X)
+		return models.RotateMatrix(currentAngle - startAngle).About(center)
+	}
+
+	var pivot models.Point
+	switch handle {
+	case ScaleTopLeftHandle:
+		pivot = maxP
+	case ScaleTopRightHandle:
+		pivot = models.Point{X: minP.X, Y: maxP.Y}
+	case ScaleBottomRightHandle:
+		pivot = minP
+	case ScaleBottomLeftHandle:
+		pivot = models.Point{X: maxP.X, Y: minP.Y}
+	default:
+		return models.IdentityMatrix()
+	}
+
+	sx, sy := 1.0, 1.0
+	if start.X != pivot.X {
+		sx = (current.X - pivot.X) / (start.X - pivot.X)
+	}
+	if start.Y != pivot.Y {
+		sy = (current.Y - pivot.Y) / (start.Y - pivot.Y)
+	}
+	if math.Abs(sx) < 0.01 {
+		sx = math.Copysign(0.01, sx)
+	}
+	if math.Abs(sy) < 0.01 {
+		sy = math.Copysign(0.01, sy)
+	}
+	return models.ScaleMatrix(sx, sy).About(pivot)
+}
+
+
+func drawTransformBox(canvas [][]color.Color, shape models.Shape, c color.Color) {
+	minP, maxP := transformBox(shape)
+	topRight := models.Point{X: maxP.X, Y: minP.Y}
+	bottomLeft := models.Point{X: minP.X, Y: maxP.Y}
+
+	drawDashedLine(canvas, minP, topRight, c)
+	drawDashedLine(canvas, topRight, maxP, c)
+	drawDashedLine(canvas, maxP, bottomLeft, c)
+	drawDashedLine(canvas, bottomLeft, minP, c)
+
+	handles := transformHandlePositions(minP, maxP)
+	for handle, pos := range handles {
+		if handle == RotateHandle {
+			algorithms.MidpointLine(canvas, int(pos.X), int(minP.Y), int(pos.X), int(pos.Y)+5, c)
+			algorithms.MidpointCircle(canvas, int(pos.X), int(pos.Y), 5, c)
+			continue
+		}
+		drawSelectionIndicator(canvas, pos.X, pos.Y, 7, c)
+	}
+}
+
+
+func drawDashedLine(canvas [][]color.Color, start, end models.Point, c color.Color) {
+	dx := end.X - start.X
+	dy := end.Y - start.Y
+	length := math.Sqrt(dx*dx + dy*dy)
+	if length == 0 {
+		return
+	}
+
+	const dash = 4.0
+	for t := 0.0; t < length; t += 2 * dash {
+		t2 := math.Min(t+dash, length)
+		algorithms.MidpointLine(canvas,
+			int(start.X+dx*t/length), int(start.Y+dy*t/length),
+			int(start.X+dx*t2/length), int(start.Y+dy*t2/length), c)
+	}
+}
+
+
+type transformGesture struct {
+	handle TransformHandle
+	base   models.Shape
+	index  int
+	boxMin models.Point
+	boxMax models.Point
+}
+
+
+func (g *transformGesture) begin(h *MouseHandler, handle TransformHandle) bool {
+	selected := h.UI.State.SelectedShape
+	g.index = models.IndexOfShape(h.UI.State.Shapes, selected)
+	if g.index < 0 {
+		return false
+	}
+
+	g.handle = handle
+	g.base = selected.Clone()
+	g.boxMin, g.boxMax = transformBox(selected)
+
+	if handle == RotateHandle {
+		h.UI.StatusLabel.SetText("Rotating shape...")
+	} else {
+		h.UI.StatusLabel.SetText("Scaling shape...")
+	}
+	return true
+}
+
+
+func (g *transformGesture) update(h *MouseHandler, p models.Point) {
+	if g.index < 0 || g.index >= len(h.UI.State.Shapes) {
+		return
+	}
+
+	m := transformForHandle(g.handle, g.boxMin, g.boxMax, h.StartPoint, p)
+	transformed := g.base.Clone().Transform(m)
+
+	h.UI.State.Shapes[g.index] = transformed
+	h.UI.Editor.Select(transformed)
+}
+
+
+func (g *transformGesture) end(h *MouseHandler) {
+	h.UI.Editor.RecordEdit(g.index, g.base, "Transform shape")
+	g.handle = NoTransformHandle
+	g.base = nil
+	h.UI.StatusLabel.SetText("Shape transformed.")
+	h.UI.Canvas.Refresh()
+}
+
+
+func (ui *MainUI) applyTransform(m models.Matrix) {
+	if ui.State.SelectedShape == nil {
+		return
+	}
+	transformed := ui.State.SelectedShape.Clone().Transform(m)
+	ui.Editor.ReplaceShape(ui.State.SelectedShape, transformed, "Transform shape")
+}
+
+
+func (ui *MainUI) showTransformDialog() {
+	if ui.State.SelectedShape == nil {
+		dialog.ShowInformation("Transform", "Please select a shape to transform first.", ui.Window)
+		return
+	}
+
+	translateX := widget.NewEntry()
+	translateX.SetText("0")
+	translateY := widget.NewEntry()
+	translateY.SetText("0")
+	rotate := widget.NewEntry()
+	rotate.SetText("0")
+	scaleX := widget.NewEntry()
+	scaleX.SetText("1")
+	scaleY := widget.NewEntry()
+	scaleY.SetText("1")
+	skewX := widget.NewEntry()
+	skewX.SetText("0")
+	skewY := widget.NewEntry()
+	skewY.SetText("0")
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Translate X", translateX),
+		widget.NewFormItem("Translate Y", translateY),
+		widget.NewFormItem("Rotate (degrees)", rotate),
+		widget.NewFormItem("Scale X", scaleX),
+		widget.NewFormItem("Scale Y", scaleY),
+		widget.NewFormItem("Skew X (degrees)", skewX),
+		widget.NewFormItem("Skew Y (degrees)", skewY),
+	}
+
+	dialog.ShowForm("Transform Shape", "Apply", "Cancel", items, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+
+		values := make([]float64, 0, 7)
+		for _, item := range items {
+			entry := item.Widget.(*widget.Entry)
+			value, err := strconv.ParseFloat(entry.Text, 64)
+			if err != nil {
+				dialog.ShowError(fmt.Errorf("invalid value for %s: %v", item.Text, err), ui.Window)
+				return
+			}
+			values = append(values, value)
+		}
+
+		toRadians := math.Pi / 180
+		center := models.ShapeCenter(ui.State.SelectedShape)
+		m := models.TranslateMatrix(values[0], values[1]).Multiply(
+			models.RotateMatrix(values[2] * toRadians).
+				Multiply(models.SkewMatrix(values[5]*toRadians, values[6]*toRadians)).
+				Multiply(models.ScaleMatrix(values[3], values[4])).
+				About(center),
+		)
+
+		ui.applyTransform(m)
+		ui.StatusLabel.SetText("Transform applied")
+	}, ui.Window)
+}
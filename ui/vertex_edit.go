@@ -0,0 +1,210 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"paint-drawer-pro/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+)
+
+
+func (t *vertexEditTool) beginDrag(h *MouseHandler, p models.Point) {
+	if poly, ok := h.UI.State.SelectedShape.(*models.Polygon); ok {
+		if handle, found := h.UI.handleAt(poly, h.ScreenPoint); found {
+			t.gesture = vertexDragging
+			t.edit.begin(h.UI, poly)
+			h.UI.Editor.SelectVertex(handle.ID)
+			h.UI.StatusLabel.SetText(fmt.Sprintf("Vertex %d selected. Drag to move, press Delete to remove.", handle.ID))
+			return
+		}
+
+		if edge, _, found := poly.EdgeAt(p); found {
+			t.gesture = edgeDragging
+			t.edge = edge
+			t.last = p
+			t.edit.begin(h.UI, poly)
+			h.UI.Editor.SelectVertex(-1)
+			h.UI.StatusLabel.SetText("Dragging edge...")
+			return
+		}
+	}
+
+	shapes := h.UI.State.SelectableShapes()
+	for i := len(shapes) - 1; i >= 0; i-- {
+		if poly, ok := shapes[i].(*models.Polygon); ok && poly.Contains(p) {
+			h.UI.Editor.Select(poly)
+			h.UI.StatusLabel.SetText("Polygon selected. Drag vertices or edges, double-click an edge to insert a vertex.")
+			return
+		}
+	}
+
+	h.UI.Editor.ClearSelection()
+	h.UI.StatusLabel.SetText("No polygon selected.")
+}
+
+
+func (t *vertexEditTool) deleteVertex(h *MouseHandler, ev *fyne.KeyEvent) bool {
+	if ev.Name != fyne.KeyDelete && ev.Name != fyne.KeyBackspace {
+		return false
+	}
+
+	poly, ok := h.UI.State.SelectedShape.(*models.Polygon)
+	if !ok || h.UI.State.SelectedVertex < 0 {
+		return false
+	}
+
+	removed := false
+	h.UI.Editor.EditShape(poly, "Delete vertex", func() {
+		removed = poly.RemoveVertex(h.UI.State.SelectedVertex)
+	})
+	if !removed {
+		h.UI.StatusLabel.SetText("A polygon needs at least 3 vertices.")
+		return true
+	}
+
+	h.UI.Editor.SelectVertex(-1)
+	h.UI.StatusLabel.SetText("Vertex removed.")
+	return true
+}
+
+
+func (t *vertexEditTool) insertVertexAt(h *MouseHandler, p models.Point) {
+	poly, ok := h.UI.State.SelectedShape.(*models.Polygon)
+	if !ok {
+		return
+	}
+
+	edge, nearest, found := poly.EdgeAt(p)
+	if !found {
+		return
+	}
+
+	t.gesture = vertexIdle
+	inserted := -1
+	h.UI.Editor.EditShape(poly, "Insert vertex", func() {
+		inserted = poly.InsertVertex(edge, nearest)
+	})
+	h.UI.Editor.SelectVertex(inserted)
+	h.UI.StatusLabel.SetText(fmt.Sprintf("Vertex inserted (%d vertices).", len(poly.Vertices)))
+}
+
+
+type vertexGesture int
+
+const (
+	vertexIdle vertexGesture = iota
+	vertexDragging
+	edgeDragging
+)
+
+
+type vertexEditTool struct {
+	baseTool
+	gesture vertexGesture
+	edge    int
+	last    models.Point
+	edit    editSession
+}
+
+
+func (t *vertexEditTool) Label() string {
+	return "Edit Vertices"
+}
+
+
+func (t *vertexEditTool) Status() string {
+	return "Vertex edit: click a polygon, drag vertices or edges, double-click an edge to insert, Delete removes a vertex"
+}
+
+
+func (t *vertexEditTool) Activate(ui *MainUI) {
+	t.gesture = vertexIdle
+	if poly, isPolygon := ui.State.SelectedShape.(*models.Polygon); isPolygon {
+		ui.Editor.Select(poly)
+	} else {
+		ui.Editor.ClearSelection()
+	}
+}
+
+
+func (t *vertexEditTool) PointerDown(h *MouseHandler, ev *desktop.MouseEvent, p models.Point) {
+	t.gesture = vertexIdle
+	if ev.Button == desktop.MouseButtonPrimary {
+		t.beginDrag(h, p)
+	}
+}
+
+
+func (t *vertexEditTool) PointerMove(h *MouseHandler, p models.Point) {
+	poly, ok := h.UI.State.SelectedShape.(*models.Polygon)
+	if !ok || t.gesture == vertexIdle {
+		t.baseTool.PointerMove(h, p)
+		return
+	}
+
+	switch t.gesture {
+	case vertexDragging:
+		poly.MoveHandle(h.UI.State.SelectedVertex, h.snapPoint(p, poly))
+		h.UI.Canvas.Refresh()
+	case edgeDragging:
+		deltaX := p.X - t.last.X
+		deltaY := p.Y - t.last.Y
+		if deltaX != 0 || deltaY != 0 {
+			poly.MoveEdge(t.edge, deltaX, deltaY)
+			t.last = p
+			h.UI.Canvas.Refresh()
+		}
+	}
+}
+
+
+func (t *vertexEditTool) PointerUp(h *MouseHandler, p models.Point) {
+	gesture := t.gesture
+	t.gesture = vertexIdle
+
+	switch gesture {
+	case edgeDragging:
+		t.edit.end(h.UI, "Move edge")
+		h.UI.StatusLabel.SetText("Edge moved.")
+	case vertexDragging:
+		t.edit.end(h.UI, "Move vertex")
+		h.UI.StatusLabel.SetText("Vertex moved.")
+	default:
+		return
+	}
+	h.UI.Canvas.Refresh()
+}
+
+
+func (t *vertexEditTool) KeyDown(h *MouseHandler, ev *fyne.KeyEvent) bool {
+	return t.deleteVertex(h, ev)
+}
+
+
+func (t *vertexEditTool) DoubleTapped(h *MouseHandler, p models.Point) {
+	t.insertVertexAt(h, p)
+}
+
+
+func (t *vertexEditTool) DrawOverlay(ui *MainUI, canvas [][]color.Color) {
+	poly, ok := ui.State.SelectedShape.(*models.Polygon)
+	if !ok {
+		return
+	}
+	poly, _ = ui.viewShape(poly).(*models.Polygon)
+	drawVertexEditOverlay(canvas, poly, ui.State.SelectedVertex, selectionColor, color.RGBA{255, 80, 0, 255})
+}
+
+
+func drawVertexEditOverlay(canvas [][]color.Color, poly *models.Polygon, selectedVertex int, c, selectedColor color.Color) {
+	for i, v := range poly.Vertices {
+		if i == selectedVertex {
+			drawSelectionIndicator(canvas, v.X, v.Y, 10, selectedColor)
+			drawSelectionIndicator(canvas, v.X, v.Y, 8, selectedColor)
+		} else {
+			drawSelectionIndicator(canvas, v.X, v.Y, 6, c)
+		}
+	}
+}
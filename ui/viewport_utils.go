@@ -0,0 +1,193 @@
+package ui
+
+import (
+	"fmt"
+	"math"
+	"paint-drawer-pro/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+
+const (
+	zoomStep       = 1.25
+	fitMargin      = 20
+	scrollZoomBase = 1.1
+)
+
+
+func (ui *MainUI) viewShape(shape models.Shape) models.Shape {
+	return ui.Viewport.ViewShape(shape)
+}
+
+
+func (ui *MainUI) viewShapes(shapes []models.Shape) []models.Shape {
+	views := make([]models.Shape, len(shapes))
+	for i, shape := range shapes {
+		views[i] = ui.viewShape(shape)
+	}
+	return views
+}
+
+
+func (ui *MainUI) viewPoints(points []models.Point) []models.Point {
+	views := make([]models.Point, len(points))
+	for i, p := range points {
+		views[i] = ui.Viewport.ToScreen(p)
+	}
+	return views
+}
+
+
+func (ui *MainUI) handleAt(shape models.Shape, screen models.Point) (models.Handle, bool) {
+	editable, ok := ui.viewShape(shape).(models.Editable)
+	if !ok {
+		return models.Handle{}, false
+	}
+	return models.HandleAt(editable, screen)
+}
+
+
+func (ui *MainUI) canvasSize() (float64, float64) {
+	size := ui.Canvas.Size()
+	return float64(size.Width), float64(size.Height)
+}
+
+
+func (ui *MainUI) setZoom(screen models.Point, zoom float64) {
+	if ui.PixelInspect {
+		zoom = math.Max(zoom, pixelGridMinZoom)
+	}
+	ui.Viewport.ZoomAt(screen, zoom)
+	ui.updateZoomLabel()
+	ui.Canvas.Refresh()
+}
+
+
+func (ui *MainUI) zoomBy(factor float64) {
+	w, h := ui.canvasSize()
+	ui.setZoom(models.Point{X: w / 2, Y: h / 2}, ui.Viewport.Zoom*factor)
+}
+
+
+func (ui *MainUI) resetZoom() {
+	ui.Viewport = models.NewViewport()
+	ui.keepInspectZoom()
+	ui.updateZoomLabel()
+	ui.Canvas.Refresh()
+}
+
+
+func (ui *MainUI) fitToContent() {
+	shapes := ui.State.VisibleShapes()
+	if len(shapes) == 0 {
+		ui.resetZoom()
+		return
+	}
+
+	content := models.DrawingState{Shapes: shapes, Selection: shapes}
+	minP, maxP, _ := content.SelectionBounds()
+	w, h := ui.canvasSize()
+	ui.Viewport.Fit(minP, maxP, w, h, fitMargin)
+	ui.keepInspectZoom()
+	ui.updateZoomLabel()
+	ui.Canvas.Refresh()
+}
+
+
+func (ui *MainUI) keepInspectZoom() {
+	if ui.PixelInspect && ui.Viewport.Zoom < pixelGridMinZoom {
+		w, h := ui.canvasSize()
+		ui.Viewport.ZoomAt(models.Point{X: w / 2, Y: h / 2}, pixelGridMinZoom)
+	}
+}
+
+
+func (ui *MainUI) updateZoomLabel() {
+	if ui.ZoomLabel != nil {
+		ui.ZoomLabel.SetText(fmt.Sprintf("%d%%", int(math.Round(ui.Viewport.Zoom*100))))
+	}
+}
+
+
+func (ui *MainUI) buildZoomControls() *fyne.Container {
+	ui.ZoomLabel = widget.NewLabel("")
+	ui.updateZoomLabel()
+
+	zoomOutBtn := widget.NewButton("-", func() {
+		ui.zoomBy(1 / zoomStep)
+	})
+	zoomInBtn := widget.NewButton("+", func() {
+		ui.zoomBy(zoomStep)
+	})
+	actualSizeBtn := widget.NewButton("100%", func() {
+		ui.resetZoom()
+	})
+	fitBtn := widget.NewButton("Fit", func() {
+		ui.fitToContent()
+	})
+
+	return container.NewHBox(widget.NewLabel("Zoom:"), zoomOutBtn, ui.ZoomLabel, zoomInBtn, actualSizeBtn, fitBtn)
+}
+
+
+func (ui *MainUI) registerViewportShortcuts() {
+	shortcuts := map[*desktop.CustomShortcut]func(){
+		{KeyName: fyne.KeyEqual, Modifier: fyne.KeyModifierShortcutDefault}: func() { ui.zoomBy(zoomStep) },
+		{KeyName: fyne.KeyMinus, Modifier: fyne.KeyModifierShortcutDefault}: func() { ui.zoomBy(1 / zoomStep) },
+		{KeyName: fyne.Key0, Modifier: fyne.KeyModifierShortcutDefault}:     ui.resetZoom,
+		{KeyName: fyne.Key1, Modifier: fyne.KeyModifierShortcutDefault}:     ui.fitToContent,
+	}
+	for shortcut, action := range shortcuts {
+		action := action
+		ui.Window.Canvas().AddShortcut(shortcut, func(fyne.Shortcut) { action() })
+	}
+}
+
+
+func (h *MouseHandler) screenPosition(ev fyne.PointEvent) models.Point {
+	canvasPos := h.UI.Canvas.Position()
+	w, hgt := h.UI.canvasSize()
+
+	x := math.Max(0, math.Min(float64(ev.Position.X-canvasPos.X), w-1))
+	y := math.Max(0, math.Min(float64(ev.Position.Y-canvasPos.Y), hgt-1))
+	return models.Point{X: x, Y: y}
+}
+
+
+func (h *MouseHandler) Scrolled(ev *fyne.ScrollEvent) {
+	if ev.Scrolled.DY == 0 {
+		return
+	}
+	factor := math.Pow(scrollZoomBase, float64(ev.Scrolled.DY)/10)
+	h.UI.setZoom(h.screenPosition(ev.PointEvent), h.UI.Viewport.Zoom*factor)
+}
+
+
+func (h *MouseHandler) beginPan() {
+	h.IsPanning = true
+	h.PanLast = h.ScreenPoint
+	h.SuppressTap = true
+	h.UI.StatusLabel.SetText("Panning...")
+}
+
+
+func (h *MouseHandler) updatePan() {
+	dx := h.ScreenPoint.X - h.PanLast.X
+	dy := h.ScreenPoint.Y - h.PanLast.Y
+	if dx == 0 && dy == 0 {
+		return
+	}
+	h.UI.Viewport.Pan(dx, dy)
+	h.PanLast = h.ScreenPoint
+	h.UI.Canvas.Refresh()
+}
+
+
+func (h *MouseHandler) endPan() {
+	h.IsPanning = false
+	h.UI.StatusLabel.SetText("Ready")
+}
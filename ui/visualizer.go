@@ -0,0 +1,308 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+	"paint-drawer-pro/algorithms"
+	"paint-drawer-pro/models"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+
+var visualizerAlgorithms = []string{"MidpointLine", "MidpointCircle", "EdgeTableFill", "SutherlandHodgman"}
+
+
+type AlgorithmVisualizer struct {
+	Algorithm   string
+	Trace       *algorithms.Trace
+	Step        int
+	Playing     bool
+	StepLabel   *widget.Label
+	StateLabel  *widget.Label
+	PlayBtn     *widget.Button
+	SpeedSlider *widget.Slider
+	stop        chan struct{}
+}
+
+
+func (ui *MainUI) buildVisualizerPanel() fyne.CanvasObject {
+	v := &AlgorithmVisualizer{Algorithm: visualizerAlgorithms[0]}
+	ui.Visualizer = v
+
+	algorithmSelect := widget.NewSelect(visualizerAlgorithms, func(selected string) {
+		v.Algorithm = selected
+	})
+	algorithmSelect.SetSelected(v.Algorithm)
+
+	traceBtn := widget.NewButton("Trace Selection", func() {
+		ui.startTrace()
+	})
+	backBtn := widget.NewButton("Back", func() {
+		ui.stopPlayback()
+		ui.stepTrace(-1)
+	})
+	v.PlayBtn = widget.NewButton("Play", func() {
+		ui.togglePlayback()
+	})
+	stepBtn := widget.NewButton("Step", func() {
+		ui.stopPlayback()
+		ui.stepTrace(1)
+	})
+	clearBtn := widget.NewButton("Clear", func() {
+		ui.clearTrace()
+	})
+
+	v.SpeedSlider = widget.NewSlider(1, 60)
+	v.SpeedSlider.SetValue(10)
+	v.StepLabel = widget.NewLabel("No trace")
+	v.StateLabel = widget.NewLabel("Select a shape and press Trace Selection.\nSutherlandHodgman clips the other selected polygon against the primary one.")
+	v.StateLabel.Wrapping = fyne.TextWrapWord
+
+	controls := container.NewVBox(
+		widget.NewLabel("Algorithm:"),
+		algorithmSelect,
+		traceBtn,
+		container.NewGridWithColumns(4, backBtn, v.PlayBtn, stepBtn, clearBtn),
+		container.NewBorder(nil, nil, widget.NewLabel("Steps/s:"), nil, v.SpeedSlider),
+		v.StepLabel,
+		widget.NewSeparator(),
+	)
+
+	return container.NewBorder(controls, nil, nil, nil, container.NewVScroll(v.StateLabel))
+}
+
+
+func (ui *MainUI) buildTrace(algorithm string) (*algorithms.Trace, error) {
+	selected := ui.State.SelectedShape
+	switch algorithm {
+	case "MidpointLine":
+		line, ok := selected.(*models.Line)
+		if !ok {
+			return nil, fmt.Errorf("select a line to trace MidpointLine")
+		}
+		return algorithms.TraceMidpointLine(roundInt(line.Start.X), roundInt(line.Start.Y), roundInt(line.End.X), roundInt(line.End.Y)), nil
+
+	case "MidpointCircle":
+		circle, ok := selected.(*models.Circle)
+		if !ok {
+			return nil, fmt.Errorf("select a circle to trace MidpointCircle")
+		}
+		return algorithms.TraceMidpointCircle(roundInt(circle.Center.X), roundInt(circle.Center.Y), roundInt(circle.Radius)), nil
+
+	case "EdgeTableFill":
+		vertices, ok := polygonVertices(selected)
+		if !ok {
+			return nil, fmt.Errorf("select a polygon or rectangle to trace EdgeTableFill")
+		}
+		return algorithms.TraceEdgeTableFill(toAlgorithmPoints(vertices)), nil
+
+	case "SutherlandHodgman":
+		clip, ok := polygonVertices(selected)
+		var subject []models.Point
+		for _, shape := range ui.State.SelectedShapes() {
+			if shape != selected {
+				subject, _ = polygonVertices(shape)
+			}
+		}
+		if !ok || len(subject) < 3 {
+			return nil, fmt.Errorf("select two polygons; the primary selection is used as the clipper")
+		}
+		return algorithms.TraceSutherlandHodgman(
+			algorithms.SimplifyPolygon(toAlgorithmPoints(subject), 2.0),
+			algorithms.SimplifyPolygon(toAlgorithmPoints(clip), 2.0),
+		), nil
+	}
+	return nil, fmt.Errorf("unknown algorithm %q", algorithm)
+}
+
+
+func (ui *MainUI) startTrace() {
+	ui.stopPlayback()
+	trace, err := ui.buildTrace(ui.Visualizer.Algorithm)
+	if err != nil {
+		dialog.ShowInformation("Visualizer", err.Error(), ui.Window)
+		return
+	}
+	if len(trace.Steps) == 0 {
+		dialog.ShowInformation("Visualizer", "The algorithm produced no steps for this shape.", ui.Window)
+		return
+	}
+
+	ui.Visualizer.Trace = trace
+	ui.Visualizer.Step = 0
+	ui.updateTraceView()
+}
+
+
+func (ui *MainUI) stepTrace(delta int) bool {
+	v := ui.Visualizer
+	if v.Trace == nil {
+		return false
+	}
+	next := v.Step + delta
+	if next < 0 || next >= len(v.Trace.Steps) {
+		return false
+	}
+	v.Step = next
+	ui.updateTraceView()
+	return true
+}
+
+
+func (ui *MainUI) togglePlayback() {
+	v := ui.Visualizer
+	if v.Playing {
+		ui.stopPlayback()
+		return
+	}
+	if v.Trace == nil {
+		return
+	}
+	if v.Step >= len(v.Trace.Steps)-1 {
+		v.Step = 0
+		ui.updateTraceView()
+	}
+
+	v.Playing = true
+	v.PlayBtn.SetText("Pause")
+	stop := make(chan struct{})
+	v.stop = stop
+	interval := time.Duration(float64(time.Second) / v.SpeedSlider.Value)
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				fyne.Do(func() {
+					if v.stop == stop && !ui.stepTrace(1) {
+						ui.stopPlayback()
+					}
+				})
+			}
+		}
+	}()
+}
+
+
+func (ui *MainUI) stopPlayback() {
+	v := ui.Visualizer
+	if v == nil || !v.Playing {
+		return
+	}
+	close(v.stop)
+	v.stop = nil
+	v.Playing = false
+	v.PlayBtn.SetText("Play")
+}
+
+
+func (ui *MainUI) clearTrace() {
+	ui.stopPlayback()
+	ui.Visualizer.Trace = nil
+	ui.Visualizer.Step = 0
+	ui.updateTraceView()
+}
+
+
+func (ui *MainUI) updateTraceView() {
+	v := ui.Visualizer
+	if v.Trace == nil {
+		v.StepLabel.SetText("No trace")
+		v.StateLabel.SetText("")
+		ui.Canvas.Refresh()
+		return
+	}
+
+	step := v.Trace.Steps[v.Step]
+	v.StepLabel.SetText(fmt.Sprintf("%s: step %d / %d", v.Trace.Algorithm, v.Step+1, len(v.Trace.Steps)))
+
+	var text strings.Builder
+	text.WriteString(step.Description)
+	text.WriteString("\n")
+	for _, tv := range step.Vars {
+		fmt.Fprintf(&text, "\n%s = %s", tv.Name, tv.Value)
+	}
+	v.StateLabel.SetText(text.String())
+	ui.Canvas.Refresh()
+}
+
+
+func polygonVertices(shape models.Shape) ([]models.Point, bool) {
+	switch s := shape.(type) {
+	case *models.Polygon:
+		return s.GetVertices(), true
+	case *models.Rectangle:
+		return s.GetVertices(), true
+	}
+	return nil, false
+}
+
+
+func toAlgorithmPoints(points []models.Point) []algorithms.Point {
+	result := make([]algorithms.Point, len(points))
+	for i, p := range points {
+		result[i] = algorithms.Point{X: p.X, Y: p.Y}
+	}
+	return result
+}
+
+
+func roundInt(v float64) int {
+	return int(math.Round(v))
+}
+
+
+func drawTraceOverlay(canvas [][]color.Color, trace *algorithms.Trace, current int, view models.Viewport) {
+	visited := color.RGBA{255, 170, 0, 255}
+	active := color.RGBA{220, 0, 0, 255}
+
+	for i := 0; i <= current && i < len(trace.Steps); i++ {
+		c := visited
+		if i == current {
+			c = active
+		}
+		for _, p := range trace.Steps[i].Pixels {
+			fillDocPixel(canvas, p, view, c)
+		}
+	}
+
+	step := trace.Steps[current]
+	if len(step.Polygon) > 1 {
+		for i := range step.Polygon {
+			a := view.ToScreen(models.Point{X: step.Polygon[i].X, Y: step.Polygon[i].Y})
+			b := view.ToScreen(models.Point{X: step.Polygon[(i+1)%len(step.Polygon)].X, Y: step.Polygon[(i+1)%len(step.Polygon)].Y})
+			algorithms.MidpointLine(canvas, roundInt(a.X), roundInt(a.Y), roundInt(b.X), roundInt(b.Y), active)
+		}
+	}
+	if len(step.Edge) == 2 {
+		a := view.ToScreen(models.Point{X: step.Edge[0].X, Y: step.Edge[0].Y})
+		b := view.ToScreen(models.Point{X: step.Edge[1].X, Y: step.Edge[1].Y})
+		drawDashedLine(canvas, a, b, color.RGBA{0, 90, 255, 255})
+	}
+}
+
+
+func fillDocPixel(canvas [][]color.Color, p algorithms.Pixel, view models.Viewport, c color.Color) {
+	topLeft := view.ToScreen(models.Point{X: float64(p.X), Y: float64(p.Y)})
+	bottomRight := view.ToScreen(models.Point{X: float64(p.X + 1), Y: float64(p.Y + 1)})
+
+	x0, y0 := int(math.Floor(topLeft.X)), int(math.Floor(topLeft.Y))
+	x1 := max(x0, int(math.Floor(bottomRight.X))-1)
+	y1 := max(y0, int(math.Floor(bottomRight.Y))-1)
+	for y := y0; y <= y1; y++ {
+		for x := x0; x <= x1; x++ {
+			algorithms.SetPixel(canvas, x, y, c)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"paint-drawer-pro/models"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/widget"
+)
+
+
+func (ui *MainUI) applyZOrder(op models.ZOrderOp) {
+	selected := ui.State.SelectedShapes()
+	if len(selected) == 0 {
+		ui.StatusLabel.SetText("Select one or more shapes to change their stacking order.")
+		return
+	}
+
+	order := ui.State.ZOrder(selected, op)
+	if models.IsIdentityOrder(order) {
+		ui.StatusLabel.SetText("Stacking order unchanged.")
+		return
+	}
+
+	ui.Editor.Execute(&models.ReorderShapesCommand{Order: order, Label: op.String()})
+	ui.StatusLabel.SetText(op.String())
+}
+
+
+func (ui *MainUI) registerZOrderShortcuts() {
+	shortcuts := map[*desktop.CustomShortcut]models.ZOrderOp{
+		{KeyName: fyne.KeyRightBracket, Modifier: fyne.KeyModifierShortcutDefault}:                         models.BringForward,
+		{KeyName: fyne.KeyLeftBracket, Modifier: fyne.KeyModifierShortcutDefault}:                          models.SendBackward,
+		{KeyName: fyne.KeyRightBracket, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}: models.BringToFront,
+		{KeyName: fyne.KeyLeftBracket, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}:  models.SendToBack,
+	}
+	for shortcut, op := range shortcuts {
+		op := op
+		ui.Window.Canvas().AddShortcut(shortcut, func(fyne.Shortcut) { ui.applyZOrder(op) })
+	}
+}
+
+
+func (ui *MainUI) showShapeContextMenu(pos fyne.Position, point models.Point) {
+	menu := fyne.NewMenu("",
+		fyne.NewMenuItem("Copy", func() { ui.copySelection() }),
+		fyne.NewMenuItem("Cut", ui.cutSelection),
+		fyne.NewMenuItem("Paste Here", func() { ui.paste(&point) }),
+		fyne.NewMenuItem("Duplicate", ui.cloneSelection),
+		fyne.NewMenuItem("Copy as PNG", ui.copySelectionAsPNG),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Bring to Front", func() { ui.applyZOrder(models.BringToFront) }),
+		fyne.NewMenuItem("Bring Forward", func() { ui.applyZOrder(models.BringForward) }),
+		fyne.NewMenuItem("Send Backward", func() { ui.applyZOrder(models.SendBackward) }),
+		fyne.NewMenuItem("Send to Back", func() { ui.applyZOrder(models.SendToBack) }),
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Group", func() { ui.groupSelection() }),
+		fyne.NewMenuItem("Ungroup", func() { ui.ungroupSelection() }),
+		fyne.NewMenuItem("Delete", func() { ui.deleteSelection() }),
+	)
+	widget.ShowPopUpMenuAtPosition(menu, ui.Window.Canvas(), pos)
+}